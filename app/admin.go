@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/app/storage"
+	"darkness8129/news-api/config"
+	"darkness8129/news-api/packages/logging"
+	"strings"
+)
+
+// PromoteAdmin gives the admin role to the registered user with the email, so the first admin can be appointed.
+// Anyone can register with any email, so the operator must make sure the account belongs to the right person.
+func PromoteAdmin(cfg *config.Config, logger logging.Logger, email string) {
+	logger = logger.Named("PromoteAdmin")
+
+	sql, db := connectDB(cfg, logger)
+	defer func() {
+		err := sql.Close()
+		if err != nil {
+			logger.Error("failed to close db connection", "err", err)
+		}
+	}()
+
+	storages := storage.NewStorages(db, logger)
+	ctx := service.SystemContext(context.Background())
+
+	user, err := storages.User.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		logger.Fatal("failed to get user", "err", err, "email", email)
+	}
+
+	_, err = service.NewUserService(storages, logger).SetRole(ctx, service.SetUserRoleOpt{
+		UserID: user.ID,
+		Role:   entity.UserRoleAdmin,
+	})
+	if err != nil {
+		logger.Fatal("failed to promote user", "err", err, "userID", user.ID)
+	}
+
+	logger.Info("successfully promoted user to admin", "userID", user.ID)
+}
@@ -1,54 +1,99 @@
 package app
 
 import (
+	"context"
 	httpcontroller "darkness8129/news-api/app/controller/http"
 	"darkness8129/news-api/app/entity"
 	"darkness8129/news-api/app/service"
 	"darkness8129/news-api/app/storage"
+	"darkness8129/news-api/app/worker"
 	"darkness8129/news-api/config"
 	"darkness8129/news-api/packages/database"
 	"darkness8129/news-api/packages/httpserver"
 	"darkness8129/news-api/packages/logging"
+	"darkness8129/news-api/packages/oidc"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+const (
+	// minJWTSecretLength is the size of HS256 keys, shorter secrets are easier to brute-force
+	minJWTSecretLength = 32
+	// placeholderJWTSecret was the example secret in .env, it's public, so anyone could sign tokens with it
+	placeholderJWTSecret = "change-me-in-production"
+)
+
 func Start(cfg *config.Config, logger logging.Logger) {
 	logger = logger.Named("app")
 
-	// connect to DB
-	sql, err := database.NewPostgreSQLDatabase(database.Options{
-		User:     cfg.PostgreSQL.User,
-		Password: cfg.PostgreSQL.Password,
-		Database: cfg.PostgreSQL.Database,
-		Port:     cfg.PostgreSQL.Port,
-		Host:     cfg.PostgreSQL.Host,
-		Logger:   logger,
-	})
-	if err != nil {
-		logger.Fatal("failed to init postgresql db", "err", err)
+	switch {
+	case cfg.Auth.JWTSecret == "":
+		logger.Fatal("jwt secret is required")
+	case cfg.Auth.JWTSecret == placeholderJWTSecret:
+		logger.Fatal("jwt secret is the public placeholder, set a random one")
+	case len(cfg.Auth.JWTSecret) < minJWTSecretLength:
+		logger.Fatal("jwt secret is too short", "minLength", minJWTSecretLength)
 	}
 
-	db, ok := sql.DB().(*gorm.DB)
-	if !ok {
-		logger.Fatal("failed type assertion for db")
+	// the purger deletes posts trashed before now minus the retention, so anything shorter would empty the trash
+	if cfg.Worker.TrashRetentionDays < 1 {
+		logger.Fatal("trash retention must be at least 1 day", "days", cfg.Worker.TrashRetentionDays)
 	}
 
-	err = db.AutoMigrate(&entity.Post{})
-	if err != nil {
-		logger.Fatal("automigration failed", "err", err)
+	authOpt := service.AuthServiceOptions{
+		Secret:          []byte(cfg.Auth.JWTSecret),
+		AccessTokenTTL:  cfg.Auth.AccessTokenTTL,
+		RefreshTokenTTL: cfg.Auth.RefreshTokenTTL,
+		AdminEmails:     cfg.Auth.AdminEmails,
 	}
+	if cfg.Auth.OIDCIssuer != "" {
+		if cfg.Auth.OIDCClientID == "" || cfg.Auth.OIDCRedirectURL == "" {
+			logger.Fatal("oidc client id and redirect url are required")
+		}
+
+		authOpt.OIDC = oidc.NewHTTPProvider(oidc.Options{
+			Issuer:       cfg.Auth.OIDCIssuer,
+			ClientID:     cfg.Auth.OIDCClientID,
+			ClientSecret: cfg.Auth.OIDCClientSecret,
+			RedirectURL:  cfg.Auth.OIDCRedirectURL,
+			Scopes:       cfg.Auth.OIDCScopes,
+		})
+		authOpt.OIDCRolesClaim = cfg.Auth.OIDCRolesClaim
+		authOpt.OIDCRoles = make(map[string]entity.UserRole, len(cfg.Auth.OIDCRoles))
+		for group, role := range cfg.Auth.OIDCRoles {
+			if !entity.UserRole(role).IsValid() {
+				logger.Fatal("invalid role of oidc group", "group", group, "role", role)
+			}
+			authOpt.OIDCRoles[group] = entity.UserRole(role)
+		}
+	}
+
+	sql, db := connectDB(cfg, logger)
 
 	// init storages and services
-	storages := service.Storages{
-		Post: storage.NewPostStorage(db, logger),
-	}
+	storages := storage.NewStorages(db, logger)
 	services := service.Services{
-		Post: service.NewPostService(storages, logger),
+		Post:        service.NewPostService(storages, logger),
+		Idempotency: service.NewIdempotencyService(storages, cfg.HTTP.IdempotencyKeyTTL, logger),
+		Tag:         service.NewTagService(storages, logger),
+		Category:    service.NewCategoryService(storages, logger),
+		Author:      service.NewAuthorService(storages, logger),
+		Comment:     service.NewCommentService(storages, cfg.Comments.Premoderation, logger),
+		Moderation:  service.NewModerationService(storages, cfg.Comments.Premoderation, logger),
+		Auth:        service.NewAuthService(storages, authOpt, logger),
+		User:        service.NewUserService(storages, logger),
+		APIKey:      service.NewAPIKeyService(storages, logger),
+	}
+
+	// posts created before slugs get them here, so they can be found by slug too
+	_, err := services.Post.BackfillSlugs(service.SystemContext(context.Background()))
+	if err != nil {
+		logger.Fatal("failed to backfill post slugs", "err", err)
 	}
 
 	// init http server and start it
@@ -58,18 +103,44 @@ func Start(cfg *config.Config, logger logging.Logger) {
 		ReadTimeout:  cfg.HTTP.ReadTimeout,
 	})
 
-	router := httpServer.Router().(*gin.Engine)
+	router, ok := httpServer.Router().(*gin.Engine)
 	if !ok {
 		logger.Fatal("failed type assertion for router")
 	}
 
 	httpcontroller.New(httpcontroller.Options{
-		Router:   router,
+		Router:         router,
+		Services:       services,
+		Logger:         logger,
+		ProblemDetails: cfg.HTTP.ProblemDetails,
+		RequireIfMatch: cfg.HTTP.RequireIfMatch,
+		MaxBatchSize:   cfg.HTTP.MaxBatchSize,
+	})
+
+	httpServer.Start()
+
+	// start background workers
+	scheduledPublisher := worker.NewScheduledPublisher(worker.ScheduledPublisherOptions{
 		Services: services,
+		Interval: cfg.Worker.PublishInterval,
 		Logger:   logger,
 	})
+	scheduledPublisher.Start()
 
-	httpServer.Start()
+	trashPurger := worker.NewTrashPurger(worker.TrashPurgerOptions{
+		Services:  services,
+		Interval:  cfg.Worker.TrashPurgeInterval,
+		Retention: time.Duration(cfg.Worker.TrashRetentionDays) * 24 * time.Hour,
+		Logger:    logger,
+	})
+	trashPurger.Start()
+
+	idempotencyKeyPurger := worker.NewIdempotencyKeyPurger(worker.IdempotencyKeyPurgerOptions{
+		Services: services,
+		Interval: cfg.Worker.IdempotencyKeyPurgeInterval,
+		Logger:   logger,
+	})
+	idempotencyKeyPurger.Start()
 
 	// graceful shutdown
 	interrupt := make(chan os.Signal, 1)
@@ -87,6 +158,21 @@ func Start(cfg *config.Config, logger logging.Logger) {
 		logger.Error("failed to shutdown server", "err", err)
 	}
 
+	err = scheduledPublisher.Stop(cfg.ShutdownTimeout)
+	if err != nil {
+		logger.Error("failed to stop scheduled publisher", "err", err)
+	}
+
+	err = trashPurger.Stop(cfg.ShutdownTimeout)
+	if err != nil {
+		logger.Error("failed to stop trash purger", "err", err)
+	}
+
+	err = idempotencyKeyPurger.Stop(cfg.ShutdownTimeout)
+	if err != nil {
+		logger.Error("failed to stop idempotency key purger", "err", err)
+	}
+
 	err = sql.Close()
 	if err != nil {
 		logger.Error("failed to close db connection", "err", err)
@@ -94,3 +180,30 @@ func Start(cfg *config.Config, logger logging.Logger) {
 
 	logger.Info("successful shutdown")
 }
+
+// connectDB connects to the DB and migrates it, the app can't work without it
+func connectDB(cfg *config.Config, logger logging.Logger) (database.Database, *gorm.DB) {
+	sql, err := database.NewPostgreSQLDatabase(database.Options{
+		User:     cfg.PostgreSQL.User,
+		Password: cfg.PostgreSQL.Password,
+		Database: cfg.PostgreSQL.Database,
+		Port:     cfg.PostgreSQL.Port,
+		Host:     cfg.PostgreSQL.Host,
+		Logger:   logger,
+	})
+	if err != nil {
+		logger.Fatal("failed to init postgresql db", "err", err)
+	}
+
+	db, ok := sql.DB().(*gorm.DB)
+	if !ok {
+		logger.Fatal("failed type assertion for db")
+	}
+
+	err = storage.Migrate(db)
+	if err != nil {
+		logger.Fatal("migration failed", "err", err)
+	}
+
+	return sql, db
+}
@@ -0,0 +1,201 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newAPIKeyController(opt controllerOptions) {
+	logger := opt.Logger.Named("apiKeyController")
+
+	c := apiKeyController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/api-keys")
+	group.POST("", errorDecorator(logger, c.create))
+	group.GET("", errorDecorator(logger, c.list))
+	group.POST(":id/revoke", errorDecorator(logger, c.revoke))
+}
+
+type apiKeyDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the beginning of the key to tell keys apart
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes" enums:"posts:read,posts:write,admin"`
+	// Active is false for expired and revoked keys
+	Active      bool       `json:"active"`
+	CreatedByID *string    `json:"createdById,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+} // @name APIKey
+
+func toAPIKeyDTO(k *entity.APIKey) *apiKeyDTO {
+	dto := &apiKeyDTO{
+		ID:          k.ID,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Scopes:      make([]string, 0, len(k.Scopes)),
+		Active:      k.IsActive(time.Now()),
+		CreatedByID: k.CreatedByID,
+		ExpiresAt:   k.ExpiresAt,
+		LastUsedAt:  k.LastUsedAt,
+		RevokedAt:   k.RevokedAt,
+		CreatedAt:   k.CreatedAt,
+	}
+	for _, scope := range k.Scopes {
+		dto.Scopes = append(dto.Scopes, string(scope))
+	}
+
+	return dto
+}
+
+type createAPIKeyBody struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,max=3,dive,oneof=posts:read posts:write admin"`
+	// ExpiresAt must be in the future, keys without it are valid till they are revoked
+	ExpiresAt *time.Time `json:"expiresAt"`
+} // @name createAPIKeyBody
+
+type createAPIKeyResponse struct {
+	APIKey *apiKeyDTO `json:"apiKey"`
+	// Key is shown only once, it's sent in X-API-Key header or as a bearer token
+	Key string `json:"key"`
+} // @name createAPIKeyResponse
+
+// @ID           CreateAPIKey
+// @Summary      CreateAPIKey provides the logic for creating an API key for machine clients, it's available to admins only. The key is returned only once.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body createAPIKeyBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} createAPIKeyResponse
+// @Failure      400,401,403,422,500 {object} httpErr
+// @Router       /api-keys [POST]
+func (ctrl *apiKeyController) create(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("create")
+
+	var body createAPIKeyBody
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	scopes := make([]entity.APIKeyScope, 0, len(body.Scopes))
+	for _, scope := range body.Scopes {
+		scopes = append(scopes, entity.APIKeyScope(scope))
+	}
+
+	created, err := ctrl.services.APIKey.Create(c, service.CreateAPIKeyOpt{
+		Name:      body.Name,
+		Scopes:    scopes,
+		ExpiresAt: body.ExpiresAt,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to create API key", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to create API key"}
+	}
+
+	logger.Info("successfully created API key", "keyID", created.APIKey.ID)
+	return createAPIKeyResponse{APIKey: toAPIKeyDTO(created.APIKey), Key: created.Key}, nil
+}
+
+type listAPIKeysResponse struct {
+	APIKeys []*apiKeyDTO `json:"apiKeys"`
+} // @name listAPIKeysResponse
+
+// @ID           ListAPIKeys
+// @Summary      ListAPIKeys provides the logic for retrieving all API keys including expired and revoked ones, the newest first. It's available to admins only.
+// @Produce      application/json
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} listAPIKeysResponse
+// @Failure      401,403,422,500 {object} httpErr
+// @Router       /api-keys [GET]
+func (ctrl *apiKeyController) list(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("list")
+
+	keys, err := ctrl.services.APIKey.List(c)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list API keys", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list API keys"}
+	}
+
+	keysDTO := make([]*apiKeyDTO, 0, len(keys))
+	for _, key := range keys {
+		keysDTO = append(keysDTO, toAPIKeyDTO(&key))
+	}
+
+	logger.Info("successfully listed API keys", "count", len(keysDTO))
+	return listAPIKeysResponse{keysDTO}, nil
+}
+
+type revokeAPIKeyPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name revokeAPIKeyPathParams
+
+type revokeAPIKeyResponse struct {
+	APIKey *apiKeyDTO `json:"apiKey"`
+} // @name revokeAPIKeyResponse
+
+// @ID           RevokeAPIKey
+// @Summary      RevokeAPIKey provides the logic for revoking an API key, it's rejected right away and kept for audit. It's available to admins only.
+// @Produce      application/json
+// @Param        id path string true "API key ID"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} revokeAPIKeyResponse
+// @Failure      400,401,403,404,422,500 {object} httpErr
+// @Router       /api-keys/{id}/revoke [POST]
+func (ctrl *apiKeyController) revoke(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("revoke")
+
+	var pathParams revokeAPIKeyPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	key, err := ctrl.services.APIKey.Revoke(c, pathParams.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to revoke API key", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to revoke API key"}
+	}
+
+	logger.Info("successfully revoked API key", "keyID", key.ID)
+	return revokeAPIKeyResponse{toAPIKeyDTO(key)}, nil
+}
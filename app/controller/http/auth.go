@@ -0,0 +1,399 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	bearerPrefix = "Bearer "
+	apiKeyHeader = "X-API-Key"
+)
+
+type authController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newAuthController(opt controllerOptions) {
+	logger := opt.Logger.Named("authController")
+
+	c := authController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/auth")
+	group.POST("register", errorDecorator(logger, c.register))
+	group.POST("login", errorDecorator(logger, c.login))
+	group.POST("refresh", errorDecorator(logger, c.refresh))
+	group.POST("oidc/login", errorDecorator(logger, c.startOIDCLogin))
+	group.GET("oidc/callback", errorDecorator(logger, c.completeOIDCLogin))
+	group.GET("me", errorDecorator(logger, c.me))
+}
+
+// authMiddleware puts the principal of the access token or the API key into the request context.
+// API keys are accepted in X-API-Key header or as bearer tokens. Requests without credentials
+// are anonymous, while requests with invalid ones are rejected.
+func authMiddleware(services service.Services, logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := logger.Named("authMiddleware")
+
+		header := c.GetHeader("Authorization")
+		apiKey := c.GetHeader(apiKeyHeader)
+		if header == "" && apiKey == "" {
+			c.Next()
+			return
+		}
+
+		// it's unclear which credentials the client wants to act with
+		if header != "" && apiKey != "" {
+			logger.Info("both authorization and API key headers are passed")
+			c.Header("WWW-Authenticate", `Bearer error="invalid_request"`)
+			abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeClient, Message: "pass either Authorization or X-API-Key header", Kind: errs.KindUnauthorized})
+			return
+		}
+
+		var token string
+		if header != "" {
+			var ok bool
+			token, ok = strings.CutPrefix(header, bearerPrefix)
+			if !ok {
+				logger.Info("unsupported authorization scheme")
+				c.Header("WWW-Authenticate", `Bearer error="invalid_request"`)
+				abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeClient, Message: "unsupported authorization scheme", Kind: errs.KindUnauthorized})
+				return
+			}
+			if strings.HasPrefix(token, service.APIKeyPrefix) {
+				apiKey, token = token, ""
+			}
+		}
+
+		var principal *service.Principal
+		var err error
+		if apiKey != "" {
+			principal, err = services.APIKey.Authenticate(c, apiKey)
+		} else {
+			principal, err = services.Auth.Authenticate(c, token)
+		}
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+				abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)})
+				return
+			}
+
+			logger.Error("failed to authenticate", "err", err)
+			abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeServer, Message: "failed to authenticate"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(service.ContextWithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// principal returns nil for anonymous requests
+func principal(c *gin.Context) *service.Principal {
+	return service.PrincipalFromContext(c.Request.Context())
+}
+
+type userDTO struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role" enums:"reader,author,editor,admin"`
+	CreatedAt time.Time `json:"createdAt"`
+} // @name User
+
+func toUserDTO(u *entity.User) *userDTO {
+	return &userDTO{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Role:      string(u.Role),
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+type authTokensDTO struct {
+	// TokenType is always Bearer, the access token is sent in Authorization header
+	TokenType             string    `json:"tokenType"`
+	AccessToken           string    `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time `json:"accessTokenExpiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+} // @name AuthTokens
+
+func toAuthTokensDTO(t *service.AuthTokens) *authTokensDTO {
+	return &authTokensDTO{
+		TokenType:             strings.TrimSpace(bearerPrefix),
+		AccessToken:           t.AccessToken,
+		AccessTokenExpiresAt:  t.AccessTokenExpiresAt,
+		RefreshToken:          t.RefreshToken,
+		RefreshTokenExpiresAt: t.RefreshTokenExpiresAt,
+	}
+}
+
+type registerBody struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+	Name  string `json:"name" binding:"required,max=100"`
+	// Password is limited to 72 bytes, longer passwords can't be hashed with bcrypt
+	Password string `json:"password" binding:"required,min=8,max=72"`
+} // @name registerBody
+
+type registerResponse struct {
+	User *userDTO `json:"user"`
+} // @name registerResponse
+
+// @ID           Register
+// @Summary      Register provides the logic for creating a user account, the email is used to sign in.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body registerBody true "data"
+// @Success      200 {object} registerResponse
+// @Failure      409,422,500 {object} httpErr
+// @Router       /auth/register [POST]
+func (ctrl *authController) register(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("register")
+
+	var body registerBody
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "email", body.Email, "name", body.Name)
+
+	user, err := ctrl.services.Auth.Register(c, service.RegisterOpt{
+		Email:    body.Email,
+		Name:     body.Name,
+		Password: body.Password,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to register user", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to register user"}
+	}
+
+	logger.Info("successfully registered user", "userID", user.ID)
+	return registerResponse{toUserDTO(user)}, nil
+}
+
+type loginBody struct {
+	Email    string `json:"email" binding:"required,max=254"`
+	Password string `json:"password" binding:"required,max=72"`
+} // @name loginBody
+
+type loginResponse struct {
+	Tokens *authTokensDTO `json:"tokens"`
+} // @name loginResponse
+
+// @ID           Login
+// @Summary      Login provides the logic for signing in with the email and password. The access token is sent in Authorization header, the refresh token is exchanged for new tokens when the access token expires.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body loginBody true "data"
+// @Success      200 {object} loginResponse
+// @Failure      401,422,500 {object} httpErr
+// @Router       /auth/login [POST]
+func (ctrl *authController) login(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("login")
+
+	var body loginBody
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "email", body.Email)
+
+	tokens, err := ctrl.services.Auth.Login(c, service.LoginOpt{
+		Email:    body.Email,
+		Password: body.Password,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to login", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to login"}
+	}
+
+	logger.Info("successfully logged in")
+	return loginResponse{toAuthTokensDTO(tokens)}, nil
+}
+
+type refreshBody struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+} // @name refreshBody
+
+type refreshResponse struct {
+	Tokens *authTokensDTO `json:"tokens"`
+} // @name refreshResponse
+
+// @ID           Refresh
+// @Summary      Refresh provides the logic for exchanging the refresh token for new tokens. Each refresh token can be used once, using it again signs out all sessions started with the same login.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body refreshBody true "data"
+// @Success      200 {object} refreshResponse
+// @Failure      401,422,500 {object} httpErr
+// @Router       /auth/refresh [POST]
+func (ctrl *authController) refresh(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("refresh")
+
+	var body refreshBody
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+
+	tokens, err := ctrl.services.Auth.Refresh(c, body.RefreshToken)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to refresh tokens", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to refresh tokens"}
+	}
+
+	logger.Info("successfully refreshed tokens")
+	return refreshResponse{toAuthTokensDTO(tokens)}, nil
+}
+
+type startOIDCLoginResponse struct {
+	// AuthorizationURL is the page of the identity provider the user is sent to
+	AuthorizationURL string `json:"authorizationUrl"`
+} // @name startOIDCLoginResponse
+
+// @ID           StartOIDCLogin
+// @Summary      StartOIDCLogin provides the logic for starting the sign-in through the identity provider. The user has 10 minutes to sign in there, then the provider sends the code and the state to the redirect URL.
+// @Produce      application/json
+// @Success      200 {object} startOIDCLoginResponse
+// @Failure      404,500 {object} httpErr
+// @Router       /auth/oidc/login [POST]
+func (ctrl *authController) startOIDCLogin(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("startOIDCLogin")
+
+	authURL, err := ctrl.services.Auth.StartOIDCLogin(c)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to start oidc login", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to start oidc login"}
+	}
+
+	logger.Info("successfully started oidc login")
+	return startOIDCLoginResponse{authURL}, nil
+}
+
+type completeOIDCLoginQueryParams struct {
+	State string `form:"state" json:"state" binding:"required"`
+	// Code is missing if the provider sends an error
+	Code  string `form:"code" json:"code" binding:"required_without=Error"`
+	Error string `form:"error" json:"error"`
+} // @name completeOIDCLoginQueryParams
+
+type completeOIDCLoginResponse struct {
+	Tokens *authTokensDTO `json:"tokens"`
+} // @name completeOIDCLoginResponse
+
+// @ID           CompleteOIDCLogin
+// @Summary      CompleteOIDCLogin provides the logic for finishing the sign-in through the identity provider with the query params it sent to the redirect URL. Unknown users are created and users with the same verified email are linked, their roles are synced from the provider groups if the mapping is configured.
+// @Produce      application/json
+// @Param        state query string true "State of the sign-in"
+// @Param        code query string false "Code issued by the provider"
+// @Param        error query string false "Error sent by the provider instead of the code"
+// @Success      200 {object} completeOIDCLoginResponse
+// @Failure      401,403,404,409,422,500 {object} httpErr
+// @Router       /auth/oidc/callback [GET]
+func (ctrl *authController) completeOIDCLogin(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("completeOIDCLogin")
+
+	var queryParams completeOIDCLoginQueryParams
+	err := c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "error", queryParams.Error)
+
+	tokens, err := ctrl.services.Auth.CompleteOIDCLogin(c, service.CompleteOIDCLoginOpt{
+		State: queryParams.State,
+		Code:  queryParams.Code,
+		Error: queryParams.Error,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to complete oidc login", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to complete oidc login"}
+	}
+
+	logger.Info("successfully completed oidc login")
+	return completeOIDCLoginResponse{toAuthTokensDTO(tokens)}, nil
+}
+
+type meResponse struct {
+	User *userDTO `json:"user"`
+} // @name meResponse
+
+// @ID           Me
+// @Summary      Me provides the logic for retrieving the signed in user.
+// @Produce      application/json
+// @Security     BearerAuth
+// @Success      200 {object} meResponse
+// @Failure      401,404,500 {object} httpErr
+// @Router       /auth/me [GET]
+func (ctrl *authController) me(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("me")
+
+	p := principal(c)
+	if p == nil {
+		logger.Info("anonymous request")
+		err := service.ErrAuthenticationRequired
+		return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+	}
+	if p.UserID == "" {
+		logger.Info("request with API key", "apiKeyID", p.APIKeyID)
+		err := service.ErrForbidden
+		return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+	}
+
+	user, err := ctrl.services.Auth.GetUser(c, p.UserID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get user", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get user"}
+	}
+
+	logger.Info("successfully got user", "userID", user.ID)
+	return meResponse{toUserDTO(user)}, nil
+}
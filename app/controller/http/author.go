@@ -0,0 +1,256 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type authorController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newAuthorController(opt controllerOptions) {
+	logger := opt.Logger.Named("authorController")
+
+	c := authorController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/authors")
+	group.POST("", errorDecorator(logger, c.create))
+	group.GET("", errorDecorator(logger, c.list))
+	group.GET(":id", errorDecorator(logger, c.get))
+	group.GET(":id/posts", errorDecorator(logger, c.listPosts))
+}
+
+type authorDTO struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Bio       string    `json:"bio"`
+	AvatarURL string    `json:"avatarUrl"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+} // @name Author
+
+func toAuthorDTO(a *entity.Author) *authorDTO {
+	return &authorDTO{
+		ID:        a.ID,
+		Name:      a.Name,
+		Slug:      a.Slug,
+		Bio:       a.Bio,
+		AvatarURL: a.AvatarURL,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
+
+// authorSummaryDTO is an author embedded in a byline of a post
+type authorSummaryDTO struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	AvatarURL string `json:"avatarUrl"`
+} // @name AuthorSummary
+
+func toAuthorSummaryDTO(a *entity.Author) *authorSummaryDTO {
+	return &authorSummaryDTO{
+		ID:        a.ID,
+		Name:      a.Name,
+		Slug:      a.Slug,
+		AvatarURL: a.AvatarURL,
+	}
+}
+
+type createAuthorBody struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Slug must consist of lowercase letters, digits and single hyphens, e.g. jane-doe
+	Slug      string `json:"slug" binding:"required,max=100"`
+	Bio       string `json:"bio" binding:"max=1000"`
+	AvatarURL string `json:"avatarUrl" binding:"omitempty,url,max=500"`
+} // @name createAuthorBody
+
+type createAuthorResponse struct {
+	Author *authorDTO `json:"author"`
+} // @name createAuthorResponse
+
+// @ID           CreateAuthor
+// @Summary      CreateAuthor provides the logic for creating an author with a unique slug.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body createAuthorBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} createAuthorResponse
+// @Failure      400,401,403,409,422,500 {object} httpErr
+// @Router       /authors [POST]
+func (ctrl *authorController) create(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("create")
+
+	var body createAuthorBody
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	author, err := ctrl.services.Author.Create(c, service.CreateAuthorOpt{
+		Name:      body.Name,
+		Slug:      body.Slug,
+		Bio:       body.Bio,
+		AvatarURL: body.AvatarURL,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to create author", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to create author"}
+	}
+
+	logger.Info("successfully created author", "author", author)
+	return createAuthorResponse{toAuthorDTO(author)}, nil
+}
+
+type listAuthorsResponse struct {
+	Authors []*authorDTO `json:"authors"`
+} // @name listAuthorsResponse
+
+// @ID           ListAuthors
+// @Summary      ListAuthors provides the logic for retrieving all authors ordered by name.
+// @Produce      application/json
+// @Success      200 {object} listAuthorsResponse
+// @Failure      500 {object} httpErr
+// @Router       /authors [GET]
+func (ctrl *authorController) list(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("list")
+
+	authors, err := ctrl.services.Author.List(c)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list authors", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list authors"}
+	}
+
+	authorsDTO := make([]*authorDTO, 0, len(authors))
+	for _, author := range authors {
+		authorsDTO = append(authorsDTO, toAuthorDTO(&author))
+	}
+
+	logger.Info("successfully listed authors", "authors", authorsDTO)
+	return listAuthorsResponse{authorsDTO}, nil
+}
+
+type getAuthorPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name getAuthorPathParams
+
+type getAuthorResponse struct {
+	Author *authorDTO `json:"author"`
+} // @name getAuthorResponse
+
+// @ID           GetAuthor
+// @Summary      GetAuthor provides the logic for retrieving an author by its ID.
+// @Produce      application/json
+// @Param        id path string true "Author ID"
+// @Success      200 {object} getAuthorResponse
+// @Failure      404,422,500 {object} httpErr
+// @Router       /authors/{id} [GET]
+func (ctrl *authorController) get(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("get")
+
+	var pathParams getAuthorPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	author, err := ctrl.services.Author.Get(c, pathParams.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get author", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get author"}
+	}
+
+	logger.Info("successfully got author", "author", author)
+	return getAuthorResponse{toAuthorDTO(author)}, nil
+}
+
+type listAuthorPostsPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name listAuthorPostsPathParams
+
+// @ID           ListAuthorPosts
+// @Summary      ListAuthorPosts provides the logic for retrieving published posts with the author in the bylines page by page.
+// @Produce      application/json
+// @Param        id path string true "Author ID"
+// @Param        tag query []string false "Only posts with these tags, the param can be repeated" collectionFormat(multi)
+// @Param        tagMatch query string false "Whether posts must have any of the tags or all of them, any by default" Enums(any, all)
+// @Param        categoryId query string false "Only posts of the category and its subcategories"
+// @Param        sort query string false "Sort key, the newest first by default" Enums(created_at, -created_at, updated_at, title)
+// @Param        limit query int false "Max number of posts on the page (1-100, 20 by default)"
+// @Param        cursor query string false "Cursor from the nextCursor of the previous page"
+// @Success      200 {object} listPostsResponse
+// @Failure      400,404,422,500 {object} httpErr
+// @Router       /authors/{id}/posts [GET]
+func (ctrl *authorController) listPosts(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("listPosts")
+
+	var pathParams listAuthorPostsPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var queryParams listPostsQueryParams
+	err = c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	opt := queryParams.toListPostsOpt()
+	opt.AuthorID = pathParams.ID
+
+	result, err := ctrl.services.Post.List(c, opt)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list author posts", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list author posts"}
+	}
+
+	postsDTO := make([]*postDTO, 0, len(result.Posts))
+	for _, p := range result.Posts {
+		postsDTO = append(postsDTO, ToPostDTO(&p))
+	}
+
+	logger.Info("successfully listed author posts", "posts", postsDTO)
+	return listPostsResponse{Posts: postsDTO, NextCursor: result.NextCursor}, nil
+}
@@ -0,0 +1,344 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type categoryController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newCategoryController(opt controllerOptions) {
+	logger := opt.Logger.Named("categoryController")
+
+	c := categoryController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/categories")
+	group.POST("", errorDecorator(logger, c.create))
+	group.GET("", errorDecorator(logger, c.list))
+	group.GET(":id", errorDecorator(logger, c.get))
+	group.PUT(":id", errorDecorator(logger, c.update))
+	group.DELETE(":id", errorDecorator(logger, c.delete))
+	group.GET(":id/posts", errorDecorator(logger, c.listPosts))
+}
+
+type categoryDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// ParentID is omitted for top-level categories
+	ParentID  *string   `json:"parentId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+} // @name Category
+
+func toCategoryDTO(c *entity.Category) *categoryDTO {
+	return &categoryDTO{
+		ID:        c.ID,
+		Name:      c.Name,
+		ParentID:  c.ParentID,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+type createCategoryBody struct {
+	Name     string  `json:"name" binding:"required,max=50"`
+	ParentID *string `json:"parentId" binding:"omitempty,uuid"`
+} // @name createCategoryBody
+
+type createCategoryResponse struct {
+	Category *categoryDTO `json:"category"`
+} // @name createCategoryResponse
+
+// @ID           CreateCategory
+// @Summary      CreateCategory provides the logic for creating a top-level category or a subcategory.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body createCategoryBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} createCategoryResponse
+// @Failure      401,403,422,500 {object} httpErr
+// @Router       /categories [POST]
+func (ctrl *categoryController) create(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("create")
+
+	var body createCategoryBody
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	category, err := ctrl.services.Category.Create(c, service.CreateCategoryOpt{
+		Name:     body.Name,
+		ParentID: body.ParentID,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to create category", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to create category"}
+	}
+
+	logger.Info("successfully created category", "category", category)
+	return createCategoryResponse{toCategoryDTO(category)}, nil
+}
+
+type listCategoriesResponse struct {
+	Categories []*categoryDTO `json:"categories"`
+} // @name listCategoriesResponse
+
+// @ID           ListCategories
+// @Summary      ListCategories provides the logic for retrieving all categories ordered by name, the tree is built from their parentId.
+// @Produce      application/json
+// @Success      200 {object} listCategoriesResponse
+// @Failure      500 {object} httpErr
+// @Router       /categories [GET]
+func (ctrl *categoryController) list(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("list")
+
+	categories, err := ctrl.services.Category.List(c)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list categories", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list categories"}
+	}
+
+	categoriesDTO := make([]*categoryDTO, 0, len(categories))
+	for _, category := range categories {
+		categoriesDTO = append(categoriesDTO, toCategoryDTO(&category))
+	}
+
+	logger.Info("successfully listed categories", "categories", categoriesDTO)
+	return listCategoriesResponse{categoriesDTO}, nil
+}
+
+type getCategoryPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name getCategoryPathParams
+
+type getCategoryResponse struct {
+	Category *categoryDTO `json:"category"`
+	// Children are direct subcategories ordered by name
+	Children []*categoryDTO `json:"children"`
+} // @name getCategoryResponse
+
+// @ID           GetCategory
+// @Summary      GetCategory provides the logic for retrieving a category with its direct subcategories by its ID.
+// @Produce      application/json
+// @Param        id path string true "Category ID"
+// @Success      200 {object} getCategoryResponse
+// @Failure      404,422,500 {object} httpErr
+// @Router       /categories/{id} [GET]
+func (ctrl *categoryController) get(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("get")
+
+	var pathParams getCategoryPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	category, err := ctrl.services.Category.Get(c, pathParams.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get category", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get category"}
+	}
+
+	children := make([]*categoryDTO, 0, len(category.Children))
+	for _, child := range category.Children {
+		children = append(children, toCategoryDTO(&child))
+	}
+
+	logger.Info("successfully got category", "category", category)
+	return getCategoryResponse{toCategoryDTO(category), children}, nil
+}
+
+type updateCategoryPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name updateCategoryPathParams
+
+type updateCategoryBody struct {
+	Name string `json:"name" binding:"required,max=50"`
+	// ParentID moves the category, it becomes top-level if omitted
+	ParentID *string `json:"parentId" binding:"omitempty,uuid"`
+} // @name updateCategoryBody
+
+type updateCategoryResponse struct {
+	Category *categoryDTO `json:"category"`
+} // @name updateCategoryResponse
+
+// @ID           UpdateCategory
+// @Summary      UpdateCategory provides the logic for renaming and moving a category by its ID, it can't be moved under itself or its descendants.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "Category ID"
+// @Param        fields body updateCategoryBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} updateCategoryResponse
+// @Failure      401,403,404,409,422,500 {object} httpErr
+// @Router       /categories/{id} [PUT]
+func (ctrl *categoryController) update(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("update")
+
+	var pathParams updateCategoryPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var body updateCategoryBody
+	err = c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	updatedCategory, err := ctrl.services.Category.Update(c, pathParams.ID, service.UpdateCategoryOpt{
+		Name:     body.Name,
+		ParentID: body.ParentID,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to update category", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to update category"}
+	}
+
+	logger.Info("successfully updated category", "updatedCategory", updatedCategory)
+	return updateCategoryResponse{toCategoryDTO(updatedCategory)}, nil
+}
+
+type deleteCategoryPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name deleteCategoryPathParams
+
+type deleteCategoryResponse struct {
+} // @name deleteCategoryResponse
+
+// @ID           DeleteCategory
+// @Summary      DeleteCategory provides the logic for deleting a category without subcategories and posts by its ID.
+// @Produce      application/json
+// @Param        id path string true "Category ID"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} deleteCategoryResponse
+// @Failure      401,403,404,409,422,500 {object} httpErr
+// @Router       /categories/{id} [DELETE]
+func (ctrl *categoryController) delete(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("delete")
+
+	var pathParams deleteCategoryPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	err = ctrl.services.Category.Delete(c, pathParams.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to delete category", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to delete category"}
+	}
+
+	logger.Info("successfully deleted category")
+	return deleteCategoryResponse{}, nil
+}
+
+type listCategoryPostsPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name listCategoryPostsPathParams
+
+// @ID           ListCategoryPosts
+// @Summary      ListCategoryPosts provides the logic for retrieving published posts of a category and all its subcategories page by page.
+// @Produce      application/json
+// @Param        id path string true "Category ID"
+// @Param        tag query []string false "Only posts with these tags, the param can be repeated" collectionFormat(multi)
+// @Param        tagMatch query string false "Whether posts must have any of the tags or all of them, any by default" Enums(any, all)
+// @Param        authorId query string false "Only posts with the author in the bylines"
+// @Param        sort query string false "Sort key, the newest first by default" Enums(created_at, -created_at, updated_at, title)
+// @Param        limit query int false "Max number of posts on the page (1-100, 20 by default)"
+// @Param        cursor query string false "Cursor from the nextCursor of the previous page"
+// @Success      200 {object} listPostsResponse
+// @Failure      400,404,422,500 {object} httpErr
+// @Router       /categories/{id}/posts [GET]
+func (ctrl *categoryController) listPosts(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("listPosts")
+
+	var pathParams listCategoryPostsPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var queryParams listPostsQueryParams
+	err = c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	opt := queryParams.toListPostsOpt()
+	opt.CategoryID = pathParams.ID
+
+	result, err := ctrl.services.Post.List(c, opt)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list category posts", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list category posts"}
+	}
+
+	postsDTO := make([]*postDTO, 0, len(result.Posts))
+	for _, p := range result.Posts {
+		postsDTO = append(postsDTO, ToPostDTO(&p))
+	}
+
+	logger.Info("successfully listed category posts", "posts", postsDTO)
+	return listPostsResponse{Posts: postsDTO, NextCursor: result.NextCursor}, nil
+}
@@ -0,0 +1,314 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// commentTokenHeader carries the token given on comment creation, it's required to edit or delete the comment
+const commentTokenHeader = "X-Comment-Token"
+
+type commentController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newCommentController(opt controllerOptions) {
+	logger := opt.Logger.Named("commentController")
+
+	c := commentController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/posts/:id/comments")
+	group.GET("", errorDecorator(logger, c.list))
+	group.POST("", errorDecorator(logger, c.create))
+	group.PUT(":commentId", errorDecorator(logger, c.update))
+	group.DELETE(":commentId", errorDecorator(logger, c.delete))
+}
+
+type commentDTO struct {
+	ID       string  `json:"id"`
+	PostID   string  `json:"postId"`
+	ParentID *string `json:"parentId,omitempty"`
+	// AuthorName and Content are empty for deleted comments, they are kept as placeholders for their replies
+	AuthorName string `json:"authorName"`
+	Content    string `json:"content"`
+	// Status is approved for all listed comments, new comments of pre-moderated posts are pending
+	Status       string     `json:"status"`
+	Deleted      bool       `json:"deleted"`
+	RepliesCount int64      `json:"repliesCount"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	EditedAt     *time.Time `json:"editedAt,omitempty"`
+} // @name Comment
+
+func toCommentDTO(c *entity.Comment) *commentDTO {
+	dto := &commentDTO{
+		ID:           c.ID,
+		PostID:       c.PostID,
+		ParentID:     c.ParentID,
+		AuthorName:   c.AuthorName,
+		Content:      c.Content,
+		Status:       string(c.Status),
+		Deleted:      c.DeletedAt != nil,
+		RepliesCount: c.RepliesCount,
+		CreatedAt:    c.CreatedAt,
+		EditedAt:     c.EditedAt,
+	}
+	if dto.Deleted {
+		dto.AuthorName = ""
+		dto.Content = ""
+	}
+
+	return dto
+}
+
+type listCommentsPathParams struct {
+	PostID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name listCommentsPathParams
+
+type listCommentsQueryParams struct {
+	ParentID *string `form:"parentId" json:"parentId" binding:"omitempty,uuid"`
+	Limit    int     `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string  `form:"cursor" json:"cursor"`
+} // @name listCommentsQueryParams
+
+type listCommentsResponse struct {
+	Comments   []*commentDTO `json:"comments"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+} // @name listCommentsResponse
+
+// @ID           ListComments
+// @Summary      ListComments provides the logic for retrieving one level of the post comments thread page by page, oldest first. Top-level comments are returned by default, replies are retrieved by parentId. Only approved comments are listed.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        parentId query string false "Only replies to this comment"
+// @Param        limit query int false "Max number of comments on the page (1-100, 20 by default)"
+// @Param        cursor query string false "Cursor from the nextCursor of the previous page"
+// @Success      200 {object} listCommentsResponse
+// @Failure      400,404,422,500 {object} httpErr
+// @Router       /posts/{id}/comments [GET]
+func (ctrl *commentController) list(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("list")
+
+	var pathParams listCommentsPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var queryParams listCommentsQueryParams
+	err = c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	result, err := ctrl.services.Comment.List(c, service.ListCommentsOpt{
+		PostID:   pathParams.PostID,
+		ParentID: queryParams.ParentID,
+		Limit:    queryParams.Limit,
+		Cursor:   queryParams.Cursor,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list comments", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list comments"}
+	}
+
+	commentsDTO := make([]*commentDTO, 0, len(result.Comments))
+	for _, comment := range result.Comments {
+		commentsDTO = append(commentsDTO, toCommentDTO(&comment))
+	}
+
+	logger.Info("successfully listed comments", "comments", commentsDTO)
+	return listCommentsResponse{Comments: commentsDTO, NextCursor: result.NextCursor}, nil
+}
+
+type createCommentPathParams struct {
+	PostID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name createCommentPathParams
+
+type createCommentBody struct {
+	// ParentID is the comment the reply answers, top-level comments are created without it
+	ParentID   *string `json:"parentId" binding:"omitempty,uuid"`
+	AuthorName string  `json:"authorName" binding:"required,max=50"`
+	Content    string  `json:"content" binding:"required,max=1000"`
+} // @name createCommentBody
+
+type createCommentResponse struct {
+	Comment *commentDTO `json:"comment"`
+	// Token is required in the X-Comment-Token header to edit or delete the comment, it's given only once
+	Token string `json:"token"`
+} // @name createCommentResponse
+
+// @ID           CreateComment
+// @Summary      CreateComment provides the logic for commenting a post or replying to a comment. The returned token is the only way to edit or delete the comment later. Comments of pre-moderated posts are pending until a moderator approves them.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        fields body createCommentBody true "data"
+// @Success      200 {object} createCommentResponse
+// @Failure      404,422,500 {object} httpErr
+// @Router       /posts/{id}/comments [POST]
+func (ctrl *commentController) create(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("create")
+
+	var pathParams createCommentPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var body createCommentBody
+	err = c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	result, err := ctrl.services.Comment.Create(c, service.CreateCommentOpt{
+		PostID:     pathParams.PostID,
+		ParentID:   body.ParentID,
+		AuthorName: body.AuthorName,
+		Content:    body.Content,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to create comment", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to create comment"}
+	}
+
+	logger.Info("successfully created comment", "comment", result.Comment)
+	return createCommentResponse{Comment: toCommentDTO(result.Comment), Token: result.Token}, nil
+}
+
+type updateCommentPathParams struct {
+	PostID    string `uri:"id" json:"id" binding:"required,uuid"`
+	CommentID string `uri:"commentId" json:"commentId" binding:"required,uuid"`
+} // @name updateCommentPathParams
+
+type updateCommentBody struct {
+	Content string `json:"content" binding:"required,max=1000"`
+} // @name updateCommentBody
+
+type updateCommentResponse struct {
+	Comment *commentDTO `json:"comment"`
+} // @name updateCommentResponse
+
+// @ID           UpdateComment
+// @Summary      UpdateComment provides the logic for editing own comment, it's identified by the token given on its creation. Edited comments of pre-moderated posts are pending again.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        commentId path string true "Comment ID"
+// @Param        X-Comment-Token header string true "Token of the comment"
+// @Param        fields body updateCommentBody true "data"
+// @Success      200 {object} updateCommentResponse
+// @Failure      403,404,422,500 {object} httpErr
+// @Router       /posts/{id}/comments/{commentId} [PUT]
+func (ctrl *commentController) update(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("update")
+
+	var pathParams updateCommentPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var body updateCommentBody
+	err = c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	updatedComment, err := ctrl.services.Comment.Update(c, service.UpdateCommentOpt{
+		PostID:  pathParams.PostID,
+		ID:      pathParams.CommentID,
+		Token:   c.GetHeader(commentTokenHeader),
+		Content: body.Content,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to update comment", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to update comment"}
+	}
+
+	logger.Info("successfully updated comment", "updatedComment", updatedComment)
+	return updateCommentResponse{toCommentDTO(updatedComment)}, nil
+}
+
+type deleteCommentPathParams struct {
+	PostID    string `uri:"id" json:"id" binding:"required,uuid"`
+	CommentID string `uri:"commentId" json:"commentId" binding:"required,uuid"`
+} // @name deleteCommentPathParams
+
+type deleteCommentResponse struct {
+} // @name deleteCommentResponse
+
+// @ID           DeleteComment
+// @Summary      DeleteComment provides the logic for deleting own comment, it's identified by the token given on its creation. Replies to the comment stay.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        commentId path string true "Comment ID"
+// @Param        X-Comment-Token header string true "Token of the comment"
+// @Success      200 {object} deleteCommentResponse
+// @Failure      403,404,422,500 {object} httpErr
+// @Router       /posts/{id}/comments/{commentId} [DELETE]
+func (ctrl *commentController) delete(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("delete")
+
+	var pathParams deleteCommentPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	err = ctrl.services.Comment.Delete(c, service.DeleteCommentOpt{
+		PostID: pathParams.PostID,
+		ID:     pathParams.CommentID,
+		Token:  c.GetHeader(commentTokenHeader),
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to delete comment", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to delete comment"}
+	}
+
+	logger.Info("successfully deleted comment")
+	return deleteCommentResponse{}, nil
+}
@@ -2,9 +2,12 @@ package httpcontroller
 
 import (
 	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
 	"darkness8129/news-api/packages/logging"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -14,24 +17,45 @@ type Options struct {
 	Router   *gin.Engine
 	Services service.Services
 	Logger   logging.Logger
+	// ProblemDetails renders all errors as RFC 7807 problem documents, otherwise only for clients accepting them
+	ProblemDetails bool
+	// RequireIfMatch makes If-Match header mandatory for modifications of resources with versions
+	RequireIfMatch bool
+	// MaxBatchSize limits the number of operations in one batch request
+	MaxBatchSize int
 }
 
 type controllerOptions struct {
-	RouterGroup *gin.RouterGroup
-	Services    service.Services
-	Logger      logging.Logger
+	RouterGroup    *gin.RouterGroup
+	Services       service.Services
+	Logger         logging.Logger
+	RequireIfMatch bool
+	MaxBatchSize   int
 }
 
 func New(opt Options) {
-	opt.Router.Use(gin.Logger(), gin.Recovery(), corsMiddleware)
+	// services get the gin context, the fallback makes values of the request context like the principal visible to them
+	opt.Router.ContextWithFallback = true
+	opt.Router.Use(gin.Logger(), gin.Recovery(), corsMiddleware, problemDetailsMiddleware(opt.ProblemDetails))
 
+	logger := opt.Logger.Named("httpController")
 	controllerOpt := controllerOptions{
-		RouterGroup: opt.Router.Group("/api/v1"),
-		Services:    opt.Services,
-		Logger:      opt.Logger.Named("httpController"),
+		RouterGroup:    opt.Router.Group("/api/v1", authMiddleware(opt.Services, logger)),
+		Services:       opt.Services,
+		Logger:         logger,
+		RequireIfMatch: opt.RequireIfMatch,
+		MaxBatchSize:   opt.MaxBatchSize,
 	}
 
 	newPostController(controllerOpt)
+	newTagController(controllerOpt)
+	newCategoryController(controllerOpt)
+	newAuthorController(controllerOpt)
+	newCommentController(controllerOpt)
+	newModerationController(controllerOpt)
+	newAuthController(controllerOpt)
+	newUserController(controllerOpt)
+	newAPIKeyController(controllerOpt)
 	newDocsController(controllerOpt)
 	// other controllers should be here
 }
@@ -49,9 +73,22 @@ func corsMiddleware(c *gin.Context) {
 	c.Next()
 }
 
+const problemDetailsKey = "problemDetails"
+
+// problemDetailsMiddleware decides whether errors of the request are rendered as problem documents
+func problemDetailsMiddleware(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accepted := strings.Contains(c.GetHeader("Accept"), problemDetailsContentType)
+		c.Set(problemDetailsKey, enabled || accepted)
+
+		c.Next()
+	}
+}
+
 // httpErr provides a base error type for all http controller errors
 type httpErr struct {
 	Type             httpErrType            `json:"-"`
+	Kind             errs.Kind              `json:"-"`
 	Code             string                 `json:"code,omitempty"`
 	Message          string                 `json:"message"`
 	Details          interface{}            `json:"details,omitempty"`
@@ -83,16 +120,13 @@ func errorDecorator(logger logging.Logger, handler func(c *gin.Context) (interfa
 
 		body, err := handler(c)
 		if err != nil {
-			if err.Type == httpErrTypeServer {
-				logger.Error("internal server error", "err", err)
-				c.AbortWithStatusJSON(http.StatusInternalServerError, err)
-			} else {
-				handleValidationErrors(err)
-
-				logger.Info("expected client error", "err", err)
-				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, err)
-			}
+			abortWithHTTPErr(c, logger, err)
+			return
+		}
 
+		// handlers write some responses themselves, e.g. 304 Not Modified or redirects
+		if c.Writer.Written() {
+			logger.Info("successfully handled request without body")
 			return
 		}
 
@@ -101,6 +135,57 @@ func errorDecorator(logger logging.Logger, handler func(c *gin.Context) (interfa
 	}
 }
 
+// abortWithHTTPErr renders the error in the format requested by the client and stops the request handling
+func abortWithHTTPErr(c *gin.Context, logger logging.Logger, err *httpErr) {
+	status := http.StatusInternalServerError
+	if err.Type == httpErrTypeServer {
+		logger.Error("internal server error", "err", err)
+	} else {
+		handleValidationErrors(err)
+		status = clientErrStatus(err)
+		// 401 responses must tell how to authenticate, handlers may set a more specific challenge
+		if status == http.StatusUnauthorized && c.Writer.Header().Get("WWW-Authenticate") == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+		}
+
+		logger.Info("expected client error", "err", err)
+	}
+
+	if c.GetBool(problemDetailsKey) {
+		c.Header("Content-Type", problemDetailsContentType)
+		c.AbortWithStatusJSON(status, newProblemDetails(c, status, err))
+	} else {
+		c.AbortWithStatusJSON(status, err)
+	}
+}
+
+// clientErrStatus returns a status code for the kind of the client error,
+// errors without kind are request validation errors
+func clientErrStatus(err *httpErr) int {
+	switch err.Kind {
+	case errs.KindInvalid:
+		return http.StatusBadRequest
+	case errs.KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case errs.KindUnauthorized:
+		return http.StatusUnauthorized
+	case errs.KindForbidden:
+		return http.StatusForbidden
+	case errs.KindNotFound:
+		return http.StatusNotFound
+	case errs.KindConflict:
+		return http.StatusConflict
+	case errs.KindRateLimited:
+		return http.StatusTooManyRequests
+	case errs.KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case errs.KindPreconditionRequired:
+		return http.StatusPreconditionRequired
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
 func handleValidationErrors(err *httpErr) {
 	// checking whether validation errors exist
 	validationErrors, ok := err.Details.(validator.ValidationErrors)
@@ -113,12 +198,28 @@ func handleValidationErrors(err *httpErr) {
 	for _, e := range validationErrors {
 		fieldName := e.Field()
 		switch e.Tag() {
-		case "required":
+		case "required", "required_if", "required_unless":
 			err.ValidationErrors[fieldName] = "field is required"
 		case "max":
-			err.ValidationErrors[fieldName] = "maximum allowed characters exceeded"
+			if e.Kind() == reflect.String {
+				err.ValidationErrors[fieldName] = "maximum allowed characters exceeded"
+			} else {
+				err.ValidationErrors[fieldName] = "maximum allowed value exceeded"
+			}
+		case "min":
+			if e.Kind() == reflect.String {
+				err.ValidationErrors[fieldName] = "minimum allowed characters not reached"
+			} else {
+				err.ValidationErrors[fieldName] = "minimum allowed value not reached"
+			}
 		case "uuid":
 			err.ValidationErrors[fieldName] = "invalid ID"
+		case "url":
+			err.ValidationErrors[fieldName] = "invalid URL"
+		case "email":
+			err.ValidationErrors[fieldName] = "invalid email"
+		case "oneof":
+			err.ValidationErrors[fieldName] = fmt.Sprintf("unknown value, allowed values: %s", e.Param())
 		default:
 			err.ValidationErrors[fieldName] = "unknown validation error"
 		}
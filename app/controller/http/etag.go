@@ -0,0 +1,58 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/packages/errs"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const anyETag = "*"
+
+// versionETag returns a strong ETag for the version of a resource
+func versionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ifMatchVersion returns the version from If-Match header, 0 is returned for "*" and the missing header.
+// Only a single strong ETag is supported, since a resource has one current version.
+func ifMatchVersion(c *gin.Context, required bool) (int, *httpErr) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" {
+		if required {
+			return 0, &httpErr{Type: httpErrTypeClient, Message: "If-Match header is required", Kind: errs.KindPreconditionRequired}
+		}
+
+		return 0, nil
+	}
+
+	if ifMatch == anyETag {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(ifMatch, `"`), `"`))
+	if err != nil || version < 1 || versionETag(version) != ifMatch {
+		return 0, &httpErr{Type: httpErrTypeClient, Message: "invalid If-Match header", Kind: errs.KindInvalid}
+	}
+
+	return version, nil
+}
+
+// ifNoneMatch reports whether If-None-Match header matches the ETag, weak comparison is used as for GET requests
+func ifNoneMatch(c *gin.Context, etag string) bool {
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == anyETag || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
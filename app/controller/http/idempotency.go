@@ -0,0 +1,159 @@
+package httpcontroller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyClientIDPrefix = "ip:"
+	idempotencyUserIDPrefix   = "user:"
+	idempotencyAPIKeyIDPrefix = "api-key:"
+)
+
+// replayedHeaders are response headers stored with the response and sent again on replays
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// idempotencyMiddleware replays the stored response for requests repeated with the same Idempotency-Key header,
+// requests without the header are handled as usual. Only responses without server errors are stored,
+// so failed requests can be retried.
+func idempotencyMiddleware(services service.Services, logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := logger.Named("idempotencyMiddleware")
+
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			logger.Info("too long idempotency key", "length", len(key))
+			abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeClient, Message: "too long idempotency key", Kind: errs.KindInvalid})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.Info("failed to read request body", "err", err)
+			abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		clientID := idempotencyClientID(c)
+		storedKey, err := services.Idempotency.Begin(c, service.BeginIdempotentRequestOpt{
+			ClientID:    clientID,
+			Key:         key,
+			RequestHash: idempotentRequestHash(c, body),
+		})
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)})
+				return
+			}
+
+			logger.Error("failed to begin idempotent request", "err", err)
+			abortWithHTTPErr(c, logger, &httpErr{Type: httpErrTypeServer, Message: "failed to begin idempotent request"})
+			return
+		}
+
+		if storedKey != nil {
+			for header, values := range storedKey.ResponseHeader {
+				for _, v := range values {
+					c.Writer.Header().Add(header, v)
+				}
+			}
+			c.Header(idempotentReplayedHeader, "true")
+			c.Data(storedKey.ResponseStatus, c.Writer.Header().Get("Content-Type"), storedKey.ResponseBody)
+			c.Abort()
+
+			logger.Info("replayed stored response", "clientID", clientID, "key", key)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// the response is already sent, so the key is stored even if the client has gone
+		ctx := context.WithoutCancel(c.Request.Context())
+		if recorder.Status() >= http.StatusInternalServerError {
+			err := services.Idempotency.Release(ctx, clientID, key)
+			if err != nil {
+				logger.Error("failed to release idempotency key", "err", err)
+			}
+
+			return
+		}
+
+		header := make(map[string][]string)
+		for _, h := range replayedHeaders {
+			if values := recorder.Header().Values(h); len(values) > 0 {
+				header[h] = values
+			}
+		}
+
+		err = services.Idempotency.Complete(ctx, service.CompleteIdempotentRequestOpt{
+			ClientID:       clientID,
+			Key:            key,
+			ResponseStatus: recorder.Status(),
+			ResponseHeader: header,
+			ResponseBody:   recorder.body.Bytes(),
+		})
+		if err != nil {
+			logger.Error("failed to complete idempotent request", "err", err)
+		}
+	}
+}
+
+// idempotencyClientID identifies the client the key belongs to, so clients can't replay responses to each other.
+// Keys of signed in users are shared by all their devices, keys of API keys are shared by all their clients.
+func idempotencyClientID(c *gin.Context) string {
+	if p := principal(c); p != nil {
+		if p.APIKeyID != "" {
+			return idempotencyAPIKeyIDPrefix + p.APIKeyID
+		}
+
+		return idempotencyUserIDPrefix + p.UserID
+	}
+
+	return idempotencyClientIDPrefix + c.ClientIP()
+}
+
+// idempotentRequestHash identifies the request the key is used for
+func idempotentRequestHash(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies the response body, while it's written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,394 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type moderationController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newModerationController(opt controllerOptions) {
+	logger := opt.Logger.Named("moderationController")
+
+	c := moderationController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/moderation")
+	group.GET("comments", errorDecorator(logger, c.listComments))
+	group.POST("comments/:id/approve", errorDecorator(logger, c.approveComment))
+	group.POST("comments/:id/reject", errorDecorator(logger, c.rejectComment))
+	group.GET("comments/:id/history", errorDecorator(logger, c.commentHistory))
+	group.GET("posts/:id/policy", errorDecorator(logger, c.getPostPolicy))
+	group.PUT("posts/:id/policy", errorDecorator(logger, c.setPostPolicy))
+}
+
+// moderatedCommentDTO shows moderators the last decision on the comment
+type moderatedCommentDTO struct {
+	*commentDTO
+	ModerationReason string     `json:"moderationReason,omitempty"`
+	ModeratedAt      *time.Time `json:"moderatedAt,omitempty"`
+} // @name ModeratedComment
+
+func toModeratedCommentDTO(c *entity.Comment) *moderatedCommentDTO {
+	return &moderatedCommentDTO{
+		commentDTO:       toCommentDTO(c),
+		ModerationReason: c.ModerationReason,
+		ModeratedAt:      c.ModeratedAt,
+	}
+}
+
+type listModerationCommentsQueryParams struct {
+	Status string `form:"status" json:"status" binding:"omitempty,oneof=pending approved rejected spam"`
+	PostID string `form:"postId" json:"postId" binding:"omitempty,uuid"`
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" json:"cursor"`
+} // @name listModerationCommentsQueryParams
+
+type listModerationCommentsResponse struct {
+	Comments   []*moderatedCommentDTO `json:"comments"`
+	NextCursor string                 `json:"nextCursor,omitempty"`
+} // @name listModerationCommentsResponse
+
+// @ID           ListModerationComments
+// @Summary      ListModerationComments provides the logic for retrieving the moderation queue, comments of all posts and thread levels with the status page by page, oldest first. Pending comments are returned by default, deleted ones aren't returned.
+// @Produce      application/json
+// @Param        status query string false "Status of the comments" Enums(pending, approved, rejected, spam)
+// @Param        postId query string false "Only comments of this post"
+// @Param        limit query int false "Max number of comments on the page (1-100, 20 by default)"
+// @Param        cursor query string false "Cursor from the nextCursor of the previous page"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} listModerationCommentsResponse
+// @Failure      400,401,403,422,500 {object} httpErr
+// @Router       /moderation/comments [GET]
+func (ctrl *moderationController) listComments(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("listComments")
+
+	var queryParams listModerationCommentsQueryParams
+	err := c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	result, err := ctrl.services.Moderation.ListComments(c, service.ListModerationCommentsOpt{
+		Status: entity.CommentStatus(queryParams.Status),
+		PostID: queryParams.PostID,
+		Limit:  queryParams.Limit,
+		Cursor: queryParams.Cursor,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list comments", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list comments"}
+	}
+
+	commentsDTO := make([]*moderatedCommentDTO, 0, len(result.Comments))
+	for _, comment := range result.Comments {
+		commentsDTO = append(commentsDTO, toModeratedCommentDTO(&comment))
+	}
+
+	logger.Info("successfully listed comments", "comments", commentsDTO)
+	return listModerationCommentsResponse{Comments: commentsDTO, NextCursor: result.NextCursor}, nil
+}
+
+type moderateCommentPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name moderateCommentPathParams
+
+type approveCommentBody struct {
+	Reason string `json:"reason" binding:"max=500"`
+} // @name approveCommentBody
+
+type rejectCommentBody struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+	// Spam marks the comment as spam instead of rejecting it
+	Spam bool `json:"spam"`
+} // @name rejectCommentBody
+
+type moderateCommentResponse struct {
+	Comment *moderatedCommentDTO `json:"comment"`
+} // @name moderateCommentResponse
+
+// @ID           ApproveComment
+// @Summary      ApproveComment provides the logic for making a comment public, the decision is recorded in the comment moderation history.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "Comment ID"
+// @Param        fields body approveCommentBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} moderateCommentResponse
+// @Failure      400,401,403,404,422,500 {object} httpErr
+// @Router       /moderation/comments/{id}/approve [POST]
+func (ctrl *moderationController) approveComment(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("approveComment")
+
+	var pathParams moderateCommentPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var body approveCommentBody
+	err = c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	return ctrl.moderateComment(c, logger, service.ModerateCommentOpt{
+		ID:     pathParams.ID,
+		Status: entity.CommentStatusApproved,
+		Reason: body.Reason,
+	})
+}
+
+// @ID           RejectComment
+// @Summary      RejectComment provides the logic for hiding a comment or marking it as spam. The reason is required, it's recorded in the comment moderation history for audit.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "Comment ID"
+// @Param        fields body rejectCommentBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} moderateCommentResponse
+// @Failure      400,401,403,404,422,500 {object} httpErr
+// @Router       /moderation/comments/{id}/reject [POST]
+func (ctrl *moderationController) rejectComment(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("rejectComment")
+
+	var pathParams moderateCommentPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var body rejectCommentBody
+	err = c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	status := entity.CommentStatusRejected
+	if body.Spam {
+		status = entity.CommentStatusSpam
+	}
+
+	return ctrl.moderateComment(c, logger, service.ModerateCommentOpt{
+		ID:     pathParams.ID,
+		Status: status,
+		Reason: body.Reason,
+	})
+}
+
+// moderateComment is shared by approve and reject actions
+func (ctrl *moderationController) moderateComment(c *gin.Context, logger logging.Logger, opt service.ModerateCommentOpt) (interface{}, *httpErr) {
+	moderatedComment, err := ctrl.services.Moderation.ModerateComment(c, opt)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to moderate comment", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to moderate comment"}
+	}
+
+	logger.Info("successfully moderated comment", "moderatedComment", moderatedComment)
+	return moderateCommentResponse{toModeratedCommentDTO(moderatedComment)}, nil
+}
+
+type commentHistoryPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name commentHistoryPathParams
+
+type commentModerationDTO struct {
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+} // @name CommentModeration
+
+type commentHistoryResponse struct {
+	Moderations []*commentModerationDTO `json:"moderations"`
+} // @name commentHistoryResponse
+
+// @ID           CommentHistory
+// @Summary      CommentHistory provides the logic for retrieving all moderation decisions on a comment with their reasons, the newest first.
+// @Produce      application/json
+// @Param        id path string true "Comment ID"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} commentHistoryResponse
+// @Failure      401,403,404,422,500 {object} httpErr
+// @Router       /moderation/comments/{id}/history [GET]
+func (ctrl *moderationController) commentHistory(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("commentHistory")
+
+	var pathParams commentHistoryPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	moderations, err := ctrl.services.Moderation.CommentHistory(c, pathParams.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get comment history", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get comment history"}
+	}
+
+	moderationsDTO := make([]*commentModerationDTO, 0, len(moderations))
+	for _, m := range moderations {
+		moderationsDTO = append(moderationsDTO, &commentModerationDTO{
+			Status:    string(m.Status),
+			Reason:    m.Reason,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+
+	logger.Info("successfully got comment history", "moderations", moderationsDTO)
+	return commentHistoryResponse{moderationsDTO}, nil
+}
+
+type postPolicyPathParams struct {
+	PostID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name postPolicyPathParams
+
+type postModerationPolicyDTO struct {
+	// Premoderation is the policy of the post, null means the global one
+	Premoderation *bool `json:"premoderation"`
+	// Effective tells whether new comments of the post wait for approval
+	Effective bool `json:"effective"`
+} // @name PostModerationPolicy
+
+func toPostModerationPolicyDTO(p *service.PostModerationPolicy) *postModerationPolicyDTO {
+	return &postModerationPolicyDTO{
+		Premoderation: p.Premoderation,
+		Effective:     p.Effective,
+	}
+}
+
+type getPostPolicyResponse struct {
+	Policy *postModerationPolicyDTO `json:"policy"`
+} // @name getPostPolicyResponse
+
+// @ID           GetPostModerationPolicy
+// @Summary      GetPostModerationPolicy provides the logic for retrieving the pre-moderation policy of the post comments.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} getPostPolicyResponse
+// @Failure      401,403,404,422,500 {object} httpErr
+// @Router       /moderation/posts/{id}/policy [GET]
+func (ctrl *moderationController) getPostPolicy(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("getPostPolicy")
+
+	var pathParams postPolicyPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	policy, err := ctrl.services.Moderation.GetPostPolicy(c, pathParams.PostID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get post policy", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get post policy"}
+	}
+
+	logger.Info("successfully got post policy", "policy", policy)
+	return getPostPolicyResponse{toPostModerationPolicyDTO(policy)}, nil
+}
+
+type setPostPolicyBody struct {
+	// Premoderation null makes the post follow the global policy
+	Premoderation *bool `json:"premoderation"`
+} // @name setPostPolicyBody
+
+type setPostPolicyResponse struct {
+	Policy *postModerationPolicyDTO `json:"policy"`
+} // @name setPostPolicyResponse
+
+// @ID           SetPostModerationPolicy
+// @Summary      SetPostModerationPolicy provides the logic for overriding the global pre-moderation policy for the post comments. It affects comments created or edited afterwards.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        fields body setPostPolicyBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} setPostPolicyResponse
+// @Failure      401,403,404,422,500 {object} httpErr
+// @Router       /moderation/posts/{id}/policy [PUT]
+func (ctrl *moderationController) setPostPolicy(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("setPostPolicy")
+
+	var pathParams postPolicyPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var body setPostPolicyBody
+	err = c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	policy, err := ctrl.services.Moderation.SetPostPolicy(c, service.SetPostModerationPolicyOpt{
+		PostID:        pathParams.PostID,
+		Premoderation: body.Premoderation,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to set post policy", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to set post policy"}
+	}
+
+	logger.Info("successfully set post policy", "policy", policy)
+	return setPostPolicyResponse{toPostModerationPolicyDTO(policy)}, nil
+}
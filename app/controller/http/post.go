@@ -1,52 +1,127 @@
 package httpcontroller
 
 import (
+	"bytes"
+	"context"
 	"darkness8129/news-api/app/entity"
 	"darkness8129/news-api/app/service"
 	"darkness8129/news-api/packages/errs"
 	"darkness8129/news-api/packages/logging"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type postController struct {
-	services service.Services
-	logger   logging.Logger
+	services       service.Services
+	logger         logging.Logger
+	requireIfMatch bool
+	maxBatchSize   int
 }
 
 func newPostController(opt controllerOptions) {
 	logger := opt.Logger.Named("postController")
 
 	c := postController{
-		services: opt.Services,
-		logger:   logger,
+		services:       opt.Services,
+		logger:         logger,
+		requireIfMatch: opt.RequireIfMatch,
+		maxBatchSize:   opt.MaxBatchSize,
 	}
 
+	// gin can't register a path with a literal colon, so custom methods of the collection
+	// like /posts:batch are registered as a param which is checked by the handler
+	opt.RouterGroup.POST("/posts:action", idempotencyMiddleware(opt.Services, logger), errorDecorator(logger, c.collectionAction))
+
 	group := opt.RouterGroup.Group("/posts")
-	group.POST("", errorDecorator(logger, c.create))
+	group.POST("", idempotencyMiddleware(opt.Services, logger), errorDecorator(logger, c.create))
 	group.GET("", errorDecorator(logger, c.list))
+	group.GET("search", errorDecorator(logger, c.search))
+	group.GET("trash", errorDecorator(logger, c.listTrash))
+	group.GET("by-slug/:slug", errorDecorator(logger, c.getBySlug))
 	group.GET(":id", errorDecorator(logger, c.get))
 	group.PUT(":id", errorDecorator(logger, c.update))
+	group.PATCH(":id", errorDecorator(logger, c.patch))
 	group.DELETE(":id", errorDecorator(logger, c.delete))
+	group.POST(":id/restore", errorDecorator(logger, c.restore))
+	group.POST(":id/publish", errorDecorator(logger, c.publish))
+	group.POST(":id/unpublish", errorDecorator(logger, c.unpublish))
+	group.POST(":id/archive", errorDecorator(logger, c.archive))
+	group.GET(":id/revisions", errorDecorator(logger, c.listRevisions))
+	group.GET(":id/revisions/diff", errorDecorator(logger, c.diffRevisions))
+	group.GET(":id/revisions/:rev", errorDecorator(logger, c.getRevision))
+	group.POST(":id/revisions/:rev/restore", errorDecorator(logger, c.restoreRevision))
 }
 
 type postDTO struct {
-	ID      string `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Slug        string     `json:"slug"`
+	Version     int        `json:"version"`
+	Status      string     `json:"status" enums:"draft,published,archived"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	CategoryID  *string    `json:"categoryId,omitempty"`
+	// OwnerID is the user who created the post, authors can edit only their own posts
+	OwnerID *string `json:"ownerId,omitempty"`
+	// Authors are bylines of the post in their order
+	Authors       []*authorSummaryDTO `json:"authors"`
+	Tags          []string            `json:"tags"`
+	CommentsCount int                 `json:"commentsCount"`
 } // @name Post
 
 func ToPostDTO(p *entity.Post) *postDTO {
-	return &postDTO{
-		ID:      p.ID,
-		Title:   p.Title,
-		Content: p.Content,
+	dto := &postDTO{
+		ID:            p.ID,
+		Title:         p.Title,
+		Content:       p.Content,
+		Slug:          p.Slug,
+		Version:       p.Version,
+		Status:        string(p.Status),
+		PublishedAt:   p.PublishedAt,
+		PublishAt:     p.PublishAt,
+		CategoryID:    p.CategoryID,
+		OwnerID:       p.OwnerID,
+		Authors:       make([]*authorSummaryDTO, 0, len(p.Authors)),
+		Tags:          make([]string, 0, len(p.Tags)),
+		CommentsCount: p.CommentsCount,
+	}
+	if p.DeletedAt.Valid {
+		dto.DeletedAt = &p.DeletedAt.Time
+	}
+	for _, postAuthor := range p.Authors {
+		if postAuthor.Author != nil {
+			dto.Authors = append(dto.Authors, toAuthorSummaryDTO(postAuthor.Author))
+		}
 	}
+	for _, tag := range p.Tags {
+		dto.Tags = append(dto.Tags, tag.Name)
+	}
+
+	return dto
 }
 
 type createPostBody struct {
 	Title   string `json:"title" binding:"required,max=50"`
 	Content string `json:"content" binding:"required,max=200"`
+	// PublishAt schedules publishing of the draft, must be in the future
+	PublishAt  *time.Time `json:"publishAt"`
+	CategoryID *string    `json:"categoryId" binding:"omitempty,uuid"`
+	// AuthorIDs are bylines of the post in their order
+	AuthorIDs []string `json:"authorIds" binding:"max=10,dive,uuid"`
+	// Tags are case insensitive, missing tags are created
+	Tags []string `json:"tags" binding:"max=10,dive,required,max=30"`
 } // @name createPostBody
 
 type createPostResponse struct {
@@ -54,12 +129,17 @@ type createPostResponse struct {
 } // @name createPostResponse
 
 // @ID           CreatePost
-// @Summary      CreatePost provides the logic for creating a post with passed data.
+// @Summary      CreatePost provides the logic for creating a draft post with passed data, optionally scheduled for publishing.
 // @Accept       application/json
 // @Produce      application/json
 // @Param        fields body createPostBody true "data"
+// @Param        Idempotency-Key header string false "Unique key of the request, the response is replayed for retries with the same key and body"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Success      200 {object} createPostResponse
-// @Failure      422,500 {object} httpErr
+// @Header       200 {string} ETag "version of the post"
+// @Header       200 {string} Idempotent-Replayed "true if the response is a replay of the stored one"
+// @Failure      400,401,403,409,422,500 {object} httpErr
 // @Router       /posts [POST]
 func (ctrl *postController) create(c *gin.Context) (interface{}, *httpErr) {
 	logger := ctrl.logger.Named("create")
@@ -73,13 +153,17 @@ func (ctrl *postController) create(c *gin.Context) (interface{}, *httpErr) {
 	logger.Debug("parsed request body", "body", body)
 
 	post, err := ctrl.services.Post.Create(c, service.CreatePostOpt{
-		Title:   body.Title,
-		Content: body.Content,
+		Title:      body.Title,
+		Content:    body.Content,
+		PublishAt:  body.PublishAt,
+		CategoryID: body.CategoryID,
+		AuthorIDs:  body.AuthorIDs,
+		Tags:       body.Tags,
 	})
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
-			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err)}
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
 		}
 
 		logger.Error("failed to create post", "err", err)
@@ -87,40 +171,406 @@ func (ctrl *postController) create(c *gin.Context) (interface{}, *httpErr) {
 	}
 
 	logger.Info("successfully created post", "post", post)
+	c.Header("ETag", versionETag(post.Version))
 	return createPostResponse{ToPostDTO(post)}, nil
 }
 
+// collectionAction dispatches custom methods of the posts collection, the action param includes the colon
+func (ctrl *postController) collectionAction(c *gin.Context) (interface{}, *httpErr) {
+	switch c.Param("action") {
+	case ":batch":
+		return ctrl.batch(c)
+	default:
+		ctrl.logger.Named("collectionAction").Info("unknown action", "action", c.Param("action"))
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "not found", Kind: errs.KindNotFound}
+	}
+}
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "bestEffort"
+)
+
+type batchPostsBody struct {
+	// Mode is atomic by default, nothing is changed then if any operation fails
+	Mode       string               `json:"mode" binding:"omitempty,oneof=atomic bestEffort" enums:"atomic,bestEffort"`
+	Operations []batchPostOperation `json:"operations" binding:"required,min=1"`
+} // @name batchPostsBody
+
+type batchPostOperation struct {
+	Op string `json:"op" binding:"required,oneof=create update delete" enums:"create,update,delete"`
+	// ID is required for update and delete
+	ID string `json:"id" binding:"required_unless=Op create,omitempty,uuid"`
+	// Title and Content are required for create, they are left as they are by update if omitted
+	Title      *string    `json:"title" binding:"required_if=Op create,omitempty,max=50"`
+	Content    *string    `json:"content" binding:"required_if=Op create,omitempty,max=200"`
+	PublishAt  *time.Time `json:"publishAt"`
+	CategoryID *string    `json:"categoryId" binding:"omitempty,uuid"`
+	// AuthorIDs replace bylines of the updated post, they are left as they are if omitted
+	AuthorIDs []string `json:"authorIds" binding:"max=10,dive,uuid"`
+	// Tags replace tags of the updated post, they are left as they are if omitted
+	Tags []string `json:"tags" binding:"max=10,dive,required,max=30"`
+	// Version is the expected current version of the updated or deleted post, 0 skips the check
+	Version int `json:"version" binding:"min=0"`
+} // @name batchPostOperation
+
+func (op batchPostOperation) toPostOperation() service.PostOperation {
+	operation := service.PostOperation{
+		Type:    service.PostOperationType(op.Op),
+		ID:      op.ID,
+		Version: op.Version,
+	}
+
+	switch operation.Type {
+	case service.PostOperationCreate:
+		operation.Create = service.CreatePostOpt{
+			Title:      *op.Title,
+			Content:    *op.Content,
+			PublishAt:  op.PublishAt,
+			CategoryID: op.CategoryID,
+			AuthorIDs:  op.AuthorIDs,
+			Tags:       op.Tags,
+		}
+	case service.PostOperationUpdate:
+		operation.Update = service.UpdatePostOpt{
+			Title:      op.Title,
+			Content:    op.Content,
+			PublishAt:  op.PublishAt,
+			CategoryID: op.CategoryID,
+			AuthorIDs:  op.AuthorIDs,
+			Tags:       op.Tags,
+			Version:    op.Version,
+		}
+	}
+
+	return operation
+}
+
+type batchPostResult struct {
+	// Status is the status code the operation would have as a separate request
+	Status int `json:"status"`
+	// Post is omitted for delete and failed operations
+	Post  *postDTO `json:"post,omitempty"`
+	Error *httpErr `json:"error,omitempty"`
+} // @name batchPostResult
+
+type batchPostsResponse struct {
+	// Results are in the order of the operations
+	Results []batchPostResult `json:"results"`
+} // @name batchPostsResponse
+
+// @ID           BatchPosts
+// @Summary      BatchPosts provides the logic for creating, updating and deleting posts in one request.
+// @Description  In atomic mode all operations are applied in one transaction, the first failed operation is returned as an error with its index in details.
+// @Description  In bestEffort mode every operation is applied on its own and its error is returned in its result.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        fields body batchPostsBody true "data"
+// @Param        Idempotency-Key header string false "Unique key of the request, the response is replayed for retries with the same key and body"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} batchPostsResponse
+// @Header       200 {string} Idempotent-Replayed "true if the response is a replay of the stored one"
+// @Failure      400,401,403,404,409,412,422,500 {object} httpErr
+// @Router       /posts:batch [POST]
+func (ctrl *postController) batch(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("batch")
+
+	var body batchPostsBody
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	if len(body.Operations) > ctrl.maxBatchSize {
+		logger.Info("too many operations", "count", len(body.Operations))
+		return nil, &httpErr{
+			Type:    httpErrTypeClient,
+			Message: fmt.Sprintf("batch can't have more than %d operations", ctrl.maxBatchSize),
+			Kind:    errs.KindInvalid,
+		}
+	}
+
+	atomic := body.Mode != batchModeBestEffort
+	results := make([]batchPostResult, len(body.Operations))
+	operations := make([]service.PostOperation, 0, len(body.Operations))
+	// indexes maps operations passed to the service to the operations of the body,
+	// invalid operations aren't passed in best-effort mode
+	indexes := make([]int, 0, len(body.Operations))
+	for i, op := range body.Operations {
+		err := binding.Validator.ValidateStruct(op)
+		if err != nil {
+			logger.Info("invalid operation", "index", i, "err", err)
+			opErr := &httpErr{
+				Type:    httpErrTypeClient,
+				Message: fmt.Sprintf("invalid operation %d", i),
+				Details: err,
+			}
+			if atomic {
+				return nil, opErr
+			}
+
+			handleValidationErrors(opErr)
+			results[i] = batchPostResult{Status: clientErrStatus(opErr), Error: opErr}
+			continue
+		}
+
+		operations = append(operations, op.toPostOperation())
+		indexes = append(indexes, i)
+	}
+
+	operationResults, err := ctrl.services.Post.Batch(c, service.BatchPostsOpt{Operations: operations, Atomic: atomic})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{
+				Type:    httpErrTypeClient,
+				Message: err.Error(),
+				Code:    errs.Code(err),
+				Kind:    errs.KindOf(err),
+				Details: errs.Metadata(err),
+			}
+		}
+
+		logger.Error("failed to apply post operations", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to apply post operations"}
+	}
+
+	for i, result := range operationResults {
+		results[indexes[i]] = ctrl.toBatchPostResult(result)
+	}
+
+	logger.Info("successfully applied post operations", "count", len(results))
+	return batchPostsResponse{results}, nil
+}
+
+func (ctrl *postController) toBatchPostResult(result service.PostOperationResult) batchPostResult {
+	if result.Err == nil {
+		var post *postDTO
+		if result.Post != nil {
+			post = ToPostDTO(result.Post)
+		}
+
+		return batchPostResult{Status: http.StatusOK, Post: post}
+	}
+
+	if !errs.IsCustom(result.Err) {
+		ctrl.logger.Named("toBatchPostResult").Error("failed to apply post operation", "err", result.Err)
+		return batchPostResult{
+			Status: http.StatusInternalServerError,
+			Error:  &httpErr{Type: httpErrTypeServer, Message: "failed to apply post operation"},
+		}
+	}
+
+	opErr := &httpErr{
+		Type:    httpErrTypeClient,
+		Message: result.Err.Error(),
+		Code:    errs.Code(result.Err),
+		Kind:    errs.KindOf(result.Err),
+	}
+	return batchPostResult{Status: clientErrStatus(opErr), Error: opErr}
+}
+
+type listPostsQueryParams struct {
+	CreatedFrom   *time.Time `form:"createdFrom" json:"createdFrom"`
+	CreatedTo     *time.Time `form:"createdTo" json:"createdTo"`
+	UpdatedSince  *time.Time `form:"updatedSince" json:"updatedSince"`
+	TitleContains string     `form:"titleContains" json:"titleContains" binding:"max=50"`
+	Tag           []string   `form:"tag" json:"tag" binding:"max=10,dive,required,max=30"`
+	TagMatch      string     `form:"tagMatch" json:"tagMatch" binding:"omitempty,oneof=any all"`
+	CategoryID    string     `form:"categoryId" json:"categoryId" binding:"omitempty,uuid"`
+	AuthorID      string     `form:"authorId" json:"authorId" binding:"omitempty,uuid"`
+	Sort          string     `form:"sort" json:"sort" binding:"omitempty,oneof=created_at -created_at updated_at title"`
+	Limit         int        `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor        string     `form:"cursor" json:"cursor"`
+} // @name listPostsQueryParams
+
+func (p listPostsQueryParams) toListPostsOpt() service.ListPostsOpt {
+	return service.ListPostsOpt{
+		CreatedFrom:   p.CreatedFrom,
+		CreatedTo:     p.CreatedTo,
+		UpdatedSince:  p.UpdatedSince,
+		TitleContains: p.TitleContains,
+		Tags:          p.Tag,
+		TagsMatchAll:  p.TagMatch == "all",
+		CategoryID:    p.CategoryID,
+		AuthorID:      p.AuthorID,
+		Sort:          entity.PostsSort(p.Sort),
+		Limit:         p.Limit,
+		Cursor:        p.Cursor,
+	}
+}
+
 type listPostsResponse struct {
-	Posts []*postDTO `json:"posts"`
+	Posts      []*postDTO `json:"posts"`
+	NextCursor string     `json:"nextCursor,omitempty"`
 } // @name listPostsResponse
 
 // @ID           ListPosts
-// @Summary      ListPosts provides the logic for retrieving all posts.
+// @Summary      ListPosts provides the logic for retrieving filtered published posts page by page, starting from the newest by default.
 // @Produce      application/json
+// @Param        createdFrom query string false "Only posts created at or after this time (RFC 3339)"
+// @Param        createdTo query string false "Only posts created at or before this time (RFC 3339)"
+// @Param        updatedSince query string false "Only posts updated at or after this time (RFC 3339)"
+// @Param        titleContains query string false "Only posts with the title containing this text, case insensitive"
+// @Param        tag query []string false "Only posts with these tags, the param can be repeated" collectionFormat(multi)
+// @Param        tagMatch query string false "Whether posts must have any of the tags or all of them, any by default" Enums(any, all)
+// @Param        categoryId query string false "Only posts of the category and its subcategories"
+// @Param        authorId query string false "Only posts with the author in the bylines"
+// @Param        sort query string false "Sort key, the newest first by default" Enums(created_at, -created_at, updated_at, title)
+// @Param        limit query int false "Max number of posts on the page (1-100, 20 by default)"
+// @Param        cursor query string false "Cursor from the nextCursor of the previous page"
 // @Success      200 {object} listPostsResponse
-// @Failure      422,500 {object} httpErr
+// @Failure      400,422,500 {object} httpErr
 // @Router       /posts [GET]
 func (ctrl *postController) list(c *gin.Context) (interface{}, *httpErr) {
 	logger := ctrl.logger.Named("list")
 
-	posts, err := ctrl.services.Post.List(c)
+	var queryParams listPostsQueryParams
+	err := c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	result, err := ctrl.services.Post.List(c, queryParams.toListPostsOpt())
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
-			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err)}
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
 		}
 
 		logger.Error("failed to list posts", "err", err)
 		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list posts"}
 	}
 
-	var postsDTO []*postDTO
-	for _, p := range posts {
+	postsDTO := make([]*postDTO, 0, len(result.Posts))
+	for _, p := range result.Posts {
+		postsDTO = append(postsDTO, ToPostDTO(&p))
+	}
+
+	logger.Info("successfully listed posts", "posts", result.Posts)
+	return listPostsResponse{Posts: postsDTO, NextCursor: result.NextCursor}, nil
+}
+
+// @ID           ListDeletedPosts
+// @Summary      ListDeletedPosts provides the logic for retrieving posts in the trash, with the same filters and pagination as ListPosts.
+// @Produce      application/json
+// @Param        createdFrom query string false "Only posts created at or after this time (RFC 3339)"
+// @Param        createdTo query string false "Only posts created at or before this time (RFC 3339)"
+// @Param        updatedSince query string false "Only posts updated at or after this time (RFC 3339)"
+// @Param        titleContains query string false "Only posts with the title containing this text, case insensitive"
+// @Param        tag query []string false "Only posts with these tags, the param can be repeated" collectionFormat(multi)
+// @Param        tagMatch query string false "Whether posts must have any of the tags or all of them, any by default" Enums(any, all)
+// @Param        categoryId query string false "Only posts of the category and its subcategories"
+// @Param        authorId query string false "Only posts with the author in the bylines"
+// @Param        sort query string false "Sort key, the newest first by default" Enums(created_at, -created_at, updated_at, title)
+// @Param        limit query int false "Max number of posts on the page (1-100, 20 by default)"
+// @Param        cursor query string false "Cursor from the nextCursor of the previous page"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} listPostsResponse
+// @Failure      400,401,403,422,500 {object} httpErr
+// @Router       /posts/trash [GET]
+func (ctrl *postController) listTrash(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("listTrash")
+
+	var queryParams listPostsQueryParams
+	err := c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	result, err := ctrl.services.Post.ListTrash(c, queryParams.toListPostsOpt())
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list deleted posts", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list deleted posts"}
+	}
+
+	postsDTO := make([]*postDTO, 0, len(result.Posts))
+	for _, p := range result.Posts {
 		postsDTO = append(postsDTO, ToPostDTO(&p))
 	}
 
-	logger.Info("successfully listed posts", "posts", posts)
-	return listPostsResponse{postsDTO}, nil
+	logger.Info("successfully listed deleted posts", "posts", result.Posts)
+	return listPostsResponse{Posts: postsDTO, NextCursor: result.NextCursor}, nil
+}
+
+type searchPostsQueryParams struct {
+	Q      string `form:"q" json:"q" binding:"required,max=200"`
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" json:"offset" binding:"omitempty,min=0"`
+} // @name searchPostsQueryParams
+
+type postSearchResultDTO struct {
+	Post *postDTO `json:"post"`
+	Rank float64  `json:"rank"`
+	// highlights wrap matched words with <mark></mark>
+	TitleHighlight   string `json:"titleHighlight"`
+	ContentHighlight string `json:"contentHighlight"`
+} // @name PostSearchResult
+
+type searchPostsResponse struct {
+	Results []*postSearchResultDTO `json:"results"`
+} // @name searchPostsResponse
+
+// @ID           SearchPosts
+// @Summary      SearchPosts provides the logic for full-text search of published posts, the most relevant go first.
+// @Produce      application/json
+// @Param        q query string true "Search query, quoted phrases, or and - for exclusion are supported"
+// @Param        limit query int false "Max number of results (1-100, 20 by default)"
+// @Param        offset query int false "Number of results to skip"
+// @Success      200 {object} searchPostsResponse
+// @Failure      400,422,500 {object} httpErr
+// @Router       /posts/search [GET]
+func (ctrl *postController) search(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("search")
+
+	var queryParams searchPostsQueryParams
+	err := c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	results, err := ctrl.services.Post.Search(c, service.SearchPostsOpt{
+		Query:  queryParams.Q,
+		Limit:  queryParams.Limit,
+		Offset: queryParams.Offset,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to search posts", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to search posts"}
+	}
+
+	resultsDTO := make([]*postSearchResultDTO, 0, len(results))
+	for _, r := range results {
+		resultsDTO = append(resultsDTO, &postSearchResultDTO{
+			Post:             ToPostDTO(&r.Post),
+			Rank:             r.Rank,
+			TitleHighlight:   r.TitleHighlight,
+			ContentHighlight: r.ContentHighlight,
+		})
+	}
+
+	logger.Info("successfully searched posts", "results", results)
+	return searchPostsResponse{resultsDTO}, nil
 }
 
 type getPostPathParams struct {
@@ -135,8 +585,11 @@ type getPostResponse struct {
 // @Summary      GetPost provides the logic for retrieving a post by its ID.
 // @Produce      application/json
 // @Param        id path string true "Post ID"
+// @Param        If-None-Match header string false "ETag of the cached post, 304 is returned if it is still current"
 // @Success      200 {object} getPostResponse
-// @Failure      422,500 {object} httpErr
+// @Header       200 {string} ETag "version of the post"
+// @Success      304 "the post is not modified"
+// @Failure      404,422,500 {object} httpErr
 // @Router       /posts/{id} [GET]
 func (ctrl *postController) get(c *gin.Context) (interface{}, *httpErr) {
 	logger := ctrl.logger.Named("get")
@@ -153,17 +606,89 @@ func (ctrl *postController) get(c *gin.Context) (interface{}, *httpErr) {
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
-			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err)}
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
 		}
 
 		logger.Error("failed to get post", "err", err)
 		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get post"}
 	}
 
+	etag := versionETag(post.Version)
+	c.Header("ETag", etag)
+	if ifNoneMatch(c, etag) {
+		logger.Info("post is not modified", "etag", etag)
+		c.AbortWithStatus(http.StatusNotModified)
+		return nil, nil
+	}
+
 	logger.Info("successfully got post", "post", post)
 	return getPostResponse{ToPostDTO(post)}, nil
 }
 
+type getPostBySlugPathParams struct {
+	Slug string `uri:"slug" json:"slug" binding:"required,max=100"`
+} // @name getPostBySlugPathParams
+
+// getPostBySlugRedirect points to the current slug of the post found by a previous one
+type getPostBySlugRedirect struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+} // @name getPostBySlugRedirect
+
+// @ID           GetPostBySlug
+// @Summary      GetPostBySlug provides the logic for retrieving a post by its slug. Previous slugs of the post, which it had before title changes, are answered with 301 pointing to the current one.
+// @Produce      application/json
+// @Param        slug path string true "Post slug"
+// @Param        If-None-Match header string false "ETag of the cached post, 304 is returned if it is still current"
+// @Success      200 {object} getPostResponse
+// @Header       200 {string} ETag "version of the post"
+// @Success      301 {object} getPostBySlugRedirect
+// @Header       301 {string} Location "URL of the post with the current slug"
+// @Success      304 "the post is not modified"
+// @Failure      404,422,500 {object} httpErr
+// @Router       /posts/by-slug/{slug} [GET]
+func (ctrl *postController) getBySlug(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("getBySlug")
+
+	var pathParams getPostBySlugPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	post, err := ctrl.services.Post.GetBySlug(c, pathParams.Slug)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get post by slug", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get post by slug"}
+	}
+
+	if post.Slug != pathParams.Slug {
+		logger.Info("post has another slug", "slug", pathParams.Slug, "currentSlug", post.Slug)
+		// the location replaces the last path segment, so it works behind any prefix
+		c.Header("Location", url.PathEscape(post.Slug))
+		c.JSON(http.StatusMovedPermanently, getPostBySlugRedirect{ID: post.ID, Slug: post.Slug})
+		return nil, nil
+	}
+
+	etag := versionETag(post.Version)
+	c.Header("ETag", etag)
+	if ifNoneMatch(c, etag) {
+		logger.Info("post is not modified", "etag", etag)
+		c.AbortWithStatus(http.StatusNotModified)
+		return nil, nil
+	}
+
+	logger.Info("successfully got post by slug", "post", post)
+	return getPostResponse{ToPostDTO(post)}, nil
+}
+
 type updatePostPathParams struct {
 	ID string `uri:"id" json:"id" binding:"required,uuid"`
 } // @name updatePostPathParams
@@ -171,6 +696,14 @@ type updatePostPathParams struct {
 type updatePostBody struct {
 	Title   string `json:"title" binding:"required,max=50"`
 	Content string `json:"content" binding:"required,max=200"`
+	// PublishAt schedules publishing of the draft, must be in the future
+	PublishAt *time.Time `json:"publishAt"`
+	// CategoryID is left as it is if omitted
+	CategoryID *string `json:"categoryId" binding:"omitempty,uuid"`
+	// AuthorIDs replace all bylines of the post, they are left as they are if omitted and removed if empty
+	AuthorIDs []string `json:"authorIds" binding:"max=10,dive,uuid"`
+	// Tags replace all tags of the post, they are left as they are if omitted and removed if empty
+	Tags []string `json:"tags" binding:"max=10,dive,required,max=30"`
 } // @name updatePostBody
 
 type updatePostResponse struct {
@@ -183,8 +716,12 @@ type updatePostResponse struct {
 // @Produce      application/json
 // @Param        id path string true "Post ID"
 // @Param        fields body updatePostBody true "data"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Success      200 {object} updatePostResponse
-// @Failure      422,500 {object} httpErr
+// @Header       200 {string} ETag "version of the post"
+// @Failure      400,401,403,404,409,412,422,428,500 {object} httpErr
 // @Router       /posts/{id} [PUT]
 func (ctrl *postController) update(c *gin.Context) (interface{}, *httpErr) {
 	logger := ctrl.logger.Named("update")
@@ -205,14 +742,25 @@ func (ctrl *postController) update(c *gin.Context) (interface{}, *httpErr) {
 	}
 	logger.Debug("parsed request body", "body", body)
 
+	version, ifMatchErr := ifMatchVersion(c, ctrl.requireIfMatch)
+	if ifMatchErr != nil {
+		logger.Info(ifMatchErr.Message)
+		return nil, ifMatchErr
+	}
+
 	updatedPost, err := ctrl.services.Post.Update(c, pathParams.ID, service.UpdatePostOpt{
-		Title:   body.Title,
-		Content: body.Content,
+		Title:      &body.Title,
+		Content:    &body.Content,
+		PublishAt:  body.PublishAt,
+		CategoryID: body.CategoryID,
+		AuthorIDs:  body.AuthorIDs,
+		Tags:       body.Tags,
+		Version:    version,
 	})
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
-			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err)}
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
 		}
 
 		logger.Error("failed to update post", "err", err)
@@ -220,27 +768,269 @@ func (ctrl *postController) update(c *gin.Context) (interface{}, *httpErr) {
 	}
 
 	logger.Info("successfully updated post", "updatedPost", updatedPost)
+	c.Header("ETag", versionETag(updatedPost.Version))
 	return updatePostResponse{ToPostDTO(updatedPost)}, nil
 }
 
+type patchPostPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name patchPostPathParams
+
+// patchPostDocument is the part of a post which can be patched, patches are applied to it
+type patchPostDocument struct {
+	Title   string `json:"title" binding:"max=50"`
+	Content string `json:"content" binding:"max=200"`
+	// PublishAt schedules publishing of the draft, must be in the future, null cancels the schedule
+	PublishAt *time.Time `json:"publishAt"`
+	// CategoryID null removes the post from its category
+	CategoryID *string  `json:"categoryId" binding:"omitempty,uuid"`
+	AuthorIDs  []string `json:"authorIds" binding:"max=10,dive,uuid"`
+	Tags       []string `json:"tags" binding:"max=10,dive,required,max=30"`
+} // @name patchPostDocument
+
+type patchPostResponse struct {
+	Post *postDTO `json:"post"`
+} // @name patchPostResponse
+
+// @ID           PatchPost
+// @Summary      PatchPost provides the logic for partially updating a post by its ID with JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902), chosen by the content type. Unlike UpdatePost it can clear fields.
+// @Accept       application/merge-patch+json,application/json-patch+json,application/json
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        patch body patchPostDocument true "merge patch, or an array of JSON Patch operations for application/json-patch+json"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} patchPostResponse
+// @Header       200 {string} ETag "version of the post"
+// @Failure      400,401,403,404,409,412,422,428,500 {object} httpErr
+// @Router       /posts/{id} [PATCH]
+func (ctrl *postController) patch(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("patch")
+
+	var pathParams patchPostPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		logger.Info("failed to read request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("read request body", "body", string(patch))
+
+	version, ifMatchErr := ifMatchVersion(c, ctrl.requireIfMatch)
+	if ifMatchErr != nil {
+		logger.Info(ifMatchErr.Message)
+		return nil, ifMatchErr
+	}
+
+	post, err := ctrl.services.Post.Get(c, pathParams.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get post", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get post"}
+	}
+
+	if version != 0 && version != post.Version {
+		logger.Info("post version mismatch", "version", version, "currentVersion", post.Version)
+		err := service.ErrPostVersionMismatch
+		return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+	}
+
+	patchedDoc, err := applyPostPatch(post, c.ContentType(), patch)
+	if err != nil {
+		logger.Info("invalid patch", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid patch", Details: err.Error()}
+	}
+
+	err = binding.Validator.ValidateStruct(patchedDoc)
+	if err != nil {
+		logger.Info("invalid patched post", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid patched post", Details: err}
+	}
+	logger.Debug("applied patch", "patchedDoc", patchedDoc)
+
+	// the patch is made for the read version, so it's applied only if the post isn't changed since then
+	opt := patchedDoc.toUpdatePostOpt(post)
+	opt.Version = post.Version
+
+	updatedPost, err := ctrl.services.Post.Update(c, pathParams.ID, opt)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to patch post", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to patch post"}
+	}
+
+	logger.Info("successfully patched post", "updatedPost", updatedPost)
+	c.Header("ETag", versionETag(updatedPost.Version))
+	return patchPostResponse{ToPostDTO(updatedPost)}, nil
+}
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+// applyPostPatch applies the patch of the content type to the post, plain JSON is treated as a merge patch
+func applyPostPatch(post *entity.Post, contentType string, patch []byte) (*patchPostDocument, error) {
+	doc, err := json.Marshal(patchPostDocument{
+		Title:      post.Title,
+		Content:    post.Content,
+		PublishAt:  post.PublishAt,
+		CategoryID: post.CategoryID,
+		AuthorIDs:  postAuthorIDs(post),
+		Tags:       ToPostDTO(post).Tags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal post: %w", err)
+	}
+
+	var patchedDoc []byte
+	switch contentType {
+	case jsonPatchContentType:
+		decodedPatch, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode JSON Patch: %w", err)
+		}
+
+		patchedDoc, err = decodedPatch.Apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply JSON Patch: %w", err)
+		}
+	case mergePatchContentType, gin.MIMEJSON:
+		patchedDoc, err = jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply merge patch: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	// patches can add fields, but only the known ones can be changed
+	decoder := json.NewDecoder(bytes.NewReader(patchedDoc))
+	decoder.DisallowUnknownFields()
+
+	var patchedPost patchPostDocument
+	err = decoder.Decode(&patchedPost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode patched post: %w", err)
+	}
+
+	return &patchedPost, nil
+}
+
+// toUpdatePostOpt keeps only the fields changed compared to the post
+func (d *patchPostDocument) toUpdatePostOpt(post *entity.Post) service.UpdatePostOpt {
+	var opt service.UpdatePostOpt
+	if d.Title != post.Title {
+		opt.Title = &d.Title
+	}
+	if d.Content != post.Content {
+		opt.Content = &d.Content
+	}
+
+	switch {
+	case d.PublishAt == nil && post.PublishAt != nil:
+		opt.ClearPublishAt = true
+	case d.PublishAt != nil && (post.PublishAt == nil || !d.PublishAt.Equal(*post.PublishAt)):
+		opt.PublishAt = d.PublishAt
+	}
+
+	switch {
+	case d.CategoryID == nil && post.CategoryID != nil:
+		opt.ClearCategory = true
+	case d.CategoryID != nil && (post.CategoryID == nil || *d.CategoryID != *post.CategoryID):
+		opt.CategoryID = d.CategoryID
+	}
+
+	// the order of bylines matters, a null or a removed field removes all of them
+	authorIDs := d.AuthorIDs
+	if authorIDs == nil {
+		authorIDs = []string{}
+	}
+	if !slices.Equal(authorIDs, postAuthorIDs(post)) {
+		opt.AuthorIDs = authorIDs
+	}
+
+	// the order of tags doesn't matter, a null or a removed field removes all tags
+	tags := d.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	if !sameTagNames(tags, post.Tags) {
+		opt.Tags = tags
+	}
+
+	return opt
+}
+
+func postAuthorIDs(post *entity.Post) []string {
+	ids := make([]string, 0, len(post.Authors))
+	for _, postAuthor := range post.Authors {
+		ids = append(ids, postAuthor.AuthorID)
+	}
+
+	return ids
+}
+
+func sameTagNames(names []string, tags []entity.Tag) bool {
+	if len(names) != len(tags) {
+		return false
+	}
+
+	current := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		current[tag.Name] = true
+	}
+	for _, name := range names {
+		// tags are stored normalized, so "Go" in a patch is the same as the stored "go"
+		if !current[strings.ToLower(strings.TrimSpace(name))] {
+			return false
+		}
+	}
+
+	return true
+}
+
 type deletePostPathParams struct {
 	ID string `uri:"id" json:"id" binding:"required,uuid"`
 } // @name deletePostPathParams
 
+type deletePostQueryParams struct {
+	Hard bool `form:"hard" json:"hard"`
+} // @name deletePostQueryParams
+
 type deletePostResponse struct {
 } // @name deletePostResponse
 
 // @ID           DeletePost
-// @Summary      DeletePost provides the logic for deleting a post by its ID. If wrong ID is passed, an error will not be returned.
+// @Summary      DeletePost provides the logic for moving a post to the trash by its ID, or deleting it permanently with hard=true.
 // @Produce      application/json
 // @Param        id path string true "Post ID"
+// @Param        hard query bool false "Delete the post permanently, also works for posts in the trash"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
 // @Success      200 {object} deletePostResponse
-// @Failure      422,500 {object} httpErr
+// @Failure      401,403,404,412,422,428,500 {object} httpErr
 // @Router       /posts/{id} [DELETE]
 func (ctrl *postController) delete(c *gin.Context) (interface{}, *httpErr) {
 	logger := ctrl.logger.Named("delete")
 
-	var pathParams updatePostPathParams
+	var pathParams deletePostPathParams
 	err := c.ShouldBindUri(&pathParams)
 	if err != nil {
 		logger.Info("invalid path params", "err", err)
@@ -248,11 +1038,29 @@ func (ctrl *postController) delete(c *gin.Context) (interface{}, *httpErr) {
 	}
 	logger.Debug("parsed path params", "pathParams", pathParams)
 
-	err = ctrl.services.Post.Delete(c, pathParams.ID)
+	var queryParams deletePostQueryParams
+	err = c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	version, ifMatchErr := ifMatchVersion(c, ctrl.requireIfMatch)
+	if ifMatchErr != nil {
+		logger.Info(ifMatchErr.Message)
+		return nil, ifMatchErr
+	}
+
+	if queryParams.Hard {
+		err = ctrl.services.Post.Purge(c, pathParams.ID, version)
+	} else {
+		err = ctrl.services.Post.Delete(c, pathParams.ID, version)
+	}
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
-			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err)}
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
 		}
 
 		logger.Error("failed to delete post", "err", err)
@@ -262,3 +1070,366 @@ func (ctrl *postController) delete(c *gin.Context) (interface{}, *httpErr) {
 	logger.Info("successfully deleted post", "id", pathParams.ID)
 	return deletePostResponse{}, nil
 }
+
+type restorePostPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name restorePostPathParams
+
+type restorePostResponse struct {
+	Post *postDTO `json:"post"`
+} // @name restorePostResponse
+
+// @ID           RestorePost
+// @Summary      RestorePost provides the logic for returning a post from the trash by its ID.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} restorePostResponse
+// @Header       200 {string} ETag "version of the post"
+// @Failure      401,403,404,412,422,428,500 {object} httpErr
+// @Router       /posts/{id}/restore [POST]
+func (ctrl *postController) restore(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("restore")
+
+	var pathParams restorePostPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	version, ifMatchErr := ifMatchVersion(c, ctrl.requireIfMatch)
+	if ifMatchErr != nil {
+		logger.Info(ifMatchErr.Message)
+		return nil, ifMatchErr
+	}
+
+	post, err := ctrl.services.Post.Restore(c, pathParams.ID, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to restore post", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to restore post"}
+	}
+
+	logger.Info("successfully restored post", "post", post)
+	c.Header("ETag", versionETag(post.Version))
+	return restorePostResponse{ToPostDTO(post)}, nil
+}
+
+type changePostStatusPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name changePostStatusPathParams
+
+type changePostStatusResponse struct {
+	Post *postDTO `json:"post"`
+} // @name changePostStatusResponse
+
+// @ID           PublishPost
+// @Summary      PublishPost provides the logic for publishing a draft post by its ID.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} changePostStatusResponse
+// @Header       200 {string} ETag "version of the post"
+// @Failure      401,403,404,409,412,422,428,500 {object} httpErr
+// @Router       /posts/{id}/publish [POST]
+func (ctrl *postController) publish(c *gin.Context) (interface{}, *httpErr) {
+	return ctrl.changeStatus(c, ctrl.logger.Named("publish"), ctrl.services.Post.Publish)
+}
+
+// @ID           UnpublishPost
+// @Summary      UnpublishPost provides the logic for moving a published post back to drafts by its ID.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} changePostStatusResponse
+// @Header       200 {string} ETag "version of the post"
+// @Failure      401,403,404,409,412,422,428,500 {object} httpErr
+// @Router       /posts/{id}/unpublish [POST]
+func (ctrl *postController) unpublish(c *gin.Context) (interface{}, *httpErr) {
+	return ctrl.changeStatus(c, ctrl.logger.Named("unpublish"), ctrl.services.Post.Unpublish)
+}
+
+// @ID           ArchivePost
+// @Summary      ArchivePost provides the logic for archiving a published post by its ID.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} changePostStatusResponse
+// @Header       200 {string} ETag "version of the post"
+// @Failure      401,403,404,409,412,422,428,500 {object} httpErr
+// @Router       /posts/{id}/archive [POST]
+func (ctrl *postController) archive(c *gin.Context) (interface{}, *httpErr) {
+	return ctrl.changeStatus(c, ctrl.logger.Named("archive"), ctrl.services.Post.Archive)
+}
+
+// changeStatus handles all status actions as they differ only in the service method
+func (ctrl *postController) changeStatus(
+	c *gin.Context, logger logging.Logger, change func(ctx context.Context, id string, version int) (*entity.Post, error),
+) (interface{}, *httpErr) {
+	var pathParams changePostStatusPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	version, ifMatchErr := ifMatchVersion(c, ctrl.requireIfMatch)
+	if ifMatchErr != nil {
+		logger.Info(ifMatchErr.Message)
+		return nil, ifMatchErr
+	}
+
+	post, err := change(c, pathParams.ID, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to change post status", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to change post status"}
+	}
+
+	logger.Info("successfully changed post status", "post", post)
+	c.Header("ETag", versionETag(post.Version))
+	return changePostStatusResponse{ToPostDTO(post)}, nil
+}
+
+type postRevisionDTO struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+} // @name PostRevision
+
+func toPostRevisionDTO(r *entity.PostRevision) *postRevisionDTO {
+	return &postRevisionDTO{
+		Number:    r.Number,
+		Title:     r.Title,
+		Content:   r.Content,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type listPostRevisionsPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name listPostRevisionsPathParams
+
+type listPostRevisionsResponse struct {
+	Revisions []*postRevisionDTO `json:"revisions"`
+} // @name listPostRevisionsResponse
+
+// @ID           ListPostRevisions
+// @Summary      ListPostRevisions provides the logic for retrieving all revisions of a post, the oldest first. Revisions appear after the first edit.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Success      200 {object} listPostRevisionsResponse
+// @Failure      404,422,500 {object} httpErr
+// @Router       /posts/{id}/revisions [GET]
+func (ctrl *postController) listRevisions(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("listRevisions")
+
+	var pathParams listPostRevisionsPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	revisions, err := ctrl.services.Post.ListRevisions(c, pathParams.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list post revisions", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list post revisions"}
+	}
+
+	revisionsDTO := make([]*postRevisionDTO, 0, len(revisions))
+	for _, r := range revisions {
+		revisionsDTO = append(revisionsDTO, toPostRevisionDTO(&r))
+	}
+
+	logger.Info("successfully listed post revisions", "revisions", revisions)
+	return listPostRevisionsResponse{revisionsDTO}, nil
+}
+
+type getPostRevisionPathParams struct {
+	ID  string `uri:"id" json:"id" binding:"required,uuid"`
+	Rev int    `uri:"rev" json:"rev" binding:"required,min=1"`
+} // @name getPostRevisionPathParams
+
+type getPostRevisionResponse struct {
+	Revision *postRevisionDTO `json:"revision"`
+} // @name getPostRevisionResponse
+
+// @ID           GetPostRevision
+// @Summary      GetPostRevision provides the logic for retrieving a post revision by its number.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        rev path int true "Revision number"
+// @Success      200 {object} getPostRevisionResponse
+// @Failure      404,422,500 {object} httpErr
+// @Router       /posts/{id}/revisions/{rev} [GET]
+func (ctrl *postController) getRevision(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("getRevision")
+
+	var pathParams getPostRevisionPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	revision, err := ctrl.services.Post.GetRevision(c, pathParams.ID, pathParams.Rev)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to get post revision", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to get post revision"}
+	}
+
+	logger.Info("successfully got post revision", "revision", revision)
+	return getPostRevisionResponse{toPostRevisionDTO(revision)}, nil
+}
+
+type diffPostRevisionsPathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name diffPostRevisionsPathParams
+
+type diffPostRevisionsQueryParams struct {
+	From int `form:"from" json:"from" binding:"required,min=1"`
+	To   int `form:"to" json:"to" binding:"required,min=1"`
+} // @name diffPostRevisionsQueryParams
+
+type diffPostRevisionsResponse struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	// diffs are in the unified format, empty if the field isn't changed
+	TitleDiff   string `json:"titleDiff"`
+	ContentDiff string `json:"contentDiff"`
+} // @name diffPostRevisionsResponse
+
+// @ID           DiffPostRevisions
+// @Summary      DiffPostRevisions provides the logic for comparing two post revisions with a unified text diff.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        from query int true "Revision number to compare from"
+// @Param        to query int true "Revision number to compare to"
+// @Success      200 {object} diffPostRevisionsResponse
+// @Failure      404,422,500 {object} httpErr
+// @Router       /posts/{id}/revisions/diff [GET]
+func (ctrl *postController) diffRevisions(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("diffRevisions")
+
+	var pathParams diffPostRevisionsPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var queryParams diffPostRevisionsQueryParams
+	err = c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	diff, err := ctrl.services.Post.DiffRevisions(c, pathParams.ID, queryParams.From, queryParams.To)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to diff post revisions", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to diff post revisions"}
+	}
+
+	logger.Info("successfully diffed post revisions", "diff", diff)
+	return diffPostRevisionsResponse{
+		From:        diff.From,
+		To:          diff.To,
+		TitleDiff:   diff.TitleDiff,
+		ContentDiff: diff.ContentDiff,
+	}, nil
+}
+
+type restorePostRevisionPathParams struct {
+	ID  string `uri:"id" json:"id" binding:"required,uuid"`
+	Rev int    `uri:"rev" json:"rev" binding:"required,min=1"`
+} // @name restorePostRevisionPathParams
+
+type restorePostRevisionResponse struct {
+	Post *postDTO `json:"post"`
+} // @name restorePostRevisionResponse
+
+// @ID           RestorePostRevision
+// @Summary      RestorePostRevision provides the logic for rolling a post back to a revision, the rollback is recorded as a new revision.
+// @Produce      application/json
+// @Param        id path string true "Post ID"
+// @Param        rev path int true "Revision number"
+// @Param        If-Match header string false "ETag of the post version the change is made for, required if the server is configured so"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} restorePostRevisionResponse
+// @Header       200 {string} ETag "version of the post"
+// @Failure      401,403,404,412,422,428,500 {object} httpErr
+// @Router       /posts/{id}/revisions/{rev}/restore [POST]
+func (ctrl *postController) restoreRevision(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("restoreRevision")
+
+	var pathParams restorePostRevisionPathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	version, ifMatchErr := ifMatchVersion(c, ctrl.requireIfMatch)
+	if ifMatchErr != nil {
+		logger.Info(ifMatchErr.Message)
+		return nil, ifMatchErr
+	}
+
+	post, err := ctrl.services.Post.RestoreRevision(c, pathParams.ID, pathParams.Rev, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to restore post revision", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to restore post revision"}
+	}
+
+	logger.Info("successfully restored post revision", "post", post)
+	c.Header("ETag", versionETag(post.Version))
+	return restorePostRevisionResponse{ToPostDTO(post)}, nil
+}
@@ -0,0 +1,42 @@
+package httpcontroller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	problemDetailsContentType = "application/problem+json"
+	problemTypeURNPrefix      = "urn:news-api:problem:"
+	problemTypeDefault        = "about:blank"
+)
+
+// problemDetails provides an RFC 7807 representation of httpErr
+type problemDetails struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code,omitempty"`
+	Errors   map[string]interface{} `json:"errors,omitempty"`
+} // @name problemDetails
+
+func newProblemDetails(c *gin.Context, status int, err *httpErr) *problemDetails {
+	// errors without code have no specific type, so the title is taken from the status
+	problemType := problemTypeDefault
+	if err.Code != "" {
+		problemType = problemTypeURNPrefix + err.Code
+	}
+
+	return &problemDetails{
+		Type:     problemType,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Message,
+		Instance: c.Request.URL.RequestURI(),
+		Code:     err.Code,
+		Errors:   err.ValidationErrors,
+	}
+}
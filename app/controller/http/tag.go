@@ -0,0 +1,73 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tagController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newTagController(opt controllerOptions) {
+	logger := opt.Logger.Named("tagController")
+
+	c := tagController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/tags")
+	group.GET("", errorDecorator(logger, c.list))
+}
+
+type tagDTO struct {
+	Name string `json:"name"`
+	// PostsCount is the number of posts with the tag, deleted posts aren't counted
+	PostsCount int64 `json:"postsCount"`
+} // @name Tag
+
+func toTagDTO(t *entity.TagUsage) *tagDTO {
+	return &tagDTO{
+		Name:       t.Name,
+		PostsCount: t.PostsCount,
+	}
+}
+
+type listTagsResponse struct {
+	Tags []*tagDTO `json:"tags"`
+} // @name listTagsResponse
+
+// @ID           ListTags
+// @Summary      ListTags provides the logic for retrieving all tags with numbers of their published posts, the most used first.
+// @Produce      application/json
+// @Success      200 {object} listTagsResponse
+// @Failure      500 {object} httpErr
+// @Router       /tags [GET]
+func (ctrl *tagController) list(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("list")
+
+	tags, err := ctrl.services.Tag.List(c)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list tags", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list tags"}
+	}
+
+	tagsDTO := make([]*tagDTO, 0, len(tags))
+	for _, t := range tags {
+		tagsDTO = append(tagsDTO, toTagDTO(&t))
+	}
+
+	logger.Info("successfully listed tags", "tags", tagsDTO)
+	return listTagsResponse{tagsDTO}, nil
+}
@@ -0,0 +1,136 @@
+package httpcontroller
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+type userController struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+func newUserController(opt controllerOptions) {
+	logger := opt.Logger.Named("userController")
+
+	c := userController{
+		services: opt.Services,
+		logger:   logger,
+	}
+
+	group := opt.RouterGroup.Group("/users")
+	group.GET("", errorDecorator(logger, c.list))
+	group.PUT(":id/role", errorDecorator(logger, c.setRole))
+}
+
+type listUsersQueryParams struct {
+	Role string `form:"role" json:"role" binding:"omitempty,oneof=reader author editor admin"`
+} // @name listUsersQueryParams
+
+type listUsersResponse struct {
+	Users []*userDTO `json:"users"`
+} // @name listUsersResponse
+
+// @ID           ListUsers
+// @Summary      ListUsers provides the logic for retrieving users ordered by email, it's available to admins only.
+// @Produce      application/json
+// @Param        role query string false "Role of the users" Enums(reader, author, editor, admin)
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} listUsersResponse
+// @Failure      400,401,403,422,500 {object} httpErr
+// @Router       /users [GET]
+func (ctrl *userController) list(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("list")
+
+	var queryParams listUsersQueryParams
+	err := c.ShouldBindQuery(&queryParams)
+	if err != nil {
+		logger.Info("invalid query params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid query params", Details: err}
+	}
+	logger.Debug("parsed query params", "queryParams", queryParams)
+
+	users, err := ctrl.services.User.List(c, service.ListUsersOpt{Role: entity.UserRole(queryParams.Role)})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to list users", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to list users"}
+	}
+
+	usersDTO := make([]*userDTO, 0, len(users))
+	for _, user := range users {
+		usersDTO = append(usersDTO, toUserDTO(&user))
+	}
+
+	logger.Info("successfully listed users", "count", len(usersDTO))
+	return listUsersResponse{usersDTO}, nil
+}
+
+type setUserRolePathParams struct {
+	ID string `uri:"id" json:"id" binding:"required,uuid"`
+} // @name setUserRolePathParams
+
+type setUserRoleBody struct {
+	Role string `json:"role" binding:"required,oneof=reader author editor admin"`
+} // @name setUserRoleBody
+
+type setUserRoleResponse struct {
+	User *userDTO `json:"user"`
+} // @name setUserRoleResponse
+
+// @ID           SetUserRole
+// @Summary      SetUserRole provides the logic for changing the role of a user, it's available to admins only and they can't change their own role. The user gets the role with the next access token.
+// @Accept       application/json
+// @Produce      application/json
+// @Param        id path string true "User ID"
+// @Param        fields body setUserRoleBody true "data"
+// @Security     BearerAuth
+// @Security     APIKeyAuth
+// @Success      200 {object} setUserRoleResponse
+// @Failure      400,401,403,404,422,500 {object} httpErr
+// @Router       /users/{id}/role [PUT]
+func (ctrl *userController) setRole(c *gin.Context) (interface{}, *httpErr) {
+	logger := ctrl.logger.Named("setRole")
+
+	var pathParams setUserRolePathParams
+	err := c.ShouldBindUri(&pathParams)
+	if err != nil {
+		logger.Info("invalid path params", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid path params", Details: err}
+	}
+	logger.Debug("parsed path params", "pathParams", pathParams)
+
+	var body setUserRoleBody
+	err = c.ShouldBindJSON(&body)
+	if err != nil {
+		logger.Info("invalid request body", "err", err)
+		return nil, &httpErr{Type: httpErrTypeClient, Message: "invalid request body", Details: err}
+	}
+	logger.Debug("parsed request body", "body", body)
+
+	user, err := ctrl.services.User.SetRole(c, service.SetUserRoleOpt{
+		UserID: pathParams.ID,
+		Role:   entity.UserRole(body.Role),
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, &httpErr{Type: httpErrTypeClient, Message: err.Error(), Code: errs.Code(err), Kind: errs.KindOf(err)}
+		}
+
+		logger.Error("failed to set user role", "err", err)
+		return nil, &httpErr{Type: httpErrTypeServer, Message: "failed to set user role"}
+	}
+
+	logger.Info("successfully set user role", "userID", user.ID)
+	return setUserRoleResponse{toUserDTO(user)}, nil
+}
@@ -0,0 +1,86 @@
+package entity
+
+import "time"
+
+// APIKey lets machine clients access the API without signing in. Only the hash of the key is stored,
+// so the key is shown once on creation and can't be restored afterwards.
+type APIKey struct {
+	ID string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	Name string `gorm:"type:varchar(100);not null"`
+	// Prefix is the beginning of the key, it tells keys apart without revealing them
+	Prefix string `gorm:"type:varchar(16);not null"`
+	// KeyHash is a hex SHA-256 of the key, keys are random enough for a fast hash to be safe and searchable
+	KeyHash string        `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scopes  []APIKeyScope `gorm:"serializer:json;not null"`
+
+	// CreatedByID is the admin who created the key, it's nil if the key was created by another key
+	// or the admin is deleted
+	CreatedByID *string `gorm:"type:uuid;index"`
+	CreatedBy   *User   `gorm:"constraint:OnDelete:SET NULL"`
+
+	// ExpiresAt is nil for keys which don't expire
+	ExpiresAt *time.Time
+	// LastUsedAt is updated at most once a minute, so it's approximate
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+
+	CreatedAt time.Time `gorm:"index"`
+	UpdatedAt time.Time
+}
+
+// IsActive tells whether the key can be used at the time
+func (k *APIKey) IsActive(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || at.Before(*k.ExpiresAt))
+}
+
+// APIKeyScope limits what an API key can do, it's checked along with the role of the key
+type APIKeyScope string
+
+const (
+	APIKeyScopePostsRead APIKeyScope = "posts:read"
+	// APIKeyScopePostsWrite lets keys create posts and edit and trash their own ones like authors,
+	// publishing, scheduling and managing categories and authors need the admin scope
+	APIKeyScopePostsWrite APIKeyScope = "posts:write"
+	// APIKeyScopeAdmin allows everything, including comments moderation and users and API keys management
+	APIKeyScopeAdmin APIKeyScope = "admin"
+)
+
+// apiKeyScopeIncludes lists scopes each scope includes besides itself
+var apiKeyScopeIncludes = map[APIKeyScope][]APIKeyScope{
+	APIKeyScopePostsRead:  nil,
+	APIKeyScopePostsWrite: {APIKeyScopePostsRead},
+	APIKeyScopeAdmin:      {APIKeyScopePostsRead, APIKeyScopePostsWrite},
+}
+
+func (s APIKeyScope) IsValid() bool {
+	_, ok := apiKeyScopeIncludes[s]
+	return ok
+}
+
+// Includes tells whether the scope allows everything the other one does
+func (s APIKeyScope) Includes(other APIKeyScope) bool {
+	if s == other {
+		return s.IsValid()
+	}
+
+	for _, included := range apiKeyScopeIncludes[s] {
+		if included == other {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Role is the most privileged role the scope is enough for, keys act with the role of their widest scope
+func (s APIKeyScope) Role() UserRole {
+	switch s {
+	case APIKeyScopeAdmin:
+		return UserRoleAdmin
+	case APIKeyScopePostsWrite:
+		return UserRoleAuthor
+	default:
+		return UserRoleReader
+	}
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Author writes posts, the slug is a unique human-readable identifier used in URLs
+type Author struct {
+	ID string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	Name      string `gorm:"type:varchar(100);not null"`
+	Slug      string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	Bio       string
+	AvatarURL string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PostAuthor is a byline of a post, bylines are shown in the order of their positions
+type PostAuthor struct {
+	PostID   string  `gorm:"type:uuid;primaryKey"`
+	AuthorID string  `gorm:"type:uuid;primaryKey;index"`
+	Author   *Author `gorm:"constraint:OnDelete:RESTRICT"`
+	Position int     `gorm:"not null"`
+}
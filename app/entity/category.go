@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Category is a section of the site, categories form a tree through their parents
+type Category struct {
+	ID string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	Name string `gorm:"type:varchar(50);not null"`
+	// ParentID is nil for top-level categories, a category with children can't be deleted
+	ParentID *string   `gorm:"type:uuid;index"`
+	Parent   *Category `gorm:"constraint:OnDelete:RESTRICT"`
+	// Children are direct subcategories, they are loaded only when a single category is requested
+	Children []Category `gorm:"foreignKey:ParentID"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CategoryUpdate replaces the name and the parent of a category, nil ParentID makes it top-level
+type CategoryUpdate struct {
+	Name     string
+	ParentID *string
+}
@@ -0,0 +1,99 @@
+package entity
+
+import "time"
+
+// Comment is a reader's comment on a post, replies refer to the comment they answer.
+// Deleted comments stay as placeholders without content, so their replies keep the thread.
+// Only approved comments are public, see CommentStatus.
+type Comment struct {
+	ID string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	PostID string `gorm:"type:uuid;not null;index:idx_comments_post_id_parent_id,priority:1"`
+	Post   *Post  `gorm:"constraint:OnDelete:CASCADE"`
+	// ParentID is nil for top-level comments
+	ParentID *string  `gorm:"type:uuid;index:idx_comments_post_id_parent_id,priority:2"`
+	Parent   *Comment `gorm:"constraint:OnDelete:CASCADE"`
+
+	AuthorName string `gorm:"type:varchar(50);not null"`
+	Content    string
+	// TokenHash is a hash of the secret token given to the commenter, the token is required to edit or delete the comment
+	TokenHash string `gorm:"type:varchar(64);not null"`
+
+	// Status is approved for comments created before moderation existed
+	Status CommentStatus `gorm:"type:varchar(16);not null;default:approved;index"`
+	// ModerationReason is the reason of the last moderation decision, the history is kept in CommentModeration
+	ModerationReason string
+	ModeratedAt      *time.Time
+
+	// RepliesCount is computed by queries, only approved and not deleted replies are counted
+	RepliesCount int64 `gorm:"->;-:migration"`
+
+	CreatedAt time.Time
+	EditedAt  *time.Time
+	DeletedAt *time.Time
+}
+
+// CommentStatus is a moderation state of a comment
+type CommentStatus string
+
+const (
+	// CommentStatusPending comments wait for a moderator, they are created so if the post is pre-moderated
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusRejected CommentStatus = "rejected"
+	CommentStatusSpam     CommentStatus = "spam"
+)
+
+// IsCounted tells whether the comment is counted in the comments count of its post and the replies count of its parent
+func (c *Comment) IsCounted() bool {
+	return c.Status == CommentStatusApproved && c.DeletedAt == nil
+}
+
+// CommentUpdate lists changed fields of a comment, nil fields are left as they are
+type CommentUpdate struct {
+	Content *string
+	// Status is changed on edits of pre-moderated comments, moderators change it with Moderation
+	Status *CommentStatus
+	// Moderation is a moderator's decision, it sets the status and is recorded in the moderation history
+	Moderation *CommentModeration
+}
+
+// CommentModeration is a record of a moderation decision, it's kept for audit
+type CommentModeration struct {
+	ID string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	CommentID string   `gorm:"type:uuid;not null;index"`
+	Comment   *Comment `gorm:"constraint:OnDelete:CASCADE"`
+
+	Status CommentStatus `gorm:"type:varchar(16);not null"`
+	Reason string
+
+	CreatedAt time.Time
+}
+
+// CommentsFilter selects comments of one level of a post thread, oldest first
+type CommentsFilter struct {
+	PostID string
+	// Status selects comments with this status only, empty means any status
+	Status CommentStatus
+	// ParentID selects replies of the comment, nil selects top-level comments
+	ParentID *string
+	Limit    int
+	// After selects comments following the cursor
+	After *CommentsCursor
+}
+
+// ModerationQueueFilter selects comments of all posts and thread levels for moderators, oldest first
+type ModerationQueueFilter struct {
+	Status CommentStatus
+	// PostID selects comments of the post only, empty means any post
+	PostID string
+	Limit  int
+	After  *CommentsCursor
+}
+
+// CommentsCursor identifies a comment position in the thread, ID is used as a tie-breaker
+type CommentsCursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        string    `json:"id"`
+}
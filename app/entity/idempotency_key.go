@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// IdempotencyKey stores the response to a request made with Idempotency-Key header, so retries of the
+// request get the same response instead of repeating its effect. Keys are unique per client.
+type IdempotencyKey struct {
+	ClientID string `gorm:"primaryKey"`
+	Key      string `gorm:"primaryKey"`
+	// RequestHash identifies the request the key was used for first
+	RequestHash string `gorm:"not null"`
+
+	// Completed is false while the first request is being handled, the response is empty till then
+	Completed      bool `gorm:"not null;default:false"`
+	ResponseStatus int
+	ResponseHeader map[string][]string `gorm:"serializer:json"`
+	ResponseBody   []byte
+
+	CreatedAt time.Time
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
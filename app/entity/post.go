@@ -11,8 +11,160 @@ type Post struct {
 
 	Title   string
 	Content string
+	// Slug is the current human-readable identifier of the post generated from the title,
+	// it's unique among all slugs the posts have ever had, see PostSlug. Posts created before slugs get theirs on start.
+	Slug string `gorm:"type:varchar(100);not null;default:'';index"`
+	// Version is incremented on every change of the post, it's used for optimistic concurrency
+	Version int `gorm:"not null;default:1"`
+
+	// Status of new posts is draft, posts created before the lifecycle are published by the migration
+	Status      PostStatus `gorm:"type:varchar(16);not null;default:draft;index"`
+	PublishedAt *time.Time
+	// PublishAt is a scheduled publishing time for a draft, it's cleared on any status change
+	PublishAt *time.Time `gorm:"index"`
+
+	// OwnerID is the user who created the post, authors can edit only their own posts.
+	// It's nil for posts created before accounts and for posts of deleted users.
+	OwnerID *string `gorm:"type:uuid;index"`
+	Owner   *User   `gorm:"constraint:OnDelete:SET NULL"`
+	// OwnerAPIKeyID is the API key which created the post, keys with the posts:write scope edit only their own posts
+	OwnerAPIKeyID *string `gorm:"type:uuid;index"`
+	OwnerAPIKey   *APIKey `gorm:"constraint:OnDelete:SET NULL"`
+
+	// CategoryID is the primary category of the post, a category with posts can't be deleted
+	CategoryID *string   `gorm:"type:uuid;index"`
+	Category   *Category `gorm:"constraint:OnDelete:RESTRICT"`
+
+	// Authors are bylines of the post ordered by position, they are deleted with the post
+	Authors []PostAuthor `gorm:"constraint:OnDelete:CASCADE"`
+
+	// CommentsCount is maintained with comments, only approved and not deleted comments are counted
+	CommentsCount int `gorm:"not null;default:0"`
+	// CommentsPremoderation overrides the global pre-moderation policy for comments of the post, nil means the global one
+	CommentsPremoderation *bool
+
+	// Tags are linked through the post_tags join table, links are deleted with the post or the tag
+	Tags []Tag `gorm:"many2many:post_tags;constraint:OnDelete:CASCADE"`
+
+	// SearchVector is maintained by PostgreSQL for full-text search and never read or written by the app
+	SearchVector string `gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || setweight(to_tsvector('english', coalesce(content, '')), 'B')) STORED;index:,type:gin;->:false;<-:false"`
 
 	CreatedAt time.Time `gorm:"index"`
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt
 }
+
+// PostStatus is a post lifecycle stage, only published posts are public
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+// postStatusTransitions lists allowed target statuses for each status
+var postStatusTransitions = map[PostStatus][]PostStatus{
+	PostStatusDraft:     {PostStatusPublished},
+	PostStatusPublished: {PostStatusArchived, PostStatusDraft},
+}
+
+func (s PostStatus) CanTransitionTo(to PostStatus) bool {
+	for _, allowed := range postStatusTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PostUpdate lists changed fields of a post, nil fields are left as they are
+type PostUpdate struct {
+	Title     *string
+	Content   *string
+	PublishAt *time.Time
+	// ClearPublishAt cancels scheduled publishing, it's ignored when PublishAt is set
+	ClearPublishAt bool
+	CategoryID     *string
+	// ClearCategory removes the post from its category, it's ignored when CategoryID is set
+	ClearCategory bool
+	// Tags replace all tags of the post, nil leaves them as they are and an empty slice removes them
+	Tags *[]Tag
+	// Authors replace all bylines of the post the same way as tags
+	Authors *[]PostAuthor
+	// Slug becomes the current slug of the post if the title is changed, the previous one is kept
+	Slug *string
+	// Version is the expected current version of the post, 0 skips the check
+	Version int
+}
+
+// PostsFilter is used to select posts, by default they are ordered from the newest to the oldest
+type PostsFilter struct {
+	// OnlyDeleted selects soft-deleted posts instead of existing ones
+	OnlyDeleted bool
+	// Status selects posts with this status only, empty means any status
+	Status        PostStatus
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	UpdatedSince  *time.Time
+	TitleContains string
+	// Tags selects posts labeled with any of the tag names, or with all of them if TagsMatchAll is set
+	Tags         []string
+	TagsMatchAll bool
+	// CategoryID selects posts of the category and all its descendants
+	CategoryID string
+	// AuthorID selects posts with the author in the bylines
+	AuthorID string
+	Sort     PostsSort
+
+	Limit int
+	// After is a keyset position, only posts after it in the current sort order are selected
+	After *PostsCursor
+}
+
+// PostsSort is a sort key, the "-" prefix means descending order
+type PostsSort string
+
+const (
+	PostsSortCreatedAt     PostsSort = "created_at"
+	PostsSortCreatedAtDesc PostsSort = "-created_at"
+	PostsSortUpdatedAt     PostsSort = "updated_at"
+	PostsSortTitle         PostsSort = "title"
+)
+
+func (s PostsSort) IsValid() bool {
+	switch s {
+	case PostsSortCreatedAt, PostsSortCreatedAtDesc, PostsSortUpdatedAt, PostsSortTitle:
+		return true
+	default:
+		return false
+	}
+}
+
+// PostsCursor identifies a post position in the list sorted by Sort,
+// ID is used as a tie-breaker for posts with equal sort values
+type PostsCursor struct {
+	Sort      PostsSort `json:"sort"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Title     string    `json:"title"`
+	ID        string    `json:"id"`
+}
+
+// PostsSearchFilter is used to find posts matching a full-text query, the most relevant go first
+type PostsSearchFilter struct {
+	// Status selects posts with this status only, empty means any status
+	Status PostStatus
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// PostSearchResult is a found post with its relevance and highlighted fragments
+type PostSearchResult struct {
+	Post
+	Rank             float64
+	TitleHighlight   string
+	ContentHighlight string
+}
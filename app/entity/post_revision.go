@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// PostRevision is a snapshot of a post content, a new one is written on each content change
+type PostRevision struct {
+	ID string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	PostID string `gorm:"type:uuid;not null;uniqueIndex:idx_post_revisions_post_id_number,priority:1"`
+	Post   *Post  `gorm:"constraint:OnDelete:CASCADE"`
+	// Number is a sequential revision number inside the post starting from 1
+	Number int `gorm:"not null;uniqueIndex:idx_post_revisions_post_id_number,priority:2"`
+
+	Title   string
+	Content string
+
+	CreatedAt time.Time
+}
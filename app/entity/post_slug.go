@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// PostSlug reserves a slug for a post, previous slugs of the post are kept after title changes
+// to redirect old links to the current slug
+type PostSlug struct {
+	Slug      string `gorm:"type:varchar(100);primaryKey"`
+	PostID    string `gorm:"type:uuid;not null;index"`
+	Post      *Post  `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time
+}
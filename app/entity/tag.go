@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Tag labels posts, names are unique and kept in lower case
+type Tag struct {
+	ID   string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name string `gorm:"type:varchar(30);not null;uniqueIndex"`
+
+	CreatedAt time.Time
+}
+
+// TagUsage is a tag with the number of existing posts labeled with it
+type TagUsage struct {
+	Tag
+	PostsCount int64
+}
@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+// User is an account signed in with the email and password or through the identity provider,
+// emails are stored lowercased
+type User struct {
+	ID string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+
+	Email string `gorm:"type:varchar(254);not null;uniqueIndex"`
+	Name  string `gorm:"type:varchar(100);not null"`
+	// PasswordHash is a bcrypt hash, the password itself is never stored. It's empty for users
+	// created by the identity provider, so they can't sign in with a password.
+	PasswordHash string   `gorm:"not null"`
+	Role         UserRole `gorm:"type:varchar(16);not null;default:reader"`
+	// OIDCSubject is the ID of the user at the identity provider, it's nil till the user signs in there
+	OIDCSubject *string `gorm:"type:varchar(255);uniqueIndex"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserRole decides what the user can do, each role can do everything the previous one can
+type UserRole string
+
+const (
+	// UserRoleReader can only read
+	UserRoleReader UserRole = "reader"
+	// UserRoleAuthor can create posts and edit their own ones
+	UserRoleAuthor UserRole = "author"
+	// UserRoleEditor can edit, publish and delete any post and moderate comments
+	UserRoleEditor UserRole = "editor"
+	// UserRoleAdmin can manage users
+	UserRoleAdmin UserRole = "admin"
+)
+
+// userRoleRanks orders roles from the least to the most privileged
+var userRoleRanks = map[UserRole]int{
+	UserRoleReader: 1,
+	UserRoleAuthor: 2,
+	UserRoleEditor: 3,
+	UserRoleAdmin:  4,
+}
+
+func (r UserRole) IsValid() bool {
+	_, ok := userRoleRanks[r]
+	return ok
+}
+
+// Includes tells whether the role can do everything the other one can, unknown roles include nothing
+func (r UserRole) Includes(other UserRole) bool {
+	return r.IsValid() && userRoleRanks[r] >= userRoleRanks[other]
+}
+
+// UsersFilter narrows down users, empty fields aren't applied
+type UsersFilter struct {
+	Role UserRole
+}
+
+// RefreshToken is an issued refresh token, the token itself is a signed JWT with the ID of this record.
+// Each refresh uses the token and issues the next one of the same family, so using a token twice
+// means it was stolen and the whole family is revoked.
+type RefreshToken struct {
+	ID string `gorm:"type:uuid;primaryKey"`
+
+	UserID string `gorm:"type:uuid;not null;index"`
+	User   *User  `gorm:"constraint:OnDelete:CASCADE"`
+	// FamilyID is shared by all tokens issued from one login
+	FamilyID string `gorm:"type:uuid;not null;index"`
+
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// OIDCAuthRequest is a sign-in started at the identity provider, the callback takes it by the state once
+type OIDCAuthRequest struct {
+	// State is sent to the provider and comes back with the code
+	State string `gorm:"type:varchar(64);primaryKey"`
+	// CodeVerifier is the PKCE secret, only its challenge is sent to the provider before the code is issued
+	CodeVerifier string `gorm:"type:varchar(128);not null"`
+	// Nonce binds the ID token to this request
+	Nonce string `gorm:"type:varchar(64);not null"`
+
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
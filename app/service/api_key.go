@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var _ APIKeyService = (*apiKeyService)(nil)
+
+const (
+	// apiKeySize is the number of random bytes in a key
+	apiKeySize = 32
+	// apiKeyDisplayLength is the length of the key prefix which is stored to tell keys apart
+	apiKeyDisplayLength = 12
+	// apiKeyLastUsedPrecision limits writes of the last usage time, keys can be used on every request
+	apiKeyLastUsedPrecision = time.Minute
+)
+
+type apiKeyService struct {
+	storages Storages
+	logger   logging.Logger
+}
+
+func NewAPIKeyService(storages Storages, logger logging.Logger) *apiKeyService {
+	return &apiKeyService{storages, logger.Named("apiKeyService")}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, opt CreateAPIKeyOpt) (*CreatedAPIKey, error) {
+	logger := s.logger.Named("Create")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if len(opt.Scopes) == 0 {
+		logger.Info("no scopes")
+		return nil, ErrInvalidAPIKeyScope
+	}
+	for _, scope := range opt.Scopes {
+		if !scope.IsValid() {
+			logger.Info("invalid scope", "scope", scope)
+			return nil, ErrInvalidAPIKeyScope
+		}
+	}
+
+	if opt.ExpiresAt != nil && !opt.ExpiresAt.After(time.Now()) {
+		logger.Info("expiresAt is not in the future", "expiresAt", opt.ExpiresAt)
+		return nil, ErrAPIKeyExpiresAtNotInFuture
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		logger.Error("failed to generate API key", "err", err)
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	apiKey := &entity.APIKey{
+		Name:      opt.Name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    opt.Scopes,
+		ExpiresAt: opt.ExpiresAt,
+	}
+	if principal := PrincipalFromContext(ctx); principal.UserID != "" {
+		apiKey.CreatedByID = &principal.UserID
+	}
+
+	createdAPIKey, err := s.storages.APIKey.Create(ctx, apiKey)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to create API key", "err", err)
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	logger.Info("successfully created API key", "keyID", createdAPIKey.ID)
+	return &CreatedAPIKey{APIKey: createdAPIKey, Key: key}, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context) ([]entity.APIKey, error) {
+	logger := s.logger.Named("List")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	keys, err := s.storages.APIKey.List(ctx)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list API keys", "err", err)
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	logger.Info("successfully listed API keys", "count", len(keys))
+	return keys, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, id string) (*entity.APIKey, error) {
+	logger := s.logger.Named("Revoke")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	revokedAPIKey, err := s.storages.APIKey.Revoke(ctx, id, time.Now())
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to revoke API key", "err", err)
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	logger.Info("successfully revoked API key", "keyID", revokedAPIKey.ID)
+	return revokedAPIKey, nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*Principal, error) {
+	logger := s.logger.Named("Authenticate")
+
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		logger.Info("key without prefix")
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.storages.APIKey.GetByHash(ctx, hashAPIKey(key))
+	if errors.Is(err, ErrGetAPIKeyNotFound) {
+		logger.Info("unknown API key")
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		logger.Error("failed to get API key", "err", err)
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		logger.Info("API key is expired or revoked", "keyID", apiKey.ID)
+		return nil, ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedPrecision {
+		// the usage is only tracked, so the request isn't failed because of it
+		err = s.storages.APIKey.Touch(ctx, apiKey.ID, now)
+		if err != nil {
+			logger.Error("failed to touch API key", "err", err)
+		}
+	}
+
+	principal := Principal{APIKeyID: apiKey.ID, Role: entity.UserRoleReader, Scopes: apiKey.Scopes}
+	for _, scope := range apiKey.Scopes {
+		if scope.Role().Includes(principal.Role) {
+			principal.Role = scope.Role()
+		}
+	}
+
+	logger.Info("successfully authenticated", "principal", principal)
+	return &principal, nil
+}
+
+// generateAPIKey returns a random key with APIKeyPrefix
+func generateAPIKey() (string, error) {
+	b := make([]byte, apiKeySize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyService_Create(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	adminID := uuid.NewString()
+	adminCtx := principalCtx(adminID, entity.UserRoleAdmin)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	scopes := []entity.APIKeyScope{entity.APIKeyScopePostsRead}
+	created := func(_ context.Context, k *entity.APIKey) (*entity.APIKey, error) {
+		k.ID = uuid.NewString()
+		return k, nil
+	}
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		mock        func(m *mocks.APIKeyStorage)
+		input       CreateAPIKeyOpt
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Create",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("Create", adminCtx, mock.MatchedBy(func(k *entity.APIKey) bool {
+					return k.Name == "partner" && k.CreatedByID != nil && *k.CreatedByID == adminID && k.ExpiresAt == nil
+				})).Return(created)
+			},
+			input: CreateAPIKeyOpt{Name: "partner", Scopes: scopes},
+		},
+		{
+			name: "Create expiring",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("Create", adminCtx, mock.MatchedBy(func(k *entity.APIKey) bool {
+					return k.ExpiresAt != nil && k.ExpiresAt.Equal(tomorrow)
+				})).Return(created)
+			},
+			input: CreateAPIKeyOpt{Name: "partner", Scopes: scopes, ExpiresAt: &tomorrow},
+		},
+		{
+			name:        "Create expired",
+			ctx:         adminCtx,
+			input:       CreateAPIKeyOpt{Name: "partner", Scopes: scopes, ExpiresAt: &yesterday},
+			expectedErr: ErrAPIKeyExpiresAtNotInFuture,
+			expectErr:   true,
+		},
+		{
+			name:        "Create without scopes",
+			ctx:         adminCtx,
+			input:       CreateAPIKeyOpt{Name: "partner"},
+			expectedErr: ErrInvalidAPIKeyScope,
+			expectErr:   true,
+		},
+		{
+			name:        "Create with invalid scope",
+			ctx:         adminCtx,
+			input:       CreateAPIKeyOpt{Name: "partner", Scopes: []entity.APIKeyScope{"posts:delete"}},
+			expectedErr: ErrInvalidAPIKeyScope,
+			expectErr:   true,
+		},
+		{
+			name: "Create with unexpected error in storage",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("Create", adminCtx, mock.Anything).Return(nil, errors.New("error!"))
+			},
+			input:     CreateAPIKeyOpt{Name: "partner", Scopes: scopes},
+			expectErr: true,
+		},
+		{
+			name:        "Create by editor",
+			ctx:         editorCtx,
+			input:       CreateAPIKeyOpt{Name: "partner", Scopes: scopes},
+			expectedErr: ErrForbidden,
+			expectErr:   true,
+		},
+		{
+			name:        "Create with writing API key",
+			ctx:         apiKeyCtx(entity.APIKeyScopePostsWrite),
+			input:       CreateAPIKeyOpt{Name: "partner", Scopes: scopes},
+			expectedErr: ErrForbidden,
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeyStorageMock := mocks.NewAPIKeyStorage(t)
+			if tc.mock != nil {
+				tc.mock(apiKeyStorageMock)
+			}
+			storages := Storages{APIKey: apiKeyStorageMock}
+
+			apiKeyService := NewAPIKeyService(storages, logger)
+			actual, err := apiKeyService.Create(tc.ctx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to create API key")
+				require.NotEmpty(t, actual.APIKey.ID, "ID is empty")
+				require.True(t, strings.HasPrefix(actual.Key, APIKeyPrefix), "key has no prefix")
+				require.True(t, strings.HasPrefix(actual.Key, actual.APIKey.Prefix), "prefix isn't the beginning of the key")
+				require.Equal(t, hashAPIKey(actual.Key), actual.APIKey.KeyHash, "hash isn't of the key")
+				require.NotContains(t, actual.APIKey.KeyHash, actual.Key, "key is stored")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "API key is not nil")
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_List(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	adminCtx := principalCtx(uuid.NewString(), entity.UserRoleAdmin)
+	keys := []entity.APIKey{{ID: uuid.NewString(), Name: "partner"}}
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		mock        func(m *mocks.APIKeyStorage)
+		expected    []entity.APIKey
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "List",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("List", adminCtx).Return(keys, nil)
+			},
+			expected: keys,
+		},
+		{
+			name: "List with unexpected error in storage",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("List", adminCtx).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+		{
+			name:        "List anonymously",
+			ctx:         context.Background(),
+			expectedErr: ErrAuthenticationRequired,
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeyStorageMock := mocks.NewAPIKeyStorage(t)
+			if tc.mock != nil {
+				tc.mock(apiKeyStorageMock)
+			}
+			storages := Storages{APIKey: apiKeyStorageMock}
+
+			apiKeyService := NewAPIKeyService(storages, logger)
+			actual, err := apiKeyService.List(tc.ctx)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list API keys")
+				require.Equal(t, tc.expected, actual, "API keys are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "API keys are not nil")
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	adminCtx := principalCtx(uuid.NewString(), entity.UserRoleAdmin)
+	keyID := uuid.NewString()
+	now := time.Now()
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		mock        func(m *mocks.APIKeyStorage)
+		expected    *entity.APIKey
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Revoke",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("Revoke", adminCtx, keyID, mock.AnythingOfType("time.Time")).Return(&entity.APIKey{ID: keyID, RevokedAt: &now}, nil)
+			},
+			expected: &entity.APIKey{ID: keyID, RevokedAt: &now},
+		},
+		{
+			name: "Revoke non-existent key",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("Revoke", adminCtx, keyID, mock.AnythingOfType("time.Time")).Return(nil, ErrGetAPIKeyNotFound)
+			},
+			expectedErr: ErrGetAPIKeyNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Revoke with unexpected error in storage",
+			ctx:  adminCtx,
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("Revoke", adminCtx, keyID, mock.AnythingOfType("time.Time")).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+		{
+			name:        "Revoke by editor",
+			ctx:         editorCtx,
+			expectedErr: ErrForbidden,
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeyStorageMock := mocks.NewAPIKeyStorage(t)
+			if tc.mock != nil {
+				tc.mock(apiKeyStorageMock)
+			}
+			storages := Storages{APIKey: apiKeyStorageMock}
+
+			apiKeyService := NewAPIKeyService(storages, logger)
+			actual, err := apiKeyService.Revoke(tc.ctx, keyID)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to revoke API key")
+				require.Equal(t, tc.expected, actual, "API keys are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "API key is not nil")
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	key := APIKeyPrefix + "key"
+	keyID := uuid.NewString()
+	justNow := time.Now().Add(-time.Second)
+	hourAgo := time.Now().Add(-time.Hour)
+	scopes := []entity.APIKeyScope{entity.APIKeyScopePostsRead, entity.APIKeyScopePostsWrite}
+
+	testCases := []struct {
+		name         string
+		mock         func(m *mocks.APIKeyStorage)
+		input        string
+		expectedRole entity.UserRole
+		expectedErr  error
+		expectErr    bool
+	}{
+		{
+			name: "Authenticate",
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("GetByHash", context.Background(), hashAPIKey(key)).Return(&entity.APIKey{ID: keyID, Scopes: scopes}, nil)
+				m.On("Touch", context.Background(), keyID, mock.AnythingOfType("time.Time")).Return(nil)
+			},
+			input:        key,
+			expectedRole: entity.UserRoleAuthor,
+		},
+		{
+			name: "Authenticate with recently used key",
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("GetByHash", context.Background(), hashAPIKey(key)).
+					Return(&entity.APIKey{ID: keyID, Scopes: []entity.APIKeyScope{entity.APIKeyScopePostsRead}, LastUsedAt: &justNow}, nil)
+			},
+			input:        key,
+			expectedRole: entity.UserRoleReader,
+		},
+		{
+			name: "Authenticate with unexpected error in touching",
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("GetByHash", context.Background(), hashAPIKey(key)).
+					Return(&entity.APIKey{ID: keyID, Scopes: []entity.APIKeyScope{entity.APIKeyScopeAdmin}, LastUsedAt: &hourAgo}, nil)
+				m.On("Touch", context.Background(), keyID, mock.AnythingOfType("time.Time")).Return(errors.New("error!"))
+			},
+			input:        key,
+			expectedRole: entity.UserRoleAdmin,
+		},
+		{
+			name: "Authenticate with expired key",
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("GetByHash", context.Background(), hashAPIKey(key)).Return(&entity.APIKey{ID: keyID, Scopes: scopes, ExpiresAt: &hourAgo}, nil)
+			},
+			input:       key,
+			expectedErr: ErrInvalidAPIKey,
+			expectErr:   true,
+		},
+		{
+			name: "Authenticate with revoked key",
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("GetByHash", context.Background(), hashAPIKey(key)).Return(&entity.APIKey{ID: keyID, Scopes: scopes, RevokedAt: &hourAgo}, nil)
+			},
+			input:       key,
+			expectedErr: ErrInvalidAPIKey,
+			expectErr:   true,
+		},
+		{
+			name: "Authenticate with unknown key",
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("GetByHash", context.Background(), hashAPIKey(key)).Return(nil, ErrGetAPIKeyNotFound)
+			},
+			input:       key,
+			expectedErr: ErrInvalidAPIKey,
+			expectErr:   true,
+		},
+		{
+			name:        "Authenticate with key without prefix",
+			input:       "key",
+			expectedErr: ErrInvalidAPIKey,
+			expectErr:   true,
+		},
+		{
+			name: "Authenticate with unexpected error in storage",
+			mock: func(m *mocks.APIKeyStorage) {
+				m.On("GetByHash", context.Background(), hashAPIKey(key)).Return(nil, errors.New("error!"))
+			},
+			input:     key,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKeyStorageMock := mocks.NewAPIKeyStorage(t)
+			if tc.mock != nil {
+				tc.mock(apiKeyStorageMock)
+			}
+			storages := Storages{APIKey: apiKeyStorageMock}
+
+			apiKeyService := NewAPIKeyService(storages, logger)
+			actual, err := apiKeyService.Authenticate(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to authenticate")
+				require.Equal(t, keyID, actual.APIKeyID, "API key IDs are not equal")
+				require.Empty(t, actual.UserID, "user ID is not empty")
+				require.Equal(t, tc.expectedRole, actual.Role, "roles are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "principal is not nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"darkness8129/news-api/packages/oidc"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var _ AuthService = (*authService)(nil)
+
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+)
+
+// dummyPasswordHash is compared with passwords of unknown emails, so login takes the same time for any email
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+type AuthServiceOptions struct {
+	// Secret signs tokens with HS256
+	Secret          []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// AdminEmails get the admin role when their accounts are created on the first sign-in through OIDC,
+	// since only the identity provider verifies emails. Other users, including registered ones, start as readers.
+	AdminEmails []string
+	// OIDC is the identity provider staff sign in through, nil disables the sign-in
+	OIDC oidc.Provider
+	// OIDCRolesClaim is the claim of the ID token with groups of the user
+	OIDCRolesClaim string
+	// OIDCRoles map the groups to roles. If they are set, the role is synced on every sign-in and users
+	// without mapped groups become readers, otherwise roles of these users are managed by admins too.
+	OIDCRoles map[string]entity.UserRole
+}
+
+type authService struct {
+	storages Storages
+	opt      AuthServiceOptions
+	logger   logging.Logger
+}
+
+func NewAuthService(storages Storages, opt AuthServiceOptions, logger logging.Logger) *authService {
+	return &authService{storages, opt, logger.Named("authService")}
+}
+
+// tokenClaims are claims of access and refresh tokens, the subject is the user ID
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Type  string          `json:"token_type"`
+	Email string          `json:"email,omitempty"`
+	Role  entity.UserRole `json:"role,omitempty"`
+}
+
+func (s *authService) Register(ctx context.Context, opt RegisterOpt) (*entity.User, error) {
+	logger := s.logger.Named("Register")
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(opt.Password), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Error("failed to hash password", "err", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	email := normalizeEmail(opt.Email)
+	createdUser, err := s.storages.User.Create(ctx, &entity.User{
+		Email:        email,
+		Name:         opt.Name,
+		PasswordHash: string(passwordHash),
+		// nothing proves the email belongs to the user, so AdminEmails aren't trusted here
+		Role: entity.UserRoleReader,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to create user", "err", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	logger.Info("successfully registered user", "userID", createdUser.ID)
+	return createdUser, nil
+}
+
+func (s *authService) Login(ctx context.Context, opt LoginOpt) (*AuthTokens, error) {
+	logger := s.logger.Named("Login")
+
+	user, err := s.storages.User.GetByEmail(ctx, normalizeEmail(opt.Email))
+	if err != nil && !errors.Is(err, ErrGetUserNotFound) {
+		logger.Error("failed to get user", "err", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = []byte(user.PasswordHash)
+	}
+	err = bcrypt.CompareHashAndPassword(passwordHash, []byte(opt.Password))
+	if user == nil || err != nil {
+		logger.Info("invalid credentials")
+		return nil, ErrInvalidCredentials
+	}
+
+	tokens, err := s.issueTokens(ctx, user, uuid.NewString())
+	if err != nil {
+		logger.Error("failed to issue tokens", "err", err)
+		return nil, fmt.Errorf("failed to issue tokens: %w", err)
+	}
+
+	logger.Info("successfully logged in", "userID", user.ID)
+	return tokens, nil
+}
+
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error) {
+	logger := s.logger.Named("Refresh")
+
+	claims, err := s.parseToken(refreshToken, refreshTokenType)
+	if err != nil {
+		logger.Info("invalid refresh token", "err", err)
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now()
+	usedToken, err := s.storages.RefreshToken.Use(ctx, claims.ID, now)
+	if errors.Is(err, ErrUseRefreshTokenUsed) {
+		// the token was stolen either by the one who used it first or by this client, both have to sign in again
+		err = s.storages.RefreshToken.RevokeFamily(ctx, claims.ID, now)
+		if err != nil {
+			logger.Error("failed to revoke refresh token family", "err", err)
+			return nil, fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+
+		logger.Info("refresh token reuse detected", "tokenID", claims.ID)
+		return nil, ErrRefreshTokenReused
+	}
+	if errors.Is(err, ErrUseRefreshTokenNotFound) {
+		logger.Info("unknown refresh token", "tokenID", claims.ID)
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		logger.Error("failed to use refresh token", "err", err)
+		return nil, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+
+	user, err := s.storages.User.Get(ctx, usedToken.UserID)
+	if errors.Is(err, ErrGetUserNotFound) {
+		logger.Info("user of refresh token not found", "userID", usedToken.UserID)
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		logger.Error("failed to get user", "err", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	tokens, err := s.issueTokens(ctx, user, usedToken.FamilyID)
+	if err != nil {
+		logger.Error("failed to issue tokens", "err", err)
+		return nil, fmt.Errorf("failed to issue tokens: %w", err)
+	}
+
+	logger.Info("successfully refreshed tokens", "userID", user.ID)
+	return tokens, nil
+}
+
+func (s *authService) Authenticate(ctx context.Context, accessToken string) (*Principal, error) {
+	logger := s.logger.Named("Authenticate")
+
+	claims, err := s.parseToken(accessToken, accessTokenType)
+	if err != nil {
+		logger.Info("invalid access token", "err", err)
+		return nil, ErrInvalidToken
+	}
+
+	principal := Principal{UserID: claims.Subject, Email: claims.Email, Role: claims.Role}
+	// tokens issued before roles were added have none
+	if !principal.Role.IsValid() {
+		principal.Role = entity.UserRoleReader
+	}
+
+	logger.Info("successfully authenticated", "principal", principal)
+	return &principal, nil
+}
+
+func (s *authService) GetUser(ctx context.Context, id string) (*entity.User, error) {
+	logger := s.logger.Named("GetUser")
+
+	user, err := s.storages.User.Get(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get user", "err", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	logger.Info("successfully got user", "userID", user.ID)
+	return user, nil
+}
+
+// issueTokens stores the refresh token in the family, so its reuse can be detected
+func (s *authService) issueTokens(ctx context.Context, user *entity.User, familyID string) (*AuthTokens, error) {
+	now := time.Now()
+	tokens := AuthTokens{
+		AccessTokenExpiresAt:  now.Add(s.opt.AccessTokenTTL),
+		RefreshTokenExpiresAt: now.Add(s.opt.RefreshTokenTTL),
+	}
+
+	var err error
+	tokens.AccessToken, err = s.signToken(tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(tokens.AccessTokenExpiresAt),
+		},
+		Type:  accessTokenType,
+		Email: user.Email,
+		Role:  user.Role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	refreshToken, err := s.storages.RefreshToken.Create(ctx, &entity.RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		ExpiresAt: tokens.RefreshTokenExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	tokens.RefreshToken, err = s.signToken(tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        refreshToken.ID,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(tokens.RefreshTokenExpiresAt),
+		},
+		Type: refreshTokenType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign refresh token: %w", err)
+	}
+
+	return &tokens, nil
+}
+
+func (s *authService) signToken(claims tokenClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.opt.Secret)
+}
+
+// parseToken checks the signature, the expiration and the type of the token,
+// so refresh tokens can't be used as access tokens and vice versa
+func (s *authService) parseToken(token, tokenType string) (*tokenClaims, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.opt.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != tokenType {
+		return nil, fmt.Errorf("unexpected token type %q", claims.Type)
+	}
+
+	return &claims, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns the context of the request made by the principal
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns nil for anonymous requests
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
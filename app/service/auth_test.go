@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testAuthOptions = AuthServiceOptions{
+	Secret:          []byte("secret"),
+	AccessTokenTTL:  time.Minute,
+	RefreshTokenTTL: time.Hour,
+	AdminEmails:     []string{"Admin@Example.com"},
+}
+
+// testToken signs a token the same way the service does, ttl can be negative for expired tokens
+func testToken(t *testing.T, secret []byte, tokenType, id string, ttl time.Duration) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+		Type:  tokenType,
+		Email: "jane@example.com",
+		Role:  entity.UserRoleAuthor,
+	}).SignedString(secret)
+	require.NoError(t, err, "failed to sign token")
+
+	return token
+}
+
+func TestAuthService_Register(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	isJane := mock.MatchedBy(func(u *entity.User) bool {
+		return u.Email == "jane@example.com" && bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("password")) == nil
+	})
+	isAdmin := mock.MatchedBy(func(u *entity.User) bool {
+		return u.Email == "admin@example.com" && u.Role == entity.UserRoleReader
+	})
+	created := func(_ context.Context, u *entity.User) (*entity.User, error) {
+		u.ID = uuid.NewString()
+		return u, nil
+	}
+
+	testCases := []struct {
+		name          string
+		mock          func(m *mocks.UserStorage)
+		input         RegisterOpt
+		expectedEmail string
+		expectedRole  entity.UserRole
+		expectedErr   error
+		expectErr     bool
+	}{
+		{
+			name: "Register",
+			mock: func(m *mocks.UserStorage) {
+				m.On("Create", context.Background(), isJane).Return(created)
+			},
+			input:         RegisterOpt{Email: " Jane@Example.com", Name: "Jane", Password: "password"},
+			expectedEmail: "jane@example.com",
+			expectedRole:  entity.UserRoleReader,
+		},
+		{
+			name: "Register with admin email",
+			mock: func(m *mocks.UserStorage) {
+				m.On("Create", context.Background(), isAdmin).Return(created)
+			},
+			input:         RegisterOpt{Email: "admin@example.com", Name: "Admin", Password: "password"},
+			expectedEmail: "admin@example.com",
+			expectedRole:  entity.UserRoleReader,
+		},
+		{
+			name: "Register with existing email",
+			mock: func(m *mocks.UserStorage) {
+				m.On("Create", context.Background(), isJane).Return(nil, ErrCreateUserEmailExists)
+			},
+			input:       RegisterOpt{Email: "jane@example.com", Name: "Jane", Password: "password"},
+			expectedErr: ErrCreateUserEmailExists,
+			expectErr:   true,
+		},
+		{
+			name: "Register with unexpected error in storage",
+			mock: func(m *mocks.UserStorage) {
+				m.On("Create", context.Background(), isJane).Return(nil, errors.New("error!"))
+			},
+			input:     RegisterOpt{Email: "jane@example.com", Name: "Jane", Password: "password"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userStorageMock := mocks.NewUserStorage(t)
+			tc.mock(userStorageMock)
+			storages := Storages{User: userStorageMock}
+
+			authService := NewAuthService(storages, testAuthOptions, logger)
+			actual, err := authService.Register(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to register")
+				require.NotEmpty(t, actual.ID, "ID is empty")
+				require.Equal(t, tc.expectedEmail, actual.Email, "email is not normalized")
+				require.Equal(t, tc.expectedRole, actual.Role, "roles are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "user is not nil")
+			}
+		})
+	}
+}
+
+func TestAuthService_Login(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err, "failed to hash password")
+	user := &entity.User{ID: uuid.NewString(), Email: "jane@example.com", PasswordHash: string(passwordHash)}
+
+	testCases := []struct {
+		name        string
+		userMock    func(m *mocks.UserStorage)
+		refreshMock func(m *mocks.RefreshTokenStorage)
+		input       LoginOpt
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Login",
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByEmail", context.Background(), "jane@example.com").Return(user, nil)
+			},
+			refreshMock: func(m *mocks.RefreshTokenStorage) {
+				m.On("Create", context.Background(), mock.MatchedBy(func(rt *entity.RefreshToken) bool {
+					return rt.UserID == user.ID && rt.FamilyID != ""
+				})).Return(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+					return rt, nil
+				})
+			},
+			input: LoginOpt{Email: "Jane@example.com", Password: "password"},
+		},
+		{
+			name: "Login with wrong password",
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByEmail", context.Background(), "jane@example.com").Return(user, nil)
+			},
+			input:       LoginOpt{Email: "jane@example.com", Password: "wrong password"},
+			expectedErr: ErrInvalidCredentials,
+			expectErr:   true,
+		},
+		{
+			name: "Login of user created by identity provider",
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByEmail", context.Background(), "john@example.com").Return(&entity.User{ID: uuid.NewString(), Email: "john@example.com"}, nil)
+			},
+			input:       LoginOpt{Email: "john@example.com", Password: "password"},
+			expectedErr: ErrInvalidCredentials,
+			expectErr:   true,
+		},
+		{
+			name: "Login with unknown email",
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByEmail", context.Background(), "john@example.com").Return(nil, ErrGetUserNotFound)
+			},
+			input:       LoginOpt{Email: "john@example.com", Password: "password"},
+			expectedErr: ErrInvalidCredentials,
+			expectErr:   true,
+		},
+		{
+			name: "Login with unexpected error in storage",
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByEmail", context.Background(), "jane@example.com").Return(nil, errors.New("error!"))
+			},
+			input:     LoginOpt{Email: "jane@example.com", Password: "password"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userStorageMock := mocks.NewUserStorage(t)
+			tc.userMock(userStorageMock)
+			refreshTokenStorageMock := mocks.NewRefreshTokenStorage(t)
+			if tc.refreshMock != nil {
+				tc.refreshMock(refreshTokenStorageMock)
+			}
+			storages := Storages{User: userStorageMock, RefreshToken: refreshTokenStorageMock}
+
+			authService := NewAuthService(storages, testAuthOptions, logger)
+			actual, err := authService.Login(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to login")
+
+				principal, err := authService.Authenticate(context.Background(), actual.AccessToken)
+				require.NoError(t, err, "failed to authenticate with access token")
+				require.Equal(t, user.ID, principal.UserID, "user IDs are not equal")
+
+				_, err = authService.Authenticate(context.Background(), actual.RefreshToken)
+				require.ErrorIs(t, err, ErrInvalidToken, "refresh token is accepted as access token")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "tokens are not nil")
+			}
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	tokenID := uuid.NewString()
+	familyID := uuid.NewString()
+	user := &entity.User{ID: uuid.NewString(), Email: "jane@example.com"}
+	refreshToken := testToken(t, testAuthOptions.Secret, refreshTokenType, tokenID, time.Hour)
+
+	testCases := []struct {
+		name        string
+		userMock    func(m *mocks.UserStorage)
+		refreshMock func(m *mocks.RefreshTokenStorage)
+		input       string
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Refresh",
+			userMock: func(m *mocks.UserStorage) {
+				m.On("Get", context.Background(), user.ID).Return(user, nil)
+			},
+			refreshMock: func(m *mocks.RefreshTokenStorage) {
+				m.On("Use", context.Background(), tokenID, mock.AnythingOfType("time.Time")).Return(&entity.RefreshToken{ID: tokenID, UserID: user.ID, FamilyID: familyID}, nil)
+				// the next token continues the family
+				m.On("Create", context.Background(), mock.MatchedBy(func(rt *entity.RefreshToken) bool {
+					return rt.FamilyID == familyID && rt.ID != tokenID
+				})).Return(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+					return rt, nil
+				})
+			},
+			input: refreshToken,
+		},
+		{
+			name: "Refresh with reused token",
+			refreshMock: func(m *mocks.RefreshTokenStorage) {
+				m.On("Use", context.Background(), tokenID, mock.AnythingOfType("time.Time")).Return(nil, ErrUseRefreshTokenUsed)
+				m.On("RevokeFamily", context.Background(), tokenID, mock.AnythingOfType("time.Time")).Return(nil)
+			},
+			input:       refreshToken,
+			expectedErr: ErrRefreshTokenReused,
+			expectErr:   true,
+		},
+		{
+			name: "Refresh with unknown token",
+			refreshMock: func(m *mocks.RefreshTokenStorage) {
+				m.On("Use", context.Background(), tokenID, mock.AnythingOfType("time.Time")).Return(nil, ErrUseRefreshTokenNotFound)
+			},
+			input:       refreshToken,
+			expectedErr: ErrInvalidToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Refresh with access token",
+			input:       testToken(t, testAuthOptions.Secret, accessTokenType, tokenID, time.Hour),
+			expectedErr: ErrInvalidToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Refresh with expired token",
+			input:       testToken(t, testAuthOptions.Secret, refreshTokenType, tokenID, -time.Minute),
+			expectedErr: ErrInvalidToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Refresh with token signed by another secret",
+			input:       testToken(t, []byte("another secret"), refreshTokenType, tokenID, time.Hour),
+			expectedErr: ErrInvalidToken,
+			expectErr:   true,
+		},
+		{
+			name: "Refresh with unexpected error in storage",
+			refreshMock: func(m *mocks.RefreshTokenStorage) {
+				m.On("Use", context.Background(), tokenID, mock.AnythingOfType("time.Time")).Return(nil, errors.New("error!"))
+			},
+			input:     refreshToken,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userStorageMock := mocks.NewUserStorage(t)
+			if tc.userMock != nil {
+				tc.userMock(userStorageMock)
+			}
+			refreshTokenStorageMock := mocks.NewRefreshTokenStorage(t)
+			if tc.refreshMock != nil {
+				tc.refreshMock(refreshTokenStorageMock)
+			}
+			storages := Storages{User: userStorageMock, RefreshToken: refreshTokenStorageMock}
+
+			authService := NewAuthService(storages, testAuthOptions, logger)
+			actual, err := authService.Refresh(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to refresh")
+				require.NotEqual(t, tc.input, actual.RefreshToken, "refresh token is not rotated")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "tokens are not nil")
+			}
+		})
+	}
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		Type:             accessTokenType,
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err, "failed to make unsigned token")
+
+	withoutRole, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: uuid.NewString(), ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		Type:             accessTokenType,
+		Email:            "jane@example.com",
+	}).SignedString(testAuthOptions.Secret)
+	require.NoError(t, err, "failed to make token without role")
+
+	testCases := []struct {
+		name         string
+		input        string
+		expectedRole entity.UserRole
+		expectErr    bool
+	}{
+		{
+			name:         "Authenticate",
+			input:        testToken(t, testAuthOptions.Secret, accessTokenType, uuid.NewString(), time.Hour),
+			expectedRole: entity.UserRoleAuthor,
+		},
+		{
+			name:         "Authenticate with token without role",
+			input:        withoutRole,
+			expectedRole: entity.UserRoleReader,
+		},
+		{
+			name:      "Authenticate with expired token",
+			input:     testToken(t, testAuthOptions.Secret, accessTokenType, uuid.NewString(), -time.Minute),
+			expectErr: true,
+		},
+		{
+			name:      "Authenticate with unsigned token",
+			input:     unsigned,
+			expectErr: true,
+		},
+		{
+			name:      "Authenticate with malformed token",
+			input:     "token",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			authService := NewAuthService(Storages{}, testAuthOptions, logger)
+			actual, err := authService.Authenticate(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to authenticate")
+				require.NotEmpty(t, actual.UserID, "user ID is empty")
+				require.Equal(t, "jane@example.com", actual.Email, "emails are not equal")
+				require.Equal(t, tc.expectedRole, actual.Role, "roles are not equal")
+			} else {
+				require.ErrorIs(t, err, ErrInvalidToken, "unexpected error")
+				require.Nil(t, actual, "principal is not nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"fmt"
+	"regexp"
+)
+
+var _ AuthorService = (*authorService)(nil)
+
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type authorService struct {
+	storages Storages
+	logger   logging.Logger
+}
+
+func NewAuthorService(storages Storages, logger logging.Logger) *authorService {
+	return &authorService{storages, logger.Named("authorService")}
+}
+
+func (s *authorService) Create(ctx context.Context, opt CreateAuthorOpt) (*entity.Author, error) {
+	logger := s.logger.Named("Create")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if !slugRegexp.MatchString(opt.Slug) {
+		logger.Info("invalid slug", "slug", opt.Slug)
+		return nil, ErrInvalidSlug
+	}
+
+	createdAuthor, err := s.storages.Author.Create(ctx, &entity.Author{
+		Name:      opt.Name,
+		Slug:      opt.Slug,
+		Bio:       opt.Bio,
+		AvatarURL: opt.AvatarURL,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to create author", "err", err)
+		return nil, fmt.Errorf("failed to create author: %w", err)
+	}
+
+	logger.Info("successfully created author", "createdAuthor", createdAuthor)
+	return createdAuthor, nil
+}
+
+func (s *authorService) List(ctx context.Context) ([]entity.Author, error) {
+	logger := s.logger.Named("List")
+
+	authors, err := s.storages.Author.List(ctx)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list authors", "err", err)
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+
+	logger.Info("successfully listed authors", "authors", authors)
+	return authors, nil
+}
+
+func (s *authorService) Get(ctx context.Context, id string) (*entity.Author, error) {
+	logger := s.logger.Named("Get")
+
+	author, err := s.storages.Author.Get(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get author", "err", err)
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+
+	logger.Info("successfully got author", "author", author)
+	return author, nil
+}
+
+// postAuthors builds bylines in the order of the IDs skipping duplicates,
+// ErrUnknownAuthor is returned if any of the authors doesn't exist
+func postAuthors(ctx context.Context, storage AuthorStorage, ids []string) ([]entity.PostAuthor, error) {
+	uniqueIDs := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+	if len(uniqueIDs) == 0 {
+		return []entity.PostAuthor{}, nil
+	}
+
+	authors, err := storage.GetMany(ctx, uniqueIDs)
+	if err != nil {
+		if errs.IsCustom(err) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+	if len(authors) != len(uniqueIDs) {
+		return nil, ErrUnknownAuthor
+	}
+
+	bylines := make([]entity.PostAuthor, 0, len(uniqueIDs))
+	for i, id := range uniqueIDs {
+		bylines = append(bylines, entity.PostAuthor{AuthorID: id, Position: i})
+	}
+
+	return bylines, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorService_Create(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	author := &entity.Author{Name: "Jane Doe", Slug: "jane-doe", Bio: "bio", AvatarURL: "https://example.com/jane.png"}
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.AuthorStorage)
+		input       CreateAuthorOpt
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Create",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("Create", editorCtx, author).Return(&entity.Author{
+					ID:        uuid.NewString(),
+					Name:      "Jane Doe",
+					Slug:      "jane-doe",
+					Bio:       "bio",
+					AvatarURL: "https://example.com/jane.png",
+				}, nil)
+			},
+			input: CreateAuthorOpt{Name: "Jane Doe", Slug: "jane-doe", Bio: "bio", AvatarURL: "https://example.com/jane.png"},
+		},
+		{
+			name:        "Create with invalid slug",
+			mock:        func(m *mocks.AuthorStorage) {},
+			input:       CreateAuthorOpt{Name: "Jane Doe", Slug: "Jane--Doe"},
+			expectedErr: ErrInvalidSlug,
+			expectErr:   true,
+		},
+		{
+			name: "Create with existing slug",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("Create", editorCtx, author).Return(nil, ErrCreateAuthorSlugExists)
+			},
+			input:       CreateAuthorOpt{Name: "Jane Doe", Slug: "jane-doe", Bio: "bio", AvatarURL: "https://example.com/jane.png"},
+			expectedErr: ErrCreateAuthorSlugExists,
+			expectErr:   true,
+		},
+		{
+			name: "Create with unexpected error in storage",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("Create", editorCtx, author).Return(nil, errors.New("error!"))
+			},
+			input:     CreateAuthorOpt{Name: "Jane Doe", Slug: "jane-doe", Bio: "bio", AvatarURL: "https://example.com/jane.png"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			authorStorageMock := mocks.NewAuthorStorage(t)
+			tc.mock(authorStorageMock)
+			storages := Storages{Author: authorStorageMock}
+
+			authorService := NewAuthorService(storages, logger)
+			actual, err := authorService.Create(editorCtx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to create author")
+				require.NotEmpty(t, actual.ID, "ID is empty")
+				require.Equal(t, tc.input.Slug, actual.Slug, "slugs are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "author is not nil")
+			}
+		})
+	}
+}
+
+func TestAuthorService_List(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.AuthorStorage)
+		expectedLen int
+		expectErr   bool
+	}{
+		{
+			name: "List",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("List", context.Background()).Return([]entity.Author{{Name: "Jane Doe"}, {Name: "John Doe"}}, nil)
+			},
+			expectedLen: 2,
+		},
+		{
+			name: "List with unexpected error in storage",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("List", context.Background()).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			authorStorageMock := mocks.NewAuthorStorage(t)
+			tc.mock(authorStorageMock)
+			storages := Storages{Author: authorStorageMock}
+
+			authorService := NewAuthorService(storages, logger)
+			actual, err := authorService.List(context.Background())
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list authors")
+				require.Equal(t, tc.expectedLen, len(actual), "len is not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "authors are not nil")
+			}
+		})
+	}
+}
+
+func TestAuthorService_Get(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	authorID := uuid.NewString()
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.AuthorStorage)
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Get",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("Get", context.Background(), authorID).Return(&entity.Author{ID: authorID, Name: "Jane Doe"}, nil)
+			},
+		},
+		{
+			name: "Get unknown author",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("Get", context.Background(), authorID).Return(nil, ErrGetAuthorNotFound)
+			},
+			expectedErr: ErrGetAuthorNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Get with unexpected error in storage",
+			mock: func(m *mocks.AuthorStorage) {
+				m.On("Get", context.Background(), authorID).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			authorStorageMock := mocks.NewAuthorStorage(t)
+			tc.mock(authorStorageMock)
+			storages := Storages{Author: authorStorageMock}
+
+			authorService := NewAuthorService(storages, logger)
+			actual, err := authorService.Get(context.Background(), authorID)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to get author")
+				require.Equal(t, authorID, actual.ID, "IDs are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "author is not nil")
+			}
+		})
+	}
+}
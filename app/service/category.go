@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+)
+
+var _ CategoryService = (*categoryService)(nil)
+
+type categoryService struct {
+	storages Storages
+	logger   logging.Logger
+}
+
+func NewCategoryService(storages Storages, logger logging.Logger) *categoryService {
+	return &categoryService{storages, logger.Named("categoryService")}
+}
+
+func (s *categoryService) Create(ctx context.Context, opt CreateCategoryOpt) (*entity.Category, error) {
+	logger := s.logger.Named("Create")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if opt.ParentID != nil {
+		err = checkCategoryExists(ctx, s.storages.Category, *opt.ParentID)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to check parent category", "err", err)
+			return nil, fmt.Errorf("failed to check parent category: %w", err)
+		}
+	}
+
+	createdCategory, err := s.storages.Category.Create(ctx, &entity.Category{
+		Name:     opt.Name,
+		ParentID: opt.ParentID,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to create category", "err", err)
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	logger.Info("successfully created category", "createdCategory", createdCategory)
+	return createdCategory, nil
+}
+
+func (s *categoryService) List(ctx context.Context) ([]entity.Category, error) {
+	logger := s.logger.Named("List")
+
+	categories, err := s.storages.Category.List(ctx)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list categories", "err", err)
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	logger.Info("successfully listed categories", "categories", categories)
+	return categories, nil
+}
+
+func (s *categoryService) Get(ctx context.Context, id string) (*entity.Category, error) {
+	logger := s.logger.Named("Get")
+
+	category, err := s.storages.Category.Get(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get category", "err", err)
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	logger.Info("successfully got category", "category", category)
+	return category, nil
+}
+
+func (s *categoryService) Update(ctx context.Context, id string, opt UpdateCategoryOpt) (*entity.Category, error) {
+	logger := s.logger.Named("Update")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if opt.ParentID != nil {
+		err = s.checkParent(ctx, id, *opt.ParentID)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to check parent category", "err", err)
+			return nil, fmt.Errorf("failed to check parent category: %w", err)
+		}
+	}
+
+	updatedCategory, err := s.storages.Category.Update(ctx, id, entity.CategoryUpdate{
+		Name:     opt.Name,
+		ParentID: opt.ParentID,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to update category", "err", err)
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+
+	logger.Info("successfully updated category", "updatedCategory", updatedCategory)
+	return updatedCategory, nil
+}
+
+// checkParent makes sure the parent exists and isn't in the subtree of the category, so the tree has no cycles
+func (s *categoryService) checkParent(ctx context.Context, id, parentID string) error {
+	subtreeIDs, err := s.storages.Category.SubtreeIDs(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get category subtree: %w", err)
+	}
+	if len(subtreeIDs) == 0 {
+		return ErrGetCategoryNotFound
+	}
+
+	for _, subtreeID := range subtreeIDs {
+		if subtreeID == parentID {
+			return ErrCategoryCycle
+		}
+	}
+
+	return checkCategoryExists(ctx, s.storages.Category, parentID)
+}
+
+func (s *categoryService) Delete(ctx context.Context, id string) error {
+	logger := s.logger.Named("Delete")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return err
+	}
+
+	err = s.storages.Category.Delete(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to delete category", "err", err)
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	logger.Info("successfully deleted category", "id", id)
+	return nil
+}
+
+// checkCategoryExists returns ErrUnknownCategory if the category referred by a post or another category doesn't exist
+func checkCategoryExists(ctx context.Context, storage CategoryStorage, id string) error {
+	_, err := storage.Get(ctx, id)
+	if errors.Is(err, ErrGetCategoryNotFound) {
+		return ErrUnknownCategory
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return nil
+}
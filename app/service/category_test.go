@@ -0,0 +1,246 @@
+package service
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryService_Create(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	parentID := uuid.NewString()
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.CategoryStorage)
+		input       CreateCategoryOpt
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Create",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Create", editorCtx, &entity.Category{Name: "World"}).
+					Return(&entity.Category{ID: uuid.NewString(), Name: "World"}, nil)
+			},
+			input: CreateCategoryOpt{Name: "World"},
+		},
+		{
+			name: "Create subcategory",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Get", editorCtx, parentID).Return(&entity.Category{ID: parentID, Name: "World"}, nil)
+				m.On("Create", editorCtx, &entity.Category{Name: "Europe", ParentID: &parentID}).
+					Return(&entity.Category{ID: uuid.NewString(), Name: "Europe", ParentID: &parentID}, nil)
+			},
+			input: CreateCategoryOpt{Name: "Europe", ParentID: &parentID},
+		},
+		{
+			name: "Create subcategory of unknown category",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Get", editorCtx, parentID).Return(nil, ErrGetCategoryNotFound)
+			},
+			input:       CreateCategoryOpt{Name: "Europe", ParentID: &parentID},
+			expectedErr: ErrUnknownCategory,
+			expectErr:   true,
+		},
+		{
+			name: "Create with unexpected error in storage",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Create", editorCtx, &entity.Category{Name: "World"}).Return(nil, errors.New("error!"))
+			},
+			input:     CreateCategoryOpt{Name: "World"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			categoryStorageMock := mocks.NewCategoryStorage(t)
+			tc.mock(categoryStorageMock)
+			storages := Storages{Category: categoryStorageMock}
+
+			categoryService := NewCategoryService(storages, logger)
+			actual, err := categoryService.Create(editorCtx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to create category")
+				require.Equal(t, tc.input.Name, actual.Name, "names are not equal")
+				require.Equal(t, tc.input.ParentID, actual.ParentID, "parents are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "category is not nil")
+			}
+		})
+	}
+}
+
+func TestCategoryService_Update(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	categoryID := uuid.NewString()
+	childID := uuid.NewString()
+	parentID := uuid.NewString()
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.CategoryStorage)
+		input       UpdateCategoryOpt
+		expectedErr error
+	}{
+		{
+			name: "Update to top-level",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Update", editorCtx, categoryID, entity.CategoryUpdate{Name: "Europe"}).
+					Return(&entity.Category{ID: categoryID, Name: "Europe"}, nil)
+			},
+			input: UpdateCategoryOpt{Name: "Europe"},
+		},
+		{
+			name: "Update moving to another parent",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("SubtreeIDs", editorCtx, categoryID).Return([]string{categoryID, childID}, nil)
+				m.On("Get", editorCtx, parentID).Return(&entity.Category{ID: parentID}, nil)
+				m.On("Update", editorCtx, categoryID, entity.CategoryUpdate{Name: "Europe", ParentID: &parentID}).
+					Return(&entity.Category{ID: categoryID, Name: "Europe", ParentID: &parentID}, nil)
+			},
+			input: UpdateCategoryOpt{Name: "Europe", ParentID: &parentID},
+		},
+		{
+			name: "Update moving under itself",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("SubtreeIDs", editorCtx, categoryID).Return([]string{categoryID, childID}, nil)
+			},
+			input:       UpdateCategoryOpt{Name: "Europe", ParentID: &categoryID},
+			expectedErr: ErrCategoryCycle,
+		},
+		{
+			name: "Update moving under descendant",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("SubtreeIDs", editorCtx, categoryID).Return([]string{categoryID, childID}, nil)
+			},
+			input:       UpdateCategoryOpt{Name: "Europe", ParentID: &childID},
+			expectedErr: ErrCategoryCycle,
+		},
+		{
+			name: "Update moving to unknown parent",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("SubtreeIDs", editorCtx, categoryID).Return([]string{categoryID}, nil)
+				m.On("Get", editorCtx, parentID).Return(nil, ErrGetCategoryNotFound)
+			},
+			input:       UpdateCategoryOpt{Name: "Europe", ParentID: &parentID},
+			expectedErr: ErrUnknownCategory,
+		},
+		{
+			name: "Update moving unknown category",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("SubtreeIDs", editorCtx, categoryID).Return([]string{}, nil)
+			},
+			input:       UpdateCategoryOpt{Name: "Europe", ParentID: &parentID},
+			expectedErr: ErrGetCategoryNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			categoryStorageMock := mocks.NewCategoryStorage(t)
+			tc.mock(categoryStorageMock)
+			storages := Storages{Category: categoryStorageMock}
+
+			categoryService := NewCategoryService(storages, logger)
+			actual, err := categoryService.Update(editorCtx, categoryID, tc.input)
+			if tc.expectedErr == nil {
+				require.NoError(t, err, "failed to update category")
+				require.Equal(t, tc.input.ParentID, actual.ParentID, "parents are not equal")
+			} else {
+				require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				require.Nil(t, actual, "category is not nil")
+			}
+		})
+	}
+}
+
+func TestCategoryService_Delete(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	categoryID := uuid.NewString()
+
+	testCases := []struct {
+		name      string
+		mock      func(m *mocks.CategoryStorage)
+		expectErr bool
+	}{
+		{
+			name: "Delete",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Delete", editorCtx, categoryID).Return(nil)
+			},
+		},
+		{
+			name: "Delete not empty",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Delete", editorCtx, categoryID).Return(ErrDeleteCategoryNotEmpty)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Delete with unexpected error in storage",
+			mock: func(m *mocks.CategoryStorage) {
+				m.On("Delete", editorCtx, categoryID).Return(errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			categoryStorageMock := mocks.NewCategoryStorage(t)
+			tc.mock(categoryStorageMock)
+			storages := Storages{Category: categoryStorageMock}
+
+			categoryService := NewCategoryService(storages, logger)
+			err := categoryService.Delete(editorCtx, categoryID)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to delete category")
+			} else {
+				require.Error(t, err, "no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var _ CommentService = (*commentService)(nil)
+
+const (
+	defaultListCommentsLimit = 20
+	maxListCommentsLimit     = 100
+
+	commentTokenSize = 32
+)
+
+type commentService struct {
+	storages Storages
+	// premoderation is the global policy, posts can override it
+	premoderation bool
+	logger        logging.Logger
+}
+
+func NewCommentService(storages Storages, premoderation bool, logger logging.Logger) *commentService {
+	return &commentService{storages, premoderation, logger.Named("commentService")}
+}
+
+func (s *commentService) Create(ctx context.Context, opt CreateCommentOpt) (*CreateCommentResult, error) {
+	logger := s.logger.Named("Create")
+
+	post, err := s.getPublishedPost(ctx, opt.PostID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post", "err", err)
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	if opt.ParentID != nil {
+		parent, err := s.storages.Comment.Get(ctx, *opt.ParentID)
+		if err != nil && !errs.IsCustom(err) {
+			logger.Error("failed to get parent comment", "err", err)
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+
+		// deleted and not approved comments can't be answered, but replies of deleted ones stay
+		if err != nil || parent.PostID != opt.PostID || !parent.IsCounted() {
+			logger.Info("unknown parent comment", "parentID", *opt.ParentID)
+			return nil, ErrUnknownParentComment
+		}
+	}
+
+	token, err := newCommentToken()
+	if err != nil {
+		logger.Error("failed to generate comment token", "err", err)
+		return nil, fmt.Errorf("failed to generate comment token: %w", err)
+	}
+
+	status := entity.CommentStatusApproved
+	if premoderated(post, s.premoderation) {
+		status = entity.CommentStatusPending
+	}
+
+	createdComment, err := s.storages.Comment.Create(ctx, &entity.Comment{
+		PostID:     opt.PostID,
+		ParentID:   opt.ParentID,
+		AuthorName: opt.AuthorName,
+		Content:    opt.Content,
+		TokenHash:  hashCommentToken(token),
+		Status:     status,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to create comment", "err", err)
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	logger.Info("successfully created comment", "createdComment", createdComment)
+	return &CreateCommentResult{Comment: createdComment, Token: token}, nil
+}
+
+func (s *commentService) List(ctx context.Context, opt ListCommentsOpt) (*ListCommentsResult, error) {
+	logger := s.logger.Named("List")
+
+	_, err := s.getPublishedPost(ctx, opt.PostID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post", "err", err)
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	if opt.ParentID != nil {
+		// replies of deleted comments are listed, so the placeholder can be expanded
+		parent, err := s.postComment(ctx, opt.PostID, *opt.ParentID)
+		if err == nil && parent.Status != entity.CommentStatusApproved {
+			err = ErrGetCommentNotFound
+		}
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to get parent comment", "err", err)
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+	}
+
+	limit := listCommentsLimit(opt.Limit)
+	filter := entity.CommentsFilter{
+		PostID:   opt.PostID,
+		Status:   entity.CommentStatusApproved,
+		ParentID: opt.ParentID,
+		// one extra comment is requested to find out whether the next page exists
+		Limit: limit + 1,
+	}
+	if opt.Cursor != "" {
+		cursor, err := decodeCommentsCursor(opt.Cursor)
+		if err != nil {
+			logger.Info("failed to decode cursor", "err", err)
+			return nil, ErrListCommentsInvalidCursor
+		}
+
+		filter.After = cursor
+	}
+	logger.Debug("built filter", "filter", filter)
+
+	comments, err := s.storages.Comment.List(ctx, filter)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list comments", "err", err)
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	result, err := commentsPage(comments, limit)
+	if err != nil {
+		logger.Error("failed to make comments page", "err", err)
+		return nil, fmt.Errorf("failed to make comments page: %w", err)
+	}
+
+	logger.Info("successfully listed comments", "result", result)
+	return result, nil
+}
+
+func (s *commentService) Update(ctx context.Context, opt UpdateCommentOpt) (*entity.Comment, error) {
+	logger := s.logger.Named("Update")
+
+	comment, err := s.checkCommentToken(ctx, opt.PostID, opt.ID, opt.Token)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to check comment token", "err", err)
+		return nil, fmt.Errorf("failed to check comment token: %w", err)
+	}
+
+	update := entity.CommentUpdate{Content: &opt.Content}
+	// edits are moderated the same way as new comments, otherwise approval could be bypassed
+	if comment.Status == entity.CommentStatusApproved {
+		post, err := s.storages.Post.Get(ctx, opt.PostID)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to get post", "err", err)
+			return nil, fmt.Errorf("failed to get post: %w", err)
+		}
+
+		if premoderated(post, s.premoderation) {
+			pending := entity.CommentStatusPending
+			update.Status = &pending
+		}
+	}
+
+	updatedComment, err := s.storages.Comment.Update(ctx, opt.ID, update)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to update comment", "err", err)
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	logger.Info("successfully updated comment", "updatedComment", updatedComment)
+	return updatedComment, nil
+}
+
+func (s *commentService) Delete(ctx context.Context, opt DeleteCommentOpt) error {
+	logger := s.logger.Named("Delete")
+
+	_, err := s.checkCommentToken(ctx, opt.PostID, opt.ID, opt.Token)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to check comment token", "err", err)
+		return fmt.Errorf("failed to check comment token: %w", err)
+	}
+
+	err = s.storages.Comment.Delete(ctx, opt.ID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to delete comment", "err", err)
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	logger.Info("successfully deleted comment")
+	return nil
+}
+
+// getPublishedPost returns the post only if it's published, otherwise ErrGetPostNotFound,
+// so drafts and archived posts can't be commented and their comments aren't listed
+func (s *commentService) getPublishedPost(ctx context.Context, id string) (*entity.Post, error) {
+	post, err := s.storages.Post.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != entity.PostStatusPublished {
+		return nil, ErrGetPostNotFound
+	}
+
+	return post, nil
+}
+
+// postComment returns the comment only if it belongs to the post, otherwise ErrGetCommentNotFound
+func (s *commentService) postComment(ctx context.Context, postID, id string) (*entity.Comment, error) {
+	comment, err := s.storages.Comment.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.PostID != postID {
+		return nil, ErrGetCommentNotFound
+	}
+
+	return comment, nil
+}
+
+// checkCommentToken makes sure the comment of the post isn't deleted and the token is the one given on its creation
+func (s *commentService) checkCommentToken(ctx context.Context, postID, id, token string) (*entity.Comment, error) {
+	comment, err := s.postComment(ctx, postID, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt != nil {
+		return nil, ErrGetCommentNotFound
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashCommentToken(token)), []byte(comment.TokenHash)) != 1 {
+		return nil, ErrInvalidCommentToken
+	}
+
+	return comment, nil
+}
+
+func listCommentsLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListCommentsLimit
+	}
+	if limit > maxListCommentsLimit {
+		return maxListCommentsLimit
+	}
+
+	return limit
+}
+
+// commentsPage cuts the extra comment requested to find out whether the next page exists
+func commentsPage(comments []entity.Comment, limit int) (*ListCommentsResult, error) {
+	result := ListCommentsResult{Comments: comments}
+	if len(comments) <= limit {
+		return &result, nil
+	}
+
+	result.Comments = comments[:limit]
+
+	last := result.Comments[limit-1]
+	cursor, err := encodeCommentsCursor(&entity.CommentsCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor = cursor
+
+	return &result, nil
+}
+
+func newCommentToken() (string, error) {
+	b := make([]byte, commentTokenSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashCommentToken uses a plain hash, tokens are random enough not to need a slow one
+func hashCommentToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// encodeCommentsCursor makes an opaque cursor the same way as encodePostsCursor
+func encodeCommentsCursor(c *entity.CommentsCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCommentsCursor(s string) (*entity.CommentsCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+
+	var c entity.CommentsCursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cursor: %w", err)
+	}
+
+	err = uuid.Validate(c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cursor has invalid ID: %w", err)
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,604 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentService_Create(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	parentID := uuid.NewString()
+	deletedAt := time.Now()
+	premoderation := true
+	noPremoderation := false
+
+	createComment := func(_ context.Context, c *entity.Comment) (*entity.Comment, error) {
+		c.ID = uuid.NewString()
+		return c, nil
+	}
+
+	testCases := []struct {
+		name           string
+		postMock       func(m *mocks.PostStorage)
+		commentMock    func(m *mocks.CommentStorage)
+		premoderation  bool
+		input          CreateCommentOpt
+		expectedStatus entity.CommentStatus
+		expectedErr    error
+		expectErr      bool
+	}{
+		{
+			name: "Create",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Create", context.Background(), mock.AnythingOfType("*entity.Comment")).Return(createComment)
+			},
+			input:          CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectedStatus: entity.CommentStatusApproved,
+		},
+		{
+			name: "Create with global premoderation",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Create", context.Background(), mock.AnythingOfType("*entity.Comment")).Return(createComment)
+			},
+			premoderation:  true,
+			input:          CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectedStatus: entity.CommentStatusPending,
+		},
+		{
+			name: "Create for pre-moderated post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished, CommentsPremoderation: &premoderation}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Create", context.Background(), mock.AnythingOfType("*entity.Comment")).Return(createComment)
+			},
+			input:          CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectedStatus: entity.CommentStatusPending,
+		},
+		{
+			name: "Create for post without premoderation when it's global",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished, CommentsPremoderation: &noPremoderation}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Create", context.Background(), mock.AnythingOfType("*entity.Comment")).Return(createComment)
+			},
+			premoderation:  true,
+			input:          CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectedStatus: entity.CommentStatusApproved,
+		},
+		{
+			name: "Create reply",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(&entity.Comment{ID: parentID, PostID: postID, Status: entity.CommentStatusApproved}, nil)
+				m.On("Create", context.Background(), mock.AnythingOfType("*entity.Comment")).Return(createComment)
+			},
+			input:          CreateCommentOpt{PostID: postID, ParentID: &parentID, AuthorName: "Jane", Content: "content"},
+			expectedStatus: entity.CommentStatusApproved,
+		},
+		{
+			name: "Create for non-existent post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(nil, ErrGetPostNotFound)
+			},
+			input:       CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Create for draft post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusDraft}, nil)
+			},
+			input:       CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Create for archived post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusArchived}, nil)
+			},
+			input:       CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Create reply to non-existent comment",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(nil, ErrGetCommentNotFound)
+			},
+			input:       CreateCommentOpt{PostID: postID, ParentID: &parentID, AuthorName: "Jane", Content: "content"},
+			expectedErr: ErrUnknownParentComment,
+			expectErr:   true,
+		},
+		{
+			name: "Create reply to comment of another post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(&entity.Comment{ID: parentID, PostID: uuid.NewString()}, nil)
+			},
+			input:       CreateCommentOpt{PostID: postID, ParentID: &parentID, AuthorName: "Jane", Content: "content"},
+			expectedErr: ErrUnknownParentComment,
+			expectErr:   true,
+		},
+		{
+			name: "Create reply to deleted comment",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(&entity.Comment{ID: parentID, PostID: postID, Status: entity.CommentStatusApproved, DeletedAt: &deletedAt}, nil)
+			},
+			input:       CreateCommentOpt{PostID: postID, ParentID: &parentID, AuthorName: "Jane", Content: "content"},
+			expectedErr: ErrUnknownParentComment,
+			expectErr:   true,
+		},
+		{
+			name: "Create reply to pending comment",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(&entity.Comment{ID: parentID, PostID: postID, Status: entity.CommentStatusPending}, nil)
+			},
+			input:       CreateCommentOpt{PostID: postID, ParentID: &parentID, AuthorName: "Jane", Content: "content"},
+			expectedErr: ErrUnknownParentComment,
+			expectErr:   true,
+		},
+		{
+			name: "Create with unexpected error in storage",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Create", context.Background(), mock.AnythingOfType("*entity.Comment")).Return(nil, errors.New("error!"))
+			},
+			input:     CreateCommentOpt{PostID: postID, AuthorName: "Jane", Content: "content"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.postMock(postStorageMock)
+			commentStorageMock := mocks.NewCommentStorage(t)
+			if tc.commentMock != nil {
+				tc.commentMock(commentStorageMock)
+			}
+			storages := Storages{Post: postStorageMock, Comment: commentStorageMock}
+
+			commentService := NewCommentService(storages, tc.premoderation, logger)
+			actual, err := commentService.Create(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to create comment")
+				require.NotEmpty(t, actual.Comment.ID, "ID is empty")
+				require.NotEmpty(t, actual.Token, "token is empty")
+				require.Equal(t, hashCommentToken(actual.Token), actual.Comment.TokenHash, "token hash doesn't match token")
+				require.Equal(t, tc.input.ParentID, actual.Comment.ParentID, "parent IDs are not equal")
+				require.Equal(t, tc.expectedStatus, actual.Comment.Status, "statuses are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "result is not nil")
+			}
+		})
+	}
+}
+
+func TestCommentService_List(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	parentID := uuid.NewString()
+
+	comments := make([]entity.Comment, 0, 3)
+	for i := 0; i < 3; i++ {
+		comments = append(comments, entity.Comment{ID: uuid.NewString(), PostID: postID, CreatedAt: time.Now().Add(time.Duration(i) * time.Second)})
+	}
+
+	cursor, err := encodeCommentsCursor(&entity.CommentsCursor{CreatedAt: comments[1].CreatedAt, ID: comments[1].ID})
+	require.NoError(t, err, "failed to encode cursor")
+
+	testCases := []struct {
+		name               string
+		postMock           func(m *mocks.PostStorage)
+		commentMock        func(m *mocks.CommentStorage)
+		input              ListCommentsOpt
+		expectedLen        int
+		expectedNextCursor string
+		expectedErr        error
+		expectErr          bool
+	}{
+		{
+			name: "List top-level comments",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("List", context.Background(), entity.CommentsFilter{PostID: postID, Status: entity.CommentStatusApproved, Limit: defaultListCommentsLimit + 1}).Return(comments, nil)
+			},
+			input:       ListCommentsOpt{PostID: postID},
+			expectedLen: 3,
+		},
+		{
+			name: "List replies",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(&entity.Comment{ID: parentID, PostID: postID, Status: entity.CommentStatusApproved}, nil)
+				m.On("List", context.Background(), entity.CommentsFilter{PostID: postID, Status: entity.CommentStatusApproved, ParentID: &parentID, Limit: defaultListCommentsLimit + 1}).Return(comments[:1], nil)
+			},
+			input:       ListCommentsOpt{PostID: postID, ParentID: &parentID},
+			expectedLen: 1,
+		},
+		{
+			name: "List with next page",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("List", context.Background(), entity.CommentsFilter{PostID: postID, Status: entity.CommentStatusApproved, Limit: 3}).Return(comments, nil)
+			},
+			input:              ListCommentsOpt{PostID: postID, Limit: 2},
+			expectedLen:        2,
+			expectedNextCursor: cursor,
+		},
+		{
+			name: "List with invalid cursor",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			input:       ListCommentsOpt{PostID: postID, Cursor: "invalid"},
+			expectedErr: ErrListCommentsInvalidCursor,
+			expectErr:   true,
+		},
+		{
+			name: "List for non-existent post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(nil, ErrGetPostNotFound)
+			},
+			input:       ListCommentsOpt{PostID: postID},
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "List for draft post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusDraft}, nil)
+			},
+			input:       ListCommentsOpt{PostID: postID},
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "List for archived post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusArchived}, nil)
+			},
+			input:       ListCommentsOpt{PostID: postID},
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "List replies to pending comment",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(&entity.Comment{ID: parentID, PostID: postID, Status: entity.CommentStatusPending}, nil)
+			},
+			input:       ListCommentsOpt{PostID: postID, ParentID: &parentID},
+			expectedErr: ErrGetCommentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "List replies to comment of another post",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), parentID).Return(&entity.Comment{ID: parentID, PostID: uuid.NewString()}, nil)
+			},
+			input:       ListCommentsOpt{PostID: postID, ParentID: &parentID},
+			expectedErr: ErrGetCommentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "List with unexpected error in storage",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusPublished}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("List", context.Background(), entity.CommentsFilter{PostID: postID, Status: entity.CommentStatusApproved, Limit: defaultListCommentsLimit + 1}).Return(nil, errors.New("error!"))
+			},
+			input:     ListCommentsOpt{PostID: postID},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.postMock(postStorageMock)
+			commentStorageMock := mocks.NewCommentStorage(t)
+			if tc.commentMock != nil {
+				tc.commentMock(commentStorageMock)
+			}
+			storages := Storages{Post: postStorageMock, Comment: commentStorageMock}
+
+			commentService := NewCommentService(storages, false, logger)
+			actual, err := commentService.List(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list comments")
+				require.Len(t, actual.Comments, tc.expectedLen, "unexpected number of comments")
+				require.Equal(t, tc.expectedNextCursor, actual.NextCursor, "cursors are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "result is not nil")
+			}
+		})
+	}
+}
+
+func TestCommentService_Update(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	id := uuid.NewString()
+	token := "token"
+	deletedAt := time.Now()
+	edited := "edited"
+	pending := entity.CommentStatusPending
+	comment := &entity.Comment{ID: id, PostID: postID, Content: "content", TokenHash: hashCommentToken(token), Status: entity.CommentStatusApproved}
+	pendingComment := &entity.Comment{ID: id, PostID: postID, Content: "content", TokenHash: hashCommentToken(token), Status: entity.CommentStatusPending}
+
+	testCases := []struct {
+		name          string
+		postMock      func(m *mocks.PostStorage)
+		commentMock   func(m *mocks.CommentStorage)
+		premoderation bool
+		input         UpdateCommentOpt
+		expectedErr   error
+		expectErr     bool
+	}{
+		{
+			name: "Update",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+				m.On("Update", context.Background(), id, entity.CommentUpdate{Content: &edited}).Return(&entity.Comment{ID: id, PostID: postID, Content: "edited"}, nil)
+			},
+			input: UpdateCommentOpt{PostID: postID, ID: id, Token: token, Content: "edited"},
+		},
+		{
+			name: "Update with premoderation",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+				m.On("Update", context.Background(), id, entity.CommentUpdate{Content: &edited, Status: &pending}).Return(&entity.Comment{ID: id, PostID: postID, Content: "edited"}, nil)
+			},
+			premoderation: true,
+			input:         UpdateCommentOpt{PostID: postID, ID: id, Token: token, Content: "edited"},
+		},
+		{
+			name: "Update pending comment",
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(pendingComment, nil)
+				m.On("Update", context.Background(), id, entity.CommentUpdate{Content: &edited}).Return(&entity.Comment{ID: id, PostID: postID, Content: "edited"}, nil)
+			},
+			premoderation: true,
+			input:         UpdateCommentOpt{PostID: postID, ID: id, Token: token, Content: "edited"},
+		},
+		{
+			name: "Update with invalid token",
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+			},
+			input:       UpdateCommentOpt{PostID: postID, ID: id, Token: "invalid", Content: "edited"},
+			expectedErr: ErrInvalidCommentToken,
+			expectErr:   true,
+		},
+		{
+			name: "Update comment of another post",
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+			},
+			input:       UpdateCommentOpt{PostID: uuid.NewString(), ID: id, Token: token, Content: "edited"},
+			expectedErr: ErrGetCommentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Update deleted comment",
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(&entity.Comment{ID: id, PostID: postID, TokenHash: hashCommentToken(token), DeletedAt: &deletedAt}, nil)
+			},
+			input:       UpdateCommentOpt{PostID: postID, ID: id, Token: token, Content: "edited"},
+			expectedErr: ErrGetCommentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Update with unexpected error in storage",
+			postMock: func(m *mocks.PostStorage) {
+				m.On("Get", context.Background(), postID).Return(&entity.Post{ID: postID}, nil)
+			},
+			commentMock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+				m.On("Update", context.Background(), id, entity.CommentUpdate{Content: &edited}).Return(nil, errors.New("error!"))
+			},
+			input:     UpdateCommentOpt{PostID: postID, ID: id, Token: token, Content: "edited"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			if tc.postMock != nil {
+				tc.postMock(postStorageMock)
+			}
+			commentStorageMock := mocks.NewCommentStorage(t)
+			tc.commentMock(commentStorageMock)
+			storages := Storages{Post: postStorageMock, Comment: commentStorageMock}
+
+			commentService := NewCommentService(storages, tc.premoderation, logger)
+			actual, err := commentService.Update(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to update comment")
+				require.Equal(t, tc.input.Content, actual.Content, "contents are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "comment is not nil")
+			}
+		})
+	}
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	id := uuid.NewString()
+	token := "token"
+	comment := &entity.Comment{ID: id, PostID: postID, Content: "content", TokenHash: hashCommentToken(token)}
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.CommentStorage)
+		input       DeleteCommentOpt
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Delete",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+				m.On("Delete", context.Background(), id).Return(nil)
+			},
+			input: DeleteCommentOpt{PostID: postID, ID: id, Token: token},
+		},
+		{
+			name: "Delete with invalid token",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+			},
+			input:       DeleteCommentOpt{PostID: postID, ID: id, Token: ""},
+			expectedErr: ErrInvalidCommentToken,
+			expectErr:   true,
+		},
+		{
+			name: "Delete non-existent comment",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(nil, ErrGetCommentNotFound)
+			},
+			input:       DeleteCommentOpt{PostID: postID, ID: id, Token: token},
+			expectedErr: ErrGetCommentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Delete with unexpected error in storage",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Get", context.Background(), id).Return(comment, nil)
+				m.On("Delete", context.Background(), id).Return(errors.New("error!"))
+			},
+			input:     DeleteCommentOpt{PostID: postID, ID: id, Token: token},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commentStorageMock := mocks.NewCommentStorage(t)
+			tc.mock(commentStorageMock)
+			storages := Storages{Comment: commentStorageMock}
+
+			commentService := NewCommentService(storages, false, logger)
+			err := commentService.Delete(context.Background(), tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to delete comment")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+			}
+		})
+	}
+}
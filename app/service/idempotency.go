@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var _ IdempotencyService = (*idempotencyService)(nil)
+
+type idempotencyService struct {
+	storages Storages
+	ttl      time.Duration
+	logger   logging.Logger
+}
+
+// NewIdempotencyService creates the service which keeps keys and their responses for ttl
+func NewIdempotencyService(storages Storages, ttl time.Duration, logger logging.Logger) *idempotencyService {
+	return &idempotencyService{storages, ttl, logger.Named("idempotencyService")}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, opt BeginIdempotentRequestOpt) (*entity.IdempotencyKey, error) {
+	logger := s.logger.Named("Begin")
+
+	now := time.Now()
+	_, err := s.storages.IdempotencyKey.Create(ctx, &entity.IdempotencyKey{
+		ClientID:    opt.ClientID,
+		Key:         opt.Key,
+		RequestHash: opt.RequestHash,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err == nil {
+		logger.Info("successfully began new request", "clientID", opt.ClientID, "key", opt.Key)
+		return nil, nil
+	}
+	if !errors.Is(err, ErrCreateIdempotencyKeyExists) {
+		logger.Error("failed to create idempotency key", "err", err)
+		return nil, fmt.Errorf("failed to create idempotency key: %w", err)
+	}
+	logger.Debug("idempotency key exists", "clientID", opt.ClientID, "key", opt.Key)
+
+	key, err := s.storages.IdempotencyKey.Get(ctx, opt.ClientID, opt.Key)
+	if err != nil {
+		// the key has been released by the first request in the meantime
+		if errors.Is(err, ErrGetIdempotencyKeyNotFound) {
+			logger.Info("idempotency key has been released", "clientID", opt.ClientID, "key", opt.Key)
+			return nil, ErrIdempotencyKeyInProgress
+		}
+
+		logger.Error("failed to get idempotency key", "err", err)
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	if key.RequestHash != opt.RequestHash {
+		logger.Info("idempotency key is reused for another request", "clientID", opt.ClientID, "key", opt.Key)
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	if !key.Completed {
+		logger.Info("request with idempotency key is in progress", "clientID", opt.ClientID, "key", opt.Key)
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	logger.Info("successfully got completed request", "clientID", opt.ClientID, "key", opt.Key)
+	return key, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, opt CompleteIdempotentRequestOpt) error {
+	logger := s.logger.Named("Complete")
+
+	err := s.storages.IdempotencyKey.Complete(ctx, &entity.IdempotencyKey{
+		ClientID:       opt.ClientID,
+		Key:            opt.Key,
+		ResponseStatus: opt.ResponseStatus,
+		ResponseHeader: opt.ResponseHeader,
+		ResponseBody:   opt.ResponseBody,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to complete idempotency key", "err", err)
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	logger.Info("successfully completed request", "clientID", opt.ClientID, "key", opt.Key)
+	return nil
+}
+
+func (s *idempotencyService) Release(ctx context.Context, clientID, key string) error {
+	logger := s.logger.Named("Release")
+
+	err := s.storages.IdempotencyKey.Delete(ctx, clientID, key)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to delete idempotency key", "err", err)
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	logger.Info("successfully released idempotency key", "clientID", clientID, "key", key)
+	return nil
+}
+
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	logger := s.logger.Named("PurgeExpired")
+
+	purged, err := s.storages.IdempotencyKey.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		logger.Error("failed to delete expired idempotency keys", "err", err)
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	logger.Info("successfully purged expired idempotency keys", "purged", purged)
+	return purged, nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	input := BeginIdempotentRequestOpt{
+		ClientID:    "client",
+		Key:         "key",
+		RequestHash: "hash",
+	}
+	newKey := mock.MatchedBy(func(key *entity.IdempotencyKey) bool {
+		return key.ClientID == "client" && key.Key == "key" && key.RequestHash == "hash" &&
+			key.ExpiresAt.After(time.Now().Add(time.Hour-time.Minute))
+	})
+	completedKey := &entity.IdempotencyKey{
+		ClientID:       "client",
+		Key:            "key",
+		RequestHash:    "hash",
+		Completed:      true,
+		ResponseStatus: 200,
+		ResponseBody:   []byte("{}"),
+	}
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.IdempotencyKeyStorage)
+		expected    *entity.IdempotencyKey
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Begin new request",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Create", context.Background(), newKey).Return(&entity.IdempotencyKey{}, nil)
+			},
+		},
+		{
+			name: "Begin completed request",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Create", context.Background(), newKey).Return(nil, ErrCreateIdempotencyKeyExists)
+				m.On("Get", context.Background(), "client", "key").Return(completedKey, nil)
+			},
+			expected: completedKey,
+		},
+		{
+			name: "Begin request in progress",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Create", context.Background(), newKey).Return(nil, ErrCreateIdempotencyKeyExists)
+				m.On("Get", context.Background(), "client", "key").Return(&entity.IdempotencyKey{
+					ClientID:    "client",
+					Key:         "key",
+					RequestHash: "hash",
+				}, nil)
+			},
+			expectedErr: ErrIdempotencyKeyInProgress,
+			expectErr:   true,
+		},
+		{
+			name: "Begin request with released key",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Create", context.Background(), newKey).Return(nil, ErrCreateIdempotencyKeyExists)
+				m.On("Get", context.Background(), "client", "key").Return(nil, ErrGetIdempotencyKeyNotFound)
+			},
+			expectedErr: ErrIdempotencyKeyInProgress,
+			expectErr:   true,
+		},
+		{
+			name: "Begin another request with the same key",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Create", context.Background(), newKey).Return(nil, ErrCreateIdempotencyKeyExists)
+				m.On("Get", context.Background(), "client", "key").Return(&entity.IdempotencyKey{
+					ClientID:    "client",
+					Key:         "key",
+					RequestHash: "another hash",
+					Completed:   true,
+				}, nil)
+			},
+			expectedErr: ErrIdempotencyKeyReused,
+			expectErr:   true,
+		},
+		{
+			name: "Begin with unexpected error in storage",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Create", context.Background(), newKey).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			idempotencyKeyStorageMock := mocks.NewIdempotencyKeyStorage(t)
+			tc.mock(idempotencyKeyStorageMock)
+			storages := Storages{IdempotencyKey: idempotencyKeyStorageMock}
+
+			idempotencyService := NewIdempotencyService(storages, time.Hour, logger)
+			actual, err := idempotencyService.Begin(context.Background(), input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to begin request")
+				require.Equal(t, tc.expected, actual, "keys are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "key is not nil")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_Complete(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	input := CompleteIdempotentRequestOpt{
+		ClientID:       "client",
+		Key:            "key",
+		ResponseStatus: 200,
+		ResponseHeader: map[string][]string{"Content-Type": {"application/json"}},
+		ResponseBody:   []byte("{}"),
+	}
+	key := &entity.IdempotencyKey{
+		ClientID:       "client",
+		Key:            "key",
+		ResponseStatus: 200,
+		ResponseHeader: map[string][]string{"Content-Type": {"application/json"}},
+		ResponseBody:   []byte("{}"),
+	}
+
+	testCases := []struct {
+		name      string
+		mock      func(m *mocks.IdempotencyKeyStorage)
+		expectErr bool
+	}{
+		{
+			name: "Complete",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Complete", context.Background(), key).Return(nil)
+			},
+		},
+		{
+			name: "Complete released key",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Complete", context.Background(), key).Return(ErrGetIdempotencyKeyNotFound)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Complete with unexpected error in storage",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Complete", context.Background(), key).Return(errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			idempotencyKeyStorageMock := mocks.NewIdempotencyKeyStorage(t)
+			tc.mock(idempotencyKeyStorageMock)
+			storages := Storages{IdempotencyKey: idempotencyKeyStorageMock}
+
+			idempotencyService := NewIdempotencyService(storages, time.Hour, logger)
+			err := idempotencyService.Complete(context.Background(), input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to complete request")
+			} else {
+				require.Error(t, err, "no error")
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_Release(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	testCases := []struct {
+		name      string
+		mock      func(m *mocks.IdempotencyKeyStorage)
+		expectErr bool
+	}{
+		{
+			name: "Release",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Delete", context.Background(), "client", "key").Return(nil)
+			},
+		},
+		{
+			name: "Release with unexpected error in storage",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("Delete", context.Background(), "client", "key").Return(errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			idempotencyKeyStorageMock := mocks.NewIdempotencyKeyStorage(t)
+			tc.mock(idempotencyKeyStorageMock)
+			storages := Storages{IdempotencyKey: idempotencyKeyStorageMock}
+
+			idempotencyService := NewIdempotencyService(storages, time.Hour, logger)
+			err := idempotencyService.Release(context.Background(), "client", "key")
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to release key")
+			} else {
+				require.Error(t, err, "no error")
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	testCases := []struct {
+		name           string
+		mock           func(m *mocks.IdempotencyKeyStorage)
+		expectedPurged int64
+		expectErr      bool
+	}{
+		{
+			name: "PurgeExpired",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("DeleteExpired", context.Background(), mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+			},
+			expectedPurged: 3,
+		},
+		{
+			name: "PurgeExpired with unexpected error in storage",
+			mock: func(m *mocks.IdempotencyKeyStorage) {
+				m.On("DeleteExpired", context.Background(), mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			idempotencyKeyStorageMock := mocks.NewIdempotencyKeyStorage(t)
+			tc.mock(idempotencyKeyStorageMock)
+			storages := Storages{IdempotencyKey: idempotencyKeyStorageMock}
+
+			idempotencyService := NewIdempotencyService(storages, time.Hour, logger)
+			actual, err := idempotencyService.PurgeExpired(context.Background())
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to purge expired keys")
+				require.Equal(t, tc.expectedPurged, actual, "purged counts are not equal")
+			} else {
+				require.Error(t, err, "no error")
+			}
+		})
+	}
+}
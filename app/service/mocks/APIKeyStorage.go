@@ -0,0 +1,150 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// APIKeyStorage is an autogenerated mock type for the APIKeyStorage type
+type APIKeyStorage struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, key
+func (_m *APIKeyStorage) Create(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 *entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.APIKey) (*entity.APIKey, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.APIKey) *entity.APIKey); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.APIKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByHash provides a mock function with given fields: ctx, keyHash
+func (_m *APIKeyStorage) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	var r0 *entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.APIKey, error)); ok {
+		return rf(ctx, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.APIKey); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *APIKeyStorage) List(ctx context.Context) ([]entity.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: ctx, id, revokedAt
+func (_m *APIKeyStorage) Revoke(ctx context.Context, id string, revokedAt time.Time) (*entity.APIKey, error) {
+	ret := _m.Called(ctx, id, revokedAt)
+
+	var r0 *entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*entity.APIKey, error)); ok {
+		return rf(ctx, id, revokedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *entity.APIKey); ok {
+		r0 = rf(ctx, id, revokedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, id, revokedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Touch provides a mock function with given fields: ctx, id, usedAt
+func (_m *APIKeyStorage) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	ret := _m.Called(ctx, id, usedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPIKeyStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyStorage creates a new instance of APIKeyStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyStorage(t mockConstructorTestingTNewAPIKeyStorage) *APIKeyStorage {
+	mock := &APIKeyStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,134 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthorStorage is an autogenerated mock type for the AuthorStorage type
+type AuthorStorage struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, author
+func (_m *AuthorStorage) Create(ctx context.Context, author *entity.Author) (*entity.Author, error) {
+	ret := _m.Called(ctx, author)
+
+	var r0 *entity.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Author) (*entity.Author, error)); ok {
+		return rf(ctx, author)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Author) *entity.Author); ok {
+		r0 = rf(ctx, author)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Author) error); ok {
+		r1 = rf(ctx, author)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *AuthorStorage) Get(ctx context.Context, id string) (*entity.Author, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Author, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Author); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMany provides a mock function with given fields: ctx, ids
+func (_m *AuthorStorage) GetMany(ctx context.Context, ids []string) ([]entity.Author, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 []entity.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]entity.Author, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []entity.Author); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *AuthorStorage) List(ctx context.Context) ([]entity.Author, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Author, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Author); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuthorStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthorStorage creates a new instance of AuthorStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthorStorage(t mockConstructorTestingTNewAuthorStorage) *AuthorStorage {
+	mock := &AuthorStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
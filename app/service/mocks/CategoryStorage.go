@@ -0,0 +1,174 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CategoryStorage is an autogenerated mock type for the CategoryStorage type
+type CategoryStorage struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, category
+func (_m *CategoryStorage) Create(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	ret := _m.Called(ctx, category)
+
+	var r0 *entity.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Category) (*entity.Category, error)); ok {
+		return rf(ctx, category)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Category) *entity.Category); ok {
+		r0 = rf(ctx, category)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Category) error); ok {
+		r1 = rf(ctx, category)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *CategoryStorage) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *CategoryStorage) Get(ctx context.Context, id string) (*entity.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Category, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *CategoryStorage) List(ctx context.Context) ([]entity.Category, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Category, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubtreeIDs provides a mock function with given fields: ctx, id
+func (_m *CategoryStorage) SubtreeIDs(ctx context.Context, id string) ([]string, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, update
+func (_m *CategoryStorage) Update(ctx context.Context, id string, update entity.CategoryUpdate) (*entity.Category, error) {
+	ret := _m.Called(ctx, id, update)
+
+	var r0 *entity.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.CategoryUpdate) (*entity.Category, error)); ok {
+		return rf(ctx, id, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.CategoryUpdate) *entity.Category); ok {
+		r0 = rf(ctx, id, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.CategoryUpdate) error); ok {
+		r1 = rf(ctx, id, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewCategoryStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCategoryStorage creates a new instance of CategoryStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCategoryStorage(t mockConstructorTestingTNewCategoryStorage) *CategoryStorage {
+	mock := &CategoryStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,200 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentStorage is an autogenerated mock type for the CommentStorage type
+type CommentStorage struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, comment
+func (_m *CommentStorage) Create(ctx context.Context, comment *entity.Comment) (*entity.Comment, error) {
+	ret := _m.Called(ctx, comment)
+
+	var r0 *entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Comment) (*entity.Comment, error)); ok {
+		return rf(ctx, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Comment) *entity.Comment); ok {
+		r0 = rf(ctx, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Comment) error); ok {
+		r1 = rf(ctx, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *CommentStorage) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *CommentStorage) Get(ctx context.Context, id string) (*entity.Comment, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Comment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Comment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *CommentStorage) List(ctx context.Context, filter entity.CommentsFilter) ([]entity.Comment, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CommentsFilter) ([]entity.Comment, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.CommentsFilter) []entity.Comment); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.CommentsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListForModeration provides a mock function with given fields: ctx, filter
+func (_m *CommentStorage) ListForModeration(ctx context.Context, filter entity.ModerationQueueFilter) ([]entity.Comment, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ModerationQueueFilter) ([]entity.Comment, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ModerationQueueFilter) []entity.Comment); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ModerationQueueFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListModerations provides a mock function with given fields: ctx, commentID
+func (_m *CommentStorage) ListModerations(ctx context.Context, commentID string) ([]entity.CommentModeration, error) {
+	ret := _m.Called(ctx, commentID)
+
+	var r0 []entity.CommentModeration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.CommentModeration, error)); ok {
+		return rf(ctx, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.CommentModeration); ok {
+		r0 = rf(ctx, commentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CommentModeration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, update
+func (_m *CommentStorage) Update(ctx context.Context, id string, update entity.CommentUpdate) (*entity.Comment, error) {
+	ret := _m.Called(ctx, id, update)
+
+	var r0 *entity.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.CommentUpdate) (*entity.Comment, error)); ok {
+		return rf(ctx, id, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.CommentUpdate) *entity.Comment); ok {
+		r0 = rf(ctx, id, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.CommentUpdate) error); ok {
+		r1 = rf(ctx, id, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewCommentStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCommentStorage creates a new instance of CommentStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCommentStorage(t mockConstructorTestingTNewCommentStorage) *CommentStorage {
+	mock := &CommentStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,136 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyKeyStorage is an autogenerated mock type for the IdempotencyKeyStorage type
+type IdempotencyKeyStorage struct {
+	mock.Mock
+}
+
+// Complete provides a mock function with given fields: ctx, key
+func (_m *IdempotencyKeyStorage) Complete(ctx context.Context, key *entity.IdempotencyKey) error {
+	ret := _m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.IdempotencyKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, key
+func (_m *IdempotencyKeyStorage) Create(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 *entity.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.IdempotencyKey) (*entity.IdempotencyKey, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.IdempotencyKey) *entity.IdempotencyKey); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.IdempotencyKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, clientID, key
+func (_m *IdempotencyKeyStorage) Delete(ctx context.Context, clientID string, key string) error {
+	ret := _m.Called(ctx, clientID, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, clientID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: ctx, now
+func (_m *IdempotencyKeyStorage) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, clientID, key
+func (_m *IdempotencyKeyStorage) Get(ctx context.Context, clientID string, key string) (*entity.IdempotencyKey, error) {
+	ret := _m.Called(ctx, clientID, key)
+
+	var r0 *entity.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.IdempotencyKey, error)); ok {
+		return rf(ctx, clientID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.IdempotencyKey); ok {
+		r0 = rf(ctx, clientID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, clientID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIdempotencyKeyStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIdempotencyKeyStorage creates a new instance of IdempotencyKeyStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIdempotencyKeyStorage(t mockConstructorTestingTNewIdempotencyKeyStorage) *IdempotencyKeyStorage {
+	mock := &IdempotencyKeyStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OIDCAuthRequestStorage is an autogenerated mock type for the OIDCAuthRequestStorage type
+type OIDCAuthRequestStorage struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, request
+func (_m *OIDCAuthRequestStorage) Create(ctx context.Context, request *entity.OIDCAuthRequest) (*entity.OIDCAuthRequest, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 *entity.OIDCAuthRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.OIDCAuthRequest) (*entity.OIDCAuthRequest, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.OIDCAuthRequest) *entity.OIDCAuthRequest); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.OIDCAuthRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.OIDCAuthRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Take provides a mock function with given fields: ctx, state, now
+func (_m *OIDCAuthRequestStorage) Take(ctx context.Context, state string, now time.Time) (*entity.OIDCAuthRequest, error) {
+	ret := _m.Called(ctx, state, now)
+
+	var r0 *entity.OIDCAuthRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*entity.OIDCAuthRequest, error)); ok {
+		return rf(ctx, state, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *entity.OIDCAuthRequest); ok {
+		r0 = rf(ctx, state, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.OIDCAuthRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, state, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewOIDCAuthRequestStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCAuthRequestStorage creates a new instance of OIDCAuthRequestStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCAuthRequestStorage(t mockConstructorTestingTNewOIDCAuthRequestStorage) *OIDCAuthRequestStorage {
+	mock := &OIDCAuthRequestStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
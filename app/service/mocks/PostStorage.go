@@ -7,6 +7,8 @@ import (
 	entity "darkness8129/news-api/app/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // PostStorage is an autogenerated mock type for the PostStorage type
@@ -40,13 +42,13 @@ func (_m *PostStorage) Create(ctx context.Context, post *entity.Post) (*entity.P
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *PostStorage) Delete(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *PostStorage) Delete(ctx context.Context, id string, version int) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -80,25 +82,299 @@ func (_m *PostStorage) Get(ctx context.Context, id string) (*entity.Post, error)
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *PostStorage) List(ctx context.Context) ([]entity.Post, error) {
-	ret := _m.Called(ctx)
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *PostStorage) GetBySlug(ctx context.Context, slug string) (*entity.Post, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 *entity.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Post, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Post); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevision provides a mock function with given fields: ctx, postID, number
+func (_m *PostStorage) GetRevision(ctx context.Context, postID string, number int) (*entity.PostRevision, error) {
+	ret := _m.Called(ctx, postID, number)
+
+	var r0 *entity.PostRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*entity.PostRevision, error)); ok {
+		return rf(ctx, postID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *entity.PostRevision); ok {
+		r0 = rf(ctx, postID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PostRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, postID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *PostStorage) List(ctx context.Context, filter entity.PostsFilter) ([]entity.Post, error) {
+	ret := _m.Called(ctx, filter)
 
 	var r0 []entity.Post
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Post, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostsFilter) ([]entity.Post, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []entity.Post); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostsFilter) []entity.Post); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, entity.PostsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRevisions provides a mock function with given fields: ctx, postID
+func (_m *PostStorage) ListRevisions(ctx context.Context, postID string) ([]entity.PostRevision, error) {
+	ret := _m.Called(ctx, postID)
+
+	var r0 []entity.PostRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entity.PostRevision, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entity.PostRevision); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PostRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWithoutSlug provides a mock function with given fields: ctx, limit
+func (_m *PostStorage) ListWithoutSlug(ctx context.Context, limit int) ([]entity.Post, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 []entity.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entity.Post, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entity.Post); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishDue provides a mock function with given fields: ctx, now, limit
+func (_m *PostStorage) PublishDue(ctx context.Context, now time.Time, limit int) ([]entity.Post, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	var r0 []entity.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]entity.Post, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []entity.Post); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, id, version
+func (_m *PostStorage) Purge(ctx context.Context, id string, version int) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeDeletedBefore provides a mock function with given fields: ctx, before
+func (_m *PostStorage) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id, version
+func (_m *PostStorage) Restore(ctx context.Context, id string, version int) (*entity.Post, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 *entity.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*entity.Post, error)); ok {
+		return rf(ctx, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *entity.Post); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, filter
+func (_m *PostStorage) Search(ctx context.Context, filter entity.PostsSearchFilter) ([]entity.PostSearchResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []entity.PostSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostsSearchFilter) ([]entity.PostSearchResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.PostsSearchFilter) []entity.PostSearchResult); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.PostSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.PostsSearchFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCommentsPremoderation provides a mock function with given fields: ctx, id, premoderation
+func (_m *PostStorage) SetCommentsPremoderation(ctx context.Context, id string, premoderation *bool) error {
+	ret := _m.Called(ctx, id, premoderation)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *bool) error); ok {
+		r0 = rf(ctx, id, premoderation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSlug provides a mock function with given fields: ctx, id, slug
+func (_m *PostStorage) SetSlug(ctx context.Context, id string, slug string) error {
+	ret := _m.Called(ctx, id, slug)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, slug)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, id, update
+func (_m *PostStorage) Update(ctx context.Context, id string, update entity.PostUpdate) (*entity.Post, error) {
+	ret := _m.Called(ctx, id, update)
+
+	var r0 *entity.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.PostUpdate) (*entity.Post, error)); ok {
+		return rf(ctx, id, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.PostUpdate) *entity.Post); ok {
+		r0 = rf(ctx, id, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.PostUpdate) error); ok {
+		r1 = rf(ctx, id, update)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -106,25 +382,25 @@ func (_m *PostStorage) List(ctx context.Context) ([]entity.Post, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, id, post
-func (_m *PostStorage) Update(ctx context.Context, id string, post *entity.Post) (*entity.Post, error) {
-	ret := _m.Called(ctx, id, post)
+// UpdateStatus provides a mock function with given fields: ctx, id, from, to, publishedAt, version
+func (_m *PostStorage) UpdateStatus(ctx context.Context, id string, from entity.PostStatus, to entity.PostStatus, publishedAt *time.Time, version int) (*entity.Post, error) {
+	ret := _m.Called(ctx, id, from, to, publishedAt, version)
 
 	var r0 *entity.Post
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *entity.Post) (*entity.Post, error)); ok {
-		return rf(ctx, id, post)
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.PostStatus, entity.PostStatus, *time.Time, int) (*entity.Post, error)); ok {
+		return rf(ctx, id, from, to, publishedAt, version)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *entity.Post) *entity.Post); ok {
-		r0 = rf(ctx, id, post)
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.PostStatus, entity.PostStatus, *time.Time, int) *entity.Post); ok {
+		r0 = rf(ctx, id, from, to, publishedAt, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *entity.Post) error); ok {
-		r1 = rf(ctx, id, post)
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.PostStatus, entity.PostStatus, *time.Time, int) error); ok {
+		r1 = rf(ctx, id, from, to, publishedAt, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,98 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RefreshTokenStorage is an autogenerated mock type for the RefreshTokenStorage type
+type RefreshTokenStorage struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, token
+func (_m *RefreshTokenStorage) Create(ctx context.Context, token *entity.RefreshToken) (*entity.RefreshToken, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *entity.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RefreshToken) (*entity.RefreshToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RefreshToken) *entity.RefreshToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.RefreshToken) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeFamily provides a mock function with given fields: ctx, id, revokedAt
+func (_m *RefreshTokenStorage) RevokeFamily(ctx context.Context, id string, revokedAt time.Time) error {
+	ret := _m.Called(ctx, id, revokedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Use provides a mock function with given fields: ctx, id, usedAt
+func (_m *RefreshTokenStorage) Use(ctx context.Context, id string, usedAt time.Time) (*entity.RefreshToken, error) {
+	ret := _m.Called(ctx, id, usedAt)
+
+	var r0 *entity.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*entity.RefreshToken, error)); ok {
+		return rf(ctx, id, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *entity.RefreshToken); ok {
+		r0 = rf(ctx, id, usedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, id, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRefreshTokenStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRefreshTokenStorage creates a new instance of RefreshTokenStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRefreshTokenStorage(t mockConstructorTestingTNewRefreshTokenStorage) *RefreshTokenStorage {
+	mock := &RefreshTokenStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.27.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "darkness8129/news-api/app/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TagStorage is an autogenerated mock type for the TagStorage type
+type TagStorage struct {
+	mock.Mock
+}
+
+// Ensure provides a mock function with given fields: ctx, names
+func (_m *TagStorage) Ensure(ctx context.Context, names []string) ([]entity.Tag, error) {
+	ret := _m.Called(ctx, names)
+
+	var r0 []entity.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]entity.Tag, error)); ok {
+		return rf(ctx, names)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []entity.Tag); ok {
+		r0 = rf(ctx, names)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *TagStorage) List(ctx context.Context) ([]entity.TagUsage, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entity.TagUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.TagUsage, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entity.TagUsage); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.TagUsage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTagStorage interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTagStorage creates a new instance of TagStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTagStorage(t mockConstructorTestingTNewTagStorage) *TagStorage {
+	mock := &TagStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"fmt"
+)
+
+var _ ModerationService = (*moderationService)(nil)
+
+type moderationService struct {
+	storages Storages
+	// premoderation is the global policy, posts can override it
+	premoderation bool
+	logger        logging.Logger
+}
+
+func NewModerationService(storages Storages, premoderation bool, logger logging.Logger) *moderationService {
+	return &moderationService{storages, premoderation, logger.Named("moderationService")}
+}
+
+func (s *moderationService) ListComments(ctx context.Context, opt ListModerationCommentsOpt) (*ListCommentsResult, error) {
+	logger := s.logger.Named("ListComments")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	limit := listCommentsLimit(opt.Limit)
+	filter := entity.ModerationQueueFilter{
+		Status: opt.Status,
+		PostID: opt.PostID,
+		Limit:  limit + 1,
+	}
+	if filter.Status == "" {
+		filter.Status = entity.CommentStatusPending
+	}
+	if opt.Cursor != "" {
+		cursor, err := decodeCommentsCursor(opt.Cursor)
+		if err != nil {
+			logger.Info("failed to decode cursor", "err", err)
+			return nil, ErrListCommentsInvalidCursor
+		}
+
+		filter.After = cursor
+	}
+	logger.Debug("built filter", "filter", filter)
+
+	comments, err := s.storages.Comment.ListForModeration(ctx, filter)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list comments", "err", err)
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	result, err := commentsPage(comments, limit)
+	if err != nil {
+		logger.Error("failed to make comments page", "err", err)
+		return nil, fmt.Errorf("failed to make comments page: %w", err)
+	}
+
+	logger.Info("successfully listed comments", "result", result)
+	return result, nil
+}
+
+func (s *moderationService) ModerateComment(ctx context.Context, opt ModerateCommentOpt) (*entity.Comment, error) {
+	logger := s.logger.Named("ModerateComment")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	switch opt.Status {
+	case entity.CommentStatusApproved:
+	case entity.CommentStatusRejected, entity.CommentStatusSpam:
+		if opt.Reason == "" {
+			logger.Info("reason is required", "status", opt.Status)
+			return nil, ErrModerationReasonRequired
+		}
+	default:
+		logger.Info("invalid moderation status", "status", opt.Status)
+		return nil, ErrInvalidModerationStatus
+	}
+
+	moderatedComment, err := s.storages.Comment.Update(ctx, opt.ID, entity.CommentUpdate{
+		Moderation: &entity.CommentModeration{Status: opt.Status, Reason: opt.Reason},
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to moderate comment", "err", err)
+		return nil, fmt.Errorf("failed to moderate comment: %w", err)
+	}
+
+	logger.Info("successfully moderated comment", "moderatedComment", moderatedComment)
+	return moderatedComment, nil
+}
+
+func (s *moderationService) CommentHistory(ctx context.Context, id string) ([]entity.CommentModeration, error) {
+	logger := s.logger.Named("CommentHistory")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	_, err = s.storages.Comment.Get(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get comment", "err", err)
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	moderations, err := s.storages.Comment.ListModerations(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list comment moderations", "err", err)
+		return nil, fmt.Errorf("failed to list comment moderations: %w", err)
+	}
+
+	logger.Info("successfully got comment history", "moderations", moderations)
+	return moderations, nil
+}
+
+func (s *moderationService) GetPostPolicy(ctx context.Context, postID string) (*PostModerationPolicy, error) {
+	logger := s.logger.Named("GetPostPolicy")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	post, err := s.storages.Post.Get(ctx, postID)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post", "err", err)
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	policy := PostModerationPolicy{
+		Premoderation: post.CommentsPremoderation,
+		Effective:     premoderated(post, s.premoderation),
+	}
+
+	logger.Info("successfully got post policy", "policy", policy)
+	return &policy, nil
+}
+
+func (s *moderationService) SetPostPolicy(ctx context.Context, opt SetPostModerationPolicyOpt) (*PostModerationPolicy, error) {
+	logger := s.logger.Named("SetPostPolicy")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	err = s.storages.Post.SetCommentsPremoderation(ctx, opt.PostID, opt.Premoderation)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to set comments premoderation", "err", err)
+		return nil, fmt.Errorf("failed to set comments premoderation: %w", err)
+	}
+
+	policy := PostModerationPolicy{
+		Premoderation: opt.Premoderation,
+		Effective:     premoderated(&entity.Post{CommentsPremoderation: opt.Premoderation}, s.premoderation),
+	}
+
+	logger.Info("successfully set post policy", "policy", policy)
+	return &policy, nil
+}
+
+// premoderated tells whether new comments of the post wait for approval
+func premoderated(post *entity.Post, global bool) bool {
+	if post.CommentsPremoderation != nil {
+		return *post.CommentsPremoderation
+	}
+
+	return global
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModerationService_ListComments(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+
+	comments := make([]entity.Comment, 0, 3)
+	for i := 0; i < 3; i++ {
+		comments = append(comments, entity.Comment{ID: uuid.NewString(), Status: entity.CommentStatusPending, CreatedAt: time.Now().Add(time.Duration(i) * time.Second)})
+	}
+
+	testCases := []struct {
+		name             string
+		mock             func(m *mocks.CommentStorage)
+		input            ListModerationCommentsOpt
+		expectedLen      int
+		expectNextCursor bool
+		expectedErr      error
+		expectErr        bool
+	}{
+		{
+			name: "List pending comments by default",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("ListForModeration", editorCtx, entity.ModerationQueueFilter{Status: entity.CommentStatusPending, Limit: defaultListCommentsLimit + 1}).Return(comments, nil)
+			},
+			expectedLen: 3,
+		},
+		{
+			name: "List rejected comments of post with next page",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("ListForModeration", editorCtx, entity.ModerationQueueFilter{Status: entity.CommentStatusRejected, PostID: postID, Limit: 3}).Return(comments, nil)
+			},
+			input:            ListModerationCommentsOpt{Status: entity.CommentStatusRejected, PostID: postID, Limit: 2},
+			expectedLen:      2,
+			expectNextCursor: true,
+		},
+		{
+			name:        "List with invalid cursor",
+			mock:        func(m *mocks.CommentStorage) {},
+			input:       ListModerationCommentsOpt{Cursor: "invalid"},
+			expectedErr: ErrListCommentsInvalidCursor,
+			expectErr:   true,
+		},
+		{
+			name: "List with unexpected error in storage",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("ListForModeration", editorCtx, entity.ModerationQueueFilter{Status: entity.CommentStatusPending, Limit: defaultListCommentsLimit + 1}).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commentStorageMock := mocks.NewCommentStorage(t)
+			tc.mock(commentStorageMock)
+			storages := Storages{Comment: commentStorageMock}
+
+			moderationService := NewModerationService(storages, false, logger)
+			actual, err := moderationService.ListComments(editorCtx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list comments")
+				require.Len(t, actual.Comments, tc.expectedLen, "unexpected number of comments")
+				require.Equal(t, tc.expectNextCursor, actual.NextCursor != "", "unexpected next cursor")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "result is not nil")
+			}
+		})
+	}
+}
+
+func TestModerationService_ModerateComment(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	id := uuid.NewString()
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.CommentStorage)
+		input       ModerateCommentOpt
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "Approve",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Update", editorCtx, id, entity.CommentUpdate{
+					Moderation: &entity.CommentModeration{Status: entity.CommentStatusApproved},
+				}).Return(&entity.Comment{ID: id, Status: entity.CommentStatusApproved}, nil)
+			},
+			input: ModerateCommentOpt{ID: id, Status: entity.CommentStatusApproved},
+		},
+		{
+			name: "Mark as spam",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Update", editorCtx, id, entity.CommentUpdate{
+					Moderation: &entity.CommentModeration{Status: entity.CommentStatusSpam, Reason: "ads"},
+				}).Return(&entity.Comment{ID: id, Status: entity.CommentStatusSpam, ModerationReason: "ads"}, nil)
+			},
+			input: ModerateCommentOpt{ID: id, Status: entity.CommentStatusSpam, Reason: "ads"},
+		},
+		{
+			name:        "Reject without reason",
+			mock:        func(m *mocks.CommentStorage) {},
+			input:       ModerateCommentOpt{ID: id, Status: entity.CommentStatusRejected},
+			expectedErr: ErrModerationReasonRequired,
+			expectErr:   true,
+		},
+		{
+			name:        "Moderate to pending",
+			mock:        func(m *mocks.CommentStorage) {},
+			input:       ModerateCommentOpt{ID: id, Status: entity.CommentStatusPending},
+			expectedErr: ErrInvalidModerationStatus,
+			expectErr:   true,
+		},
+		{
+			name: "Moderate non-existent comment",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Update", editorCtx, id, entity.CommentUpdate{
+					Moderation: &entity.CommentModeration{Status: entity.CommentStatusRejected, Reason: "rude"},
+				}).Return(nil, ErrGetCommentNotFound)
+			},
+			input:       ModerateCommentOpt{ID: id, Status: entity.CommentStatusRejected, Reason: "rude"},
+			expectedErr: ErrGetCommentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Moderate with unexpected error in storage",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Update", editorCtx, id, entity.CommentUpdate{
+					Moderation: &entity.CommentModeration{Status: entity.CommentStatusApproved},
+				}).Return(nil, errors.New("error!"))
+			},
+			input:     ModerateCommentOpt{ID: id, Status: entity.CommentStatusApproved},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commentStorageMock := mocks.NewCommentStorage(t)
+			tc.mock(commentStorageMock)
+			storages := Storages{Comment: commentStorageMock}
+
+			moderationService := NewModerationService(storages, false, logger)
+			actual, err := moderationService.ModerateComment(editorCtx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to moderate comment")
+				require.Equal(t, tc.input.Status, actual.Status, "statuses are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "comment is not nil")
+			}
+		})
+	}
+}
+
+func TestModerationService_CommentHistory(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	id := uuid.NewString()
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.CommentStorage)
+		expectedLen int
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "CommentHistory",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Get", editorCtx, id).Return(&entity.Comment{ID: id}, nil)
+				m.On("ListModerations", editorCtx, id).Return([]entity.CommentModeration{
+					{CommentID: id, Status: entity.CommentStatusApproved},
+					{CommentID: id, Status: entity.CommentStatusRejected, Reason: "rude"},
+				}, nil)
+			},
+			expectedLen: 2,
+		},
+		{
+			name: "CommentHistory of non-existent comment",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Get", editorCtx, id).Return(nil, ErrGetCommentNotFound)
+			},
+			expectedErr: ErrGetCommentNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "CommentHistory with unexpected error in storage",
+			mock: func(m *mocks.CommentStorage) {
+				m.On("Get", editorCtx, id).Return(&entity.Comment{ID: id}, nil)
+				m.On("ListModerations", editorCtx, id).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commentStorageMock := mocks.NewCommentStorage(t)
+			tc.mock(commentStorageMock)
+			storages := Storages{Comment: commentStorageMock}
+
+			moderationService := NewModerationService(storages, false, logger)
+			actual, err := moderationService.CommentHistory(editorCtx, id)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to get comment history")
+				require.Len(t, actual, tc.expectedLen, "unexpected number of moderations")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "history is not nil")
+			}
+		})
+	}
+}
+
+func TestModerationService_PostPolicy(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	premoderation := false
+
+	testCases := []struct {
+		name              string
+		mock              func(m *mocks.PostStorage)
+		globalPolicy      bool
+		input             *bool
+		expectedEffective bool
+		expectedErr       error
+		expectErr         bool
+	}{
+		{
+			name: "Set post policy",
+			mock: func(m *mocks.PostStorage) {
+				m.On("SetCommentsPremoderation", editorCtx, postID, &premoderation).Return(nil)
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID, CommentsPremoderation: &premoderation}, nil)
+			},
+			globalPolicy:      true,
+			input:             &premoderation,
+			expectedEffective: false,
+		},
+		{
+			name: "Reset post policy to global one",
+			mock: func(m *mocks.PostStorage) {
+				m.On("SetCommentsPremoderation", editorCtx, postID, (*bool)(nil)).Return(nil)
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID}, nil)
+			},
+			globalPolicy:      true,
+			expectedEffective: true,
+		},
+		{
+			name: "Set policy of non-existent post",
+			mock: func(m *mocks.PostStorage) {
+				m.On("SetCommentsPremoderation", editorCtx, postID, &premoderation).Return(ErrGetPostNotFound)
+			},
+			input:       &premoderation,
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Set policy with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("SetCommentsPremoderation", editorCtx, postID, &premoderation).Return(errors.New("error!"))
+			},
+			input:     &premoderation,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			moderationService := NewModerationService(storages, tc.globalPolicy, logger)
+			actual, err := moderationService.SetPostPolicy(editorCtx, SetPostModerationPolicyOpt{PostID: postID, Premoderation: tc.input})
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to set post policy")
+				require.Equal(t, tc.input, actual.Premoderation, "policies are not equal")
+				require.Equal(t, tc.expectedEffective, actual.Effective, "unexpected effective policy")
+
+				actual, err = moderationService.GetPostPolicy(editorCtx, postID)
+				require.NoError(t, err, "failed to get post policy")
+				require.Equal(t, tc.expectedEffective, actual.Effective, "unexpected effective policy")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "policy is not nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/oidc"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// oidcAuthRequestTTL is how long the user has to sign in at the identity provider
+	oidcAuthRequestTTL = 10 * time.Minute
+	// oidcStateSize is the number of random bytes in states and nonces
+	oidcStateSize = 32
+	// maxUserNameLength is the length of the name column, longer names of the provider are cut
+	maxUserNameLength = 100
+)
+
+func (s *authService) StartOIDCLogin(ctx context.Context) (string, error) {
+	logger := s.logger.Named("StartOIDCLogin")
+
+	if s.opt.OIDC == nil {
+		logger.Info("oidc is disabled")
+		return "", ErrOIDCDisabled
+	}
+
+	state, err := oidc.RandomString(oidcStateSize)
+	if err != nil {
+		logger.Error("failed to generate state", "err", err)
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	nonce, err := oidc.RandomString(oidcStateSize)
+	if err != nil {
+		logger.Error("failed to generate nonce", "err", err)
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	codeVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		logger.Error("failed to generate code verifier", "err", err)
+		return "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	// the URL is built first, so nothing is stored while the provider is unavailable
+	authURL, err := s.opt.OIDC.AuthCodeURL(ctx, oidc.AuthCodeOptions{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: oidc.CodeChallenge(codeVerifier),
+	})
+	if err != nil {
+		logger.Error("failed to get authorization URL", "err", err)
+		return "", fmt.Errorf("failed to get authorization URL: %w", err)
+	}
+
+	_, err = s.storages.OIDCAuthRequest.Create(ctx, &entity.OIDCAuthRequest{
+		State:        state,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(oidcAuthRequestTTL),
+	})
+	if err != nil {
+		logger.Error("failed to create OIDC auth request", "err", err)
+		return "", fmt.Errorf("failed to create OIDC auth request: %w", err)
+	}
+
+	logger.Info("successfully started OIDC login")
+	return authURL, nil
+}
+
+func (s *authService) CompleteOIDCLogin(ctx context.Context, opt CompleteOIDCLoginOpt) (*AuthTokens, error) {
+	logger := s.logger.Named("CompleteOIDCLogin")
+
+	if s.opt.OIDC == nil {
+		logger.Info("oidc is disabled")
+		return nil, ErrOIDCDisabled
+	}
+
+	request, err := s.storages.OIDCAuthRequest.Take(ctx, opt.State, time.Now())
+	if errors.Is(err, ErrTakeOIDCAuthRequestNotFound) {
+		logger.Info("unknown, used or expired state")
+		return nil, ErrInvalidOIDCState
+	}
+	if err != nil {
+		logger.Error("failed to take OIDC auth request", "err", err)
+		return nil, fmt.Errorf("failed to take OIDC auth request: %w", err)
+	}
+
+	if opt.Error != "" {
+		logger.Info("provider returned error", "error", opt.Error)
+		return nil, ErrOIDCLoginRejected
+	}
+
+	claims, err := s.opt.OIDC.Exchange(ctx, oidc.ExchangeOptions{
+		Code:         opt.Code,
+		CodeVerifier: request.CodeVerifier,
+		Nonce:        request.Nonce,
+	})
+	var oauthErr *oidc.Error
+	if errors.As(err, &oauthErr) || errors.Is(err, oidc.ErrInvalidIDToken) {
+		logger.Info("code or ID token is rejected", "err", err)
+		return nil, ErrOIDCLoginRejected
+	}
+	if err != nil {
+		logger.Error("failed to exchange code", "err", err)
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	user, err := s.oidcUser(ctx, claims)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get OIDC user", "err", err)
+		return nil, fmt.Errorf("failed to get OIDC user: %w", err)
+	}
+
+	tokens, err := s.issueTokens(ctx, user, uuid.NewString())
+	if err != nil {
+		logger.Error("failed to issue tokens", "err", err)
+		return nil, fmt.Errorf("failed to issue tokens: %w", err)
+	}
+
+	logger.Info("successfully logged in through OIDC", "userID", user.ID)
+	return tokens, nil
+}
+
+// oidcUser finds the user of the claims, links the user with the same verified email or creates a new one,
+// then it syncs the role if the groups are mapped to roles
+func (s *authService) oidcUser(ctx context.Context, claims *oidc.Claims) (*entity.User, error) {
+	user, err := s.storages.User.GetByOIDCSubject(ctx, claims.Subject)
+	if errors.Is(err, ErrGetUserNotFound) {
+		user, err = s.linkOIDCUser(ctx, claims)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.opt.OIDCRoles) == 0 {
+		return user, nil
+	}
+
+	role := s.oidcRole(claims)
+	if role == user.Role {
+		return user, nil
+	}
+
+	user, err = s.storages.User.UpdateRole(ctx, user.ID, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	return user, nil
+}
+
+// linkOIDCUser trusts the email only if the provider verified it, otherwise anyone could take over an account
+func (s *authService) linkOIDCUser(ctx context.Context, claims *oidc.Claims) (*entity.User, error) {
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	email := normalizeEmail(claims.Email)
+	user, err := s.storages.User.GetByEmail(ctx, email)
+	if err == nil && user.OIDCSubject != nil {
+		// the email is moved to another account of the provider, the old one must be unlinked by hand
+		return nil, ErrCreateUserEmailExists
+	}
+	if err == nil {
+		return s.storages.User.LinkOIDCSubject(ctx, user.ID, claims.Subject)
+	}
+	if !errors.Is(err, ErrGetUserNotFound) {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = email
+	}
+	if utf8.RuneCountInString(name) > maxUserNameLength {
+		name = string([]rune(name)[:maxUserNameLength])
+	}
+
+	return s.storages.User.Create(ctx, &entity.User{
+		Email:       email,
+		Name:        name,
+		Role:        s.oidcRegistrationRole(claims),
+		OIDCSubject: &claims.Subject,
+	})
+}
+
+// oidcRegistrationRole is the role of a new user, AdminEmails get the admin one only if the provider verified the email
+func (s *authService) oidcRegistrationRole(claims *oidc.Claims) entity.UserRole {
+	if !claims.EmailVerified {
+		return entity.UserRoleReader
+	}
+
+	email := normalizeEmail(claims.Email)
+	for _, adminEmail := range s.opt.AdminEmails {
+		if normalizeEmail(adminEmail) == email {
+			return entity.UserRoleAdmin
+		}
+	}
+
+	return entity.UserRoleReader
+}
+
+// oidcRole is the widest role of the mapped groups, users without them are readers
+func (s *authService) oidcRole(claims *oidc.Claims) entity.UserRole {
+	role := entity.UserRoleReader
+	for _, group := range claims.Strings(s.opt.OIDCRolesClaim) {
+		mapped, ok := s.opt.OIDCRoles[group]
+		if ok && mapped.Includes(role) {
+			role = mapped
+		}
+	}
+
+	return role
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"darkness8129/news-api/packages/oidc"
+	"darkness8129/news-api/packages/oidc/oidctest"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestIdP starts a stub identity provider and returns the options of the service signing in through it
+func newTestIdP(t *testing.T) (*oidctest.Server, AuthServiceOptions) {
+	idp := oidctest.NewServer("news-api", "secret")
+	t.Cleanup(idp.Close)
+
+	opt := testAuthOptions
+	opt.OIDC = oidc.NewHTTPProvider(oidc.Options{
+		Issuer:       idp.URL,
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  "https://news.example.com/oidc/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+	opt.OIDCRolesClaim = "groups"
+
+	return idp, opt
+}
+
+func TestAuthService_StartOIDCLogin(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	idp, opt := newTestIdP(t)
+	unavailableIdP, unavailableOpt := newTestIdP(t)
+	unavailableIdP.Close()
+	disabledOpt := testAuthOptions
+
+	testCases := []struct {
+		name        string
+		opt         AuthServiceOptions
+		mock        func(m *mocks.OIDCAuthRequestStorage)
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "StartOIDCLogin",
+			opt:  opt,
+			mock: func(m *mocks.OIDCAuthRequestStorage) {
+				m.On("Create", context.Background(), mock.Anything).Return(func(_ context.Context, r *entity.OIDCAuthRequest) (*entity.OIDCAuthRequest, error) {
+					return r, nil
+				})
+			},
+		},
+		{
+			name: "StartOIDCLogin with unexpected error in storage",
+			opt:  opt,
+			mock: func(m *mocks.OIDCAuthRequestStorage) {
+				m.On("Create", context.Background(), mock.Anything).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+		{
+			name:      "StartOIDCLogin with unavailable provider",
+			opt:       unavailableOpt,
+			expectErr: true,
+		},
+		{
+			name:        "StartOIDCLogin without provider",
+			opt:         disabledOpt,
+			expectedErr: ErrOIDCDisabled,
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			requestStorageMock := mocks.NewOIDCAuthRequestStorage(t)
+			if tc.mock != nil {
+				tc.mock(requestStorageMock)
+			}
+			storages := Storages{OIDCAuthRequest: requestStorageMock}
+
+			authService := NewAuthService(storages, tc.opt, logger)
+			actual, err := authService.StartOIDCLogin(context.Background())
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to start OIDC login")
+				require.True(t, strings.HasPrefix(actual, idp.URL+"/authorize?"), "URL isn't of the provider")
+
+				u, err := url.Parse(actual)
+				require.NoError(t, err, "failed to parse URL")
+				request := requestStorageMock.Calls[0].Arguments.Get(1).(*entity.OIDCAuthRequest)
+				require.Equal(t, request.State, u.Query().Get("state"), "states are not equal")
+				require.Equal(t, request.Nonce, u.Query().Get("nonce"), "nonces are not equal")
+				require.Equal(t, oidc.CodeChallenge(request.CodeVerifier), u.Query().Get("code_challenge"), "challenge isn't of the verifier")
+				require.NotContains(t, actual, request.CodeVerifier, "code verifier is sent")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Empty(t, actual, "URL is not empty")
+			}
+		})
+	}
+}
+
+func TestAuthService_CompleteOIDCLogin(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	idp, opt := newTestIdP(t)
+	mappedOpt := opt
+	mappedOpt.OIDCRoles = map[string]entity.UserRole{"news-editors": entity.UserRoleEditor, "news-admins": entity.UserRoleAdmin}
+
+	userID := uuid.NewString()
+	subject := "jane-subject"
+	jane := map[string]interface{}{"sub": subject, "email": "Jane@Example.com", "email_verified": true, "name": "Jane", "groups": []string{"staff"}}
+	with := func(claims map[string]interface{}) map[string]interface{} {
+		merged := make(map[string]interface{}, len(jane)+len(claims))
+		for name, value := range jane {
+			merged[name] = value
+		}
+		for name, value := range claims {
+			merged[name] = value
+		}
+		return merged
+	}
+	linkedUser := func(role entity.UserRole) *entity.User {
+		return &entity.User{ID: userID, Email: "jane@example.com", Role: role, OIDCSubject: &subject}
+	}
+	created := func(_ context.Context, u *entity.User) (*entity.User, error) {
+		u.ID = userID
+		return u, nil
+	}
+
+	testCases := []struct {
+		name          string
+		opt           AuthServiceOptions
+		claims        map[string]interface{}
+		userMock      func(m *mocks.UserStorage)
+		unknownState  bool
+		providerError string
+		expectedRole  entity.UserRole
+		expectedErr   error
+		expectErr     bool
+	}{
+		{
+			name:   "CompleteOIDCLogin",
+			opt:    opt,
+			claims: jane,
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(linkedUser(entity.UserRoleEditor), nil)
+			},
+			expectedRole: entity.UserRoleEditor,
+		},
+		{
+			name:   "CompleteOIDCLogin of new user",
+			opt:    opt,
+			claims: jane,
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(nil, ErrGetUserNotFound)
+				m.On("GetByEmail", context.Background(), "jane@example.com").Return(nil, ErrGetUserNotFound)
+				m.On("Create", context.Background(), mock.MatchedBy(func(u *entity.User) bool {
+					return u.Email == "jane@example.com" && u.Name == "Jane" && u.PasswordHash == "" &&
+						u.OIDCSubject != nil && *u.OIDCSubject == subject && u.Role == entity.UserRoleReader
+				})).Return(created)
+			},
+			expectedRole: entity.UserRoleReader,
+		},
+		{
+			name:   "CompleteOIDCLogin of new admin",
+			opt:    opt,
+			claims: with(map[string]interface{}{"email": "admin@example.com", "name": ""}),
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(nil, ErrGetUserNotFound)
+				m.On("GetByEmail", context.Background(), "admin@example.com").Return(nil, ErrGetUserNotFound)
+				m.On("Create", context.Background(), mock.MatchedBy(func(u *entity.User) bool {
+					return u.Name == "admin@example.com" && u.Role == entity.UserRoleAdmin
+				})).Return(created)
+			},
+			expectedRole: entity.UserRoleAdmin,
+		},
+		{
+			name:   "CompleteOIDCLogin of user with the same email",
+			opt:    opt,
+			claims: jane,
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(nil, ErrGetUserNotFound)
+				m.On("GetByEmail", context.Background(), "jane@example.com").Return(&entity.User{ID: userID, Role: entity.UserRoleAuthor}, nil)
+				m.On("LinkOIDCSubject", context.Background(), userID, subject).Return(linkedUser(entity.UserRoleAuthor), nil)
+			},
+			expectedRole: entity.UserRoleAuthor,
+		},
+		{
+			name:   "CompleteOIDCLogin of user with the same email linked to another subject",
+			opt:    opt,
+			claims: jane,
+			userMock: func(m *mocks.UserStorage) {
+				anotherSubject := "another-subject"
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(nil, ErrGetUserNotFound)
+				m.On("GetByEmail", context.Background(), "jane@example.com").Return(&entity.User{ID: userID, OIDCSubject: &anotherSubject}, nil)
+			},
+			expectedErr: ErrCreateUserEmailExists,
+			expectErr:   true,
+		},
+		{
+			name:   "CompleteOIDCLogin with unverified email",
+			opt:    opt,
+			claims: with(map[string]interface{}{"email_verified": false}),
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(nil, ErrGetUserNotFound)
+			},
+			expectedErr: ErrOIDCEmailNotVerified,
+			expectErr:   true,
+		},
+		{
+			name:   "CompleteOIDCLogin with mapped groups",
+			opt:    mappedOpt,
+			claims: with(map[string]interface{}{"groups": []string{"staff", "news-editors"}}),
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(linkedUser(entity.UserRoleReader), nil)
+				m.On("UpdateRole", context.Background(), userID, entity.UserRoleEditor).Return(linkedUser(entity.UserRoleEditor), nil)
+			},
+			expectedRole: entity.UserRoleEditor,
+		},
+		{
+			name:   "CompleteOIDCLogin without mapped groups",
+			opt:    mappedOpt,
+			claims: jane,
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(linkedUser(entity.UserRoleAdmin), nil)
+				m.On("UpdateRole", context.Background(), userID, entity.UserRoleReader).Return(linkedUser(entity.UserRoleReader), nil)
+			},
+			expectedRole: entity.UserRoleReader,
+		},
+		{
+			name:   "CompleteOIDCLogin with the same mapped role",
+			opt:    mappedOpt,
+			claims: with(map[string]interface{}{"groups": []string{"news-editors", "news-admins"}}),
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(linkedUser(entity.UserRoleAdmin), nil)
+			},
+			expectedRole: entity.UserRoleAdmin,
+		},
+		{
+			name:         "CompleteOIDCLogin with unknown state",
+			opt:          opt,
+			claims:       jane,
+			unknownState: true,
+			expectedErr:  ErrInvalidOIDCState,
+			expectErr:    true,
+		},
+		{
+			name:          "CompleteOIDCLogin with error of provider",
+			opt:           opt,
+			claims:        jane,
+			providerError: "access_denied",
+			expectedErr:   ErrOIDCLoginRejected,
+			expectErr:     true,
+		},
+		{
+			name:        "CompleteOIDCLogin with ID token of another request",
+			opt:         opt,
+			claims:      with(map[string]interface{}{"nonce": "another"}),
+			expectedErr: ErrOIDCLoginRejected,
+			expectErr:   true,
+		},
+		{
+			name:   "CompleteOIDCLogin with unexpected error in storage",
+			opt:    opt,
+			claims: jane,
+			userMock: func(m *mocks.UserStorage) {
+				m.On("GetByOIDCSubject", context.Background(), subject).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userStorageMock := mocks.NewUserStorage(t)
+			if tc.userMock != nil {
+				tc.userMock(userStorageMock)
+			}
+			refreshTokenStorageMock := mocks.NewRefreshTokenStorage(t)
+			if !tc.expectErr {
+				refreshTokenStorageMock.On("Create", context.Background(), mock.Anything).Return(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+					return rt, nil
+				})
+			}
+			requestStorageMock := mocks.NewOIDCAuthRequestStorage(t)
+			requestStorageMock.On("Create", context.Background(), mock.Anything).Return(func(_ context.Context, r *entity.OIDCAuthRequest) (*entity.OIDCAuthRequest, error) {
+				if tc.unknownState {
+					requestStorageMock.On("Take", context.Background(), r.State, mock.AnythingOfType("time.Time")).Return(nil, ErrTakeOIDCAuthRequestNotFound)
+				} else {
+					requestStorageMock.On("Take", context.Background(), r.State, mock.AnythingOfType("time.Time")).Return(r, nil)
+				}
+				return r, nil
+			})
+			storages := Storages{User: userStorageMock, RefreshToken: refreshTokenStorageMock, OIDCAuthRequest: requestStorageMock}
+
+			authService := NewAuthService(storages, tc.opt, logger)
+			authURL, err := authService.StartOIDCLogin(context.Background())
+			require.NoError(t, err, "failed to start OIDC login")
+			code, state, err := idp.Authorize(authURL, tc.claims)
+			require.NoError(t, err, "failed to sign in at provider")
+
+			actual, err := authService.CompleteOIDCLogin(context.Background(), CompleteOIDCLoginOpt{State: state, Code: code, Error: tc.providerError})
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to complete OIDC login")
+
+				principal, err := authService.Authenticate(context.Background(), actual.AccessToken)
+				require.NoError(t, err, "failed to authenticate with access token")
+				require.Equal(t, userID, principal.UserID, "user IDs are not equal")
+				require.Equal(t, tc.expectedRole, principal.Role, "roles are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "tokens are not nil")
+			}
+		})
+	}
+}
+
+func TestAuthService_CompleteOIDCLogin_Disabled(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	authService := NewAuthService(Storages{}, testAuthOptions, logger)
+	actual, err := authService.CompleteOIDCLogin(context.Background(), CompleteOIDCLoginOpt{State: "state", Code: "code"})
+	require.ErrorIs(t, err, ErrOIDCDisabled, "unexpected error")
+	require.Nil(t, actual, "tokens are not nil")
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+)
+
+// systemPrincipal is used by background jobs, which act on behalf of the app rather than a user
+var systemPrincipal = &Principal{Role: entity.UserRoleAdmin}
+
+// SystemContext returns the context for background jobs, the policy allows them everything
+func SystemContext(ctx context.Context) context.Context {
+	return ContextWithPrincipal(ctx, systemPrincipal)
+}
+
+// authorize checks that the principal of the context has the role or a more privileged one,
+// API keys must have the scope too. Anonymous requests get ErrAuthenticationRequired unless reading is enough.
+func authorize(ctx context.Context, role entity.UserRole, scope entity.APIKeyScope) error {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		if role == entity.UserRoleReader {
+			return nil
+		}
+
+		return ErrAuthenticationRequired
+	}
+
+	if !principal.Role.Includes(role) || !principal.HasScope(scope) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizePostEdit lets editors edit any post and authors only their own ones,
+// the post is got only when its owner matters
+func authorizePostEdit(ctx context.Context, getPost func() (*entity.Post, error)) error {
+	err := authorize(ctx, entity.UserRoleAuthor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		return err
+	}
+
+	principal := PrincipalFromContext(ctx)
+	if principal.Role.Includes(entity.UserRoleEditor) {
+		return nil
+	}
+
+	post, err := getPost()
+	if err != nil {
+		return err
+	}
+
+	if !ownsPost(principal, post) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizePostRead lets everyone read published posts, while drafts and archived posts are seen only by editors
+// and their owners. The others get ErrGetPostNotFound, so they can't even tell that the post exists.
+func authorizePostRead(ctx context.Context, post *entity.Post) error {
+	if post.Status == entity.PostStatusPublished {
+		return nil
+	}
+
+	if authorize(ctx, entity.UserRoleAuthor, entity.APIKeyScopePostsRead) != nil {
+		return ErrGetPostNotFound
+	}
+
+	principal := PrincipalFromContext(ctx)
+	if principal.Role.Includes(entity.UserRoleEditor) {
+		return nil
+	}
+
+	if !ownsPost(principal, post) {
+		return ErrGetPostNotFound
+	}
+
+	return nil
+}
+
+// ownsPost tells whether the principal created the post, API keys own the posts created with them
+func ownsPost(principal *Principal, post *entity.Post) bool {
+	if principal.APIKeyID != "" {
+		return post.OwnerAPIKeyID != nil && *post.OwnerAPIKeyID == principal.APIKeyID
+	}
+
+	return post.OwnerID != nil && *post.OwnerID == principal.UserID
+}
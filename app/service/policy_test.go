@@ -0,0 +1,655 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	editorID = uuid.NewString()
+
+	// editorCtx passes the policy of all post and moderation methods, except the ones of background jobs
+	editorCtx = ContextWithPrincipal(context.Background(), &Principal{UserID: editorID, Role: entity.UserRoleEditor})
+	systemCtx = SystemContext(context.Background())
+)
+
+func principalCtx(userID string, role entity.UserRole) context.Context {
+	return ContextWithPrincipal(context.Background(), &Principal{UserID: userID, Role: role})
+}
+
+// apiKeyCtx has the role of the scope, as Authenticate of API keys gives it
+func apiKeyCtx(scope entity.APIKeyScope) context.Context {
+	return ContextWithPrincipal(context.Background(), &Principal{
+		APIKeyID: uuid.NewString(),
+		Role:     scope.Role(),
+		Scopes:   []entity.APIKeyScope{scope},
+	})
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		role        entity.UserRole
+		scope       entity.APIKeyScope
+		expectedErr error
+	}{
+		{
+			name:  "Anonymous reads",
+			ctx:   context.Background(),
+			role:  entity.UserRoleReader,
+			scope: entity.APIKeyScopePostsRead,
+		},
+		{
+			name:        "Anonymous writes",
+			ctx:         context.Background(),
+			role:        entity.UserRoleAuthor,
+			scope:       entity.APIKeyScopePostsWrite,
+			expectedErr: ErrAuthenticationRequired,
+		},
+		{
+			name:        "Reader writes",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleReader),
+			role:        entity.UserRoleAuthor,
+			scope:       entity.APIKeyScopePostsWrite,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:  "Author writes",
+			ctx:   principalCtx(uuid.NewString(), entity.UserRoleAuthor),
+			role:  entity.UserRoleAuthor,
+			scope: entity.APIKeyScopePostsWrite,
+		},
+		{
+			name:        "Author publishes",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleAuthor),
+			role:        entity.UserRoleEditor,
+			scope:       entity.APIKeyScopePostsWrite,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:  "Editor publishes",
+			ctx:   principalCtx(uuid.NewString(), entity.UserRoleEditor),
+			role:  entity.UserRoleEditor,
+			scope: entity.APIKeyScopePostsWrite,
+		},
+		{
+			name:        "Editor manages users",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleEditor),
+			role:        entity.UserRoleAdmin,
+			scope:       entity.APIKeyScopeAdmin,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:  "Admin publishes",
+			ctx:   principalCtx(uuid.NewString(), entity.UserRoleAdmin),
+			role:  entity.UserRoleEditor,
+			scope: entity.APIKeyScopePostsWrite,
+		},
+		{
+			name:  "Admin manages users",
+			ctx:   principalCtx(uuid.NewString(), entity.UserRoleAdmin),
+			role:  entity.UserRoleAdmin,
+			scope: entity.APIKeyScopeAdmin,
+		},
+		{
+			name:        "Unknown role reads",
+			ctx:         principalCtx(uuid.NewString(), "root"),
+			role:        entity.UserRoleReader,
+			scope:       entity.APIKeyScopePostsRead,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:  "API key reads",
+			ctx:   apiKeyCtx(entity.APIKeyScopePostsRead),
+			role:  entity.UserRoleReader,
+			scope: entity.APIKeyScopePostsRead,
+		},
+		{
+			name:        "Read-only API key writes",
+			ctx:         apiKeyCtx(entity.APIKeyScopePostsRead),
+			role:        entity.UserRoleAuthor,
+			scope:       entity.APIKeyScopePostsWrite,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:  "Writing API key writes",
+			ctx:   apiKeyCtx(entity.APIKeyScopePostsWrite),
+			role:  entity.UserRoleAuthor,
+			scope: entity.APIKeyScopePostsWrite,
+		},
+		{
+			name:        "Writing API key publishes",
+			ctx:         apiKeyCtx(entity.APIKeyScopePostsWrite),
+			role:        entity.UserRoleEditor,
+			scope:       entity.APIKeyScopePostsWrite,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "Writing API key moderates",
+			ctx:         apiKeyCtx(entity.APIKeyScopePostsWrite),
+			role:        entity.UserRoleEditor,
+			scope:       entity.APIKeyScopeAdmin,
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:  "Admin API key manages users",
+			ctx:   apiKeyCtx(entity.APIKeyScopeAdmin),
+			role:  entity.UserRoleAdmin,
+			scope: entity.APIKeyScopeAdmin,
+		},
+		{
+			name:  "System job",
+			ctx:   systemCtx,
+			role:  entity.UserRoleAdmin,
+			scope: entity.APIKeyScopeAdmin,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := authorize(tc.ctx, tc.role, tc.scope)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+// TestPostService_Policy checks every role against every post method, storages succeed,
+// so only the policy decides whether a method fails
+func TestPostService_Policy(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	authorID := uuid.NewString()
+	writingKeyID := uuid.NewString()
+	postID := uuid.NewString()
+	tomorrow := time.Now().Add(24 * time.Hour)
+	content := "content"
+
+	type call struct {
+		name string
+		// owned tells whether the author or the writing API key of the test case owns the post
+		owned bool
+		// published posts can be unpublished and archived, drafts can be published and scheduled
+		published bool
+		// expectedErr overrides the one of the test case, hidden drafts aren't found rather than forbidden
+		expectedErr error
+		call        func(ctx context.Context, s *postService) error
+	}
+	calls := []call{
+		{name: "Create", call: func(ctx context.Context, s *postService) error {
+			_, err := s.Create(ctx, CreatePostOpt{Title: "title", Content: "content"})
+			return err
+		}},
+		{name: "CreateScheduled", call: func(ctx context.Context, s *postService) error {
+			_, err := s.Create(ctx, CreatePostOpt{Title: "title", Content: "content", PublishAt: &tomorrow})
+			return err
+		}},
+		{name: "List", call: func(ctx context.Context, s *postService) error {
+			_, err := s.List(ctx, ListPostsOpt{})
+			return err
+		}},
+		{name: "Search", call: func(ctx context.Context, s *postService) error {
+			_, err := s.Search(ctx, SearchPostsOpt{Query: "go"})
+			return err
+		}},
+		{name: "Get", published: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Get(ctx, postID)
+			return err
+		}},
+		{name: "GetOwnDraft", owned: true, expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Get(ctx, postID)
+			return err
+		}},
+		{name: "GetOthersDraft", expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Get(ctx, postID)
+			return err
+		}},
+		{name: "GetBySlug", published: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.GetBySlug(ctx, "slug")
+			return err
+		}},
+		{name: "GetBySlugOwnDraft", owned: true, expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.GetBySlug(ctx, "slug")
+			return err
+		}},
+		{name: "GetBySlugOthersDraft", expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.GetBySlug(ctx, "slug")
+			return err
+		}},
+		{name: "UpdateOwn", owned: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Update(ctx, postID, UpdatePostOpt{Content: &content})
+			return err
+		}},
+		{name: "UpdateOthers", call: func(ctx context.Context, s *postService) error {
+			_, err := s.Update(ctx, postID, UpdatePostOpt{Content: &content})
+			return err
+		}},
+		{name: "ScheduleOwn", owned: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Update(ctx, postID, UpdatePostOpt{PublishAt: &tomorrow})
+			return err
+		}},
+		{name: "DeleteOwn", owned: true, call: func(ctx context.Context, s *postService) error {
+			return s.Delete(ctx, postID, 0)
+		}},
+		{name: "DeleteOthers", call: func(ctx context.Context, s *postService) error {
+			return s.Delete(ctx, postID, 0)
+		}},
+		{name: "ListTrash", call: func(ctx context.Context, s *postService) error {
+			_, err := s.ListTrash(ctx, ListPostsOpt{})
+			return err
+		}},
+		{name: "Restore", call: func(ctx context.Context, s *postService) error {
+			_, err := s.Restore(ctx, postID, 0)
+			return err
+		}},
+		{name: "Purge", call: func(ctx context.Context, s *postService) error {
+			return s.Purge(ctx, postID, 0)
+		}},
+		{name: "PurgeTrash", call: func(ctx context.Context, s *postService) error {
+			_, err := s.PurgeTrash(ctx, time.Hour)
+			return err
+		}},
+		{name: "PublishOwn", owned: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Publish(ctx, postID, 0)
+			return err
+		}},
+		{name: "Unpublish", published: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Unpublish(ctx, postID, 0)
+			return err
+		}},
+		{name: "Archive", published: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.Archive(ctx, postID, 0)
+			return err
+		}},
+		{name: "PublishScheduled", call: func(ctx context.Context, s *postService) error {
+			_, err := s.PublishScheduled(ctx)
+			return err
+		}},
+		{name: "BackfillSlugs", call: func(ctx context.Context, s *postService) error {
+			_, err := s.BackfillSlugs(ctx)
+			return err
+		}},
+		{name: "ListRevisions", published: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.ListRevisions(ctx, postID)
+			return err
+		}},
+		{name: "ListOwnDraftRevisions", owned: true, expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.ListRevisions(ctx, postID)
+			return err
+		}},
+		{name: "ListOthersDraftRevisions", expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.ListRevisions(ctx, postID)
+			return err
+		}},
+		{name: "GetRevision", published: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.GetRevision(ctx, postID, 1)
+			return err
+		}},
+		{name: "GetOwnDraftRevision", owned: true, expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.GetRevision(ctx, postID, 1)
+			return err
+		}},
+		{name: "GetOthersDraftRevision", expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.GetRevision(ctx, postID, 1)
+			return err
+		}},
+		{name: "DiffRevisions", published: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.DiffRevisions(ctx, postID, 1, 1)
+			return err
+		}},
+		{name: "DiffOwnDraftRevisions", owned: true, expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.DiffRevisions(ctx, postID, 1, 1)
+			return err
+		}},
+		{name: "DiffOthersDraftRevisions", expectedErr: ErrGetPostNotFound, call: func(ctx context.Context, s *postService) error {
+			_, err := s.DiffRevisions(ctx, postID, 1, 1)
+			return err
+		}},
+		{name: "RestoreOwnRevision", owned: true, call: func(ctx context.Context, s *postService) error {
+			_, err := s.RestoreRevision(ctx, postID, 1, 0)
+			return err
+		}},
+		{name: "RestoreOthersRevision", call: func(ctx context.Context, s *postService) error {
+			_, err := s.RestoreRevision(ctx, postID, 1, 0)
+			return err
+		}},
+		{name: "BatchDeleteOwn", owned: true, call: func(ctx context.Context, s *postService) error {
+			results, err := s.Batch(ctx, BatchPostsOpt{Operations: []PostOperation{{Type: PostOperationDelete, ID: postID}}})
+			if err != nil {
+				return err
+			}
+
+			return results[0].Err
+		}},
+		{name: "BatchDeleteOthers", call: func(ctx context.Context, s *postService) error {
+			results, err := s.Batch(ctx, BatchPostsOpt{Operations: []PostOperation{{Type: PostOperationDelete, ID: postID}}})
+			if err != nil {
+				return err
+			}
+
+			return results[0].Err
+		}},
+	}
+
+	// allowed lists the calls each role can make, the others must fail with the expected error
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		allowed     []string
+		expectedErr error
+	}{
+		{
+			name:        "Anonymous",
+			ctx:         context.Background(),
+			allowed:     []string{"List", "Search", "Get", "GetBySlug", "ListRevisions", "GetRevision", "DiffRevisions"},
+			expectedErr: ErrAuthenticationRequired,
+		},
+		{
+			name:        "Reader",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleReader),
+			allowed:     []string{"List", "Search", "Get", "GetBySlug", "ListRevisions", "GetRevision", "DiffRevisions"},
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "Author",
+			ctx:  principalCtx(authorID, entity.UserRoleAuthor),
+			allowed: []string{
+				"Create", "List", "Search", "Get", "GetOwnDraft", "GetBySlug", "GetBySlugOwnDraft", "UpdateOwn",
+				"DeleteOwn", "ListRevisions", "ListOwnDraftRevisions", "GetRevision", "GetOwnDraftRevision",
+				"DiffRevisions", "DiffOwnDraftRevisions", "RestoreOwnRevision", "BatchDeleteOwn",
+			},
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "Editor",
+			ctx:  principalCtx(uuid.NewString(), entity.UserRoleEditor),
+			allowed: []string{
+				"Create", "CreateScheduled", "List", "Search", "Get", "GetOwnDraft", "GetOthersDraft", "GetBySlug",
+				"GetBySlugOwnDraft", "GetBySlugOthersDraft", "UpdateOwn", "UpdateOthers", "ScheduleOwn", "DeleteOwn",
+				"DeleteOthers", "ListTrash", "Restore", "Purge", "PublishOwn", "Unpublish", "Archive", "ListRevisions",
+				"ListOwnDraftRevisions", "ListOthersDraftRevisions", "GetRevision", "GetOwnDraftRevision",
+				"GetOthersDraftRevision", "DiffRevisions", "DiffOwnDraftRevisions", "DiffOthersDraftRevisions",
+				"RestoreOwnRevision", "RestoreOthersRevision", "BatchDeleteOwn", "BatchDeleteOthers",
+			},
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "Read-only API key",
+			ctx:         apiKeyCtx(entity.APIKeyScopePostsRead),
+			allowed:     []string{"List", "Search", "Get", "GetBySlug", "ListRevisions", "GetRevision", "DiffRevisions"},
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "Writing API key",
+			ctx: ContextWithPrincipal(context.Background(), &Principal{
+				APIKeyID: writingKeyID,
+				Role:     entity.APIKeyScopePostsWrite.Role(),
+				Scopes:   []entity.APIKeyScope{entity.APIKeyScopePostsWrite},
+			}),
+			allowed: []string{
+				"Create", "List", "Search", "Get", "GetOwnDraft", "GetBySlug", "GetBySlugOwnDraft", "UpdateOwn",
+				"DeleteOwn", "ListRevisions", "ListOwnDraftRevisions", "GetRevision", "GetOwnDraftRevision",
+				"DiffRevisions", "DiffOwnDraftRevisions", "RestoreOwnRevision", "BatchDeleteOwn",
+			},
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "Admin API key",
+			ctx:  apiKeyCtx(entity.APIKeyScopeAdmin),
+			allowed: []string{
+				"Create", "CreateScheduled", "List", "Search", "Get", "GetOwnDraft", "GetOthersDraft", "GetBySlug",
+				"GetBySlugOwnDraft", "GetBySlugOthersDraft", "UpdateOwn", "UpdateOthers", "ScheduleOwn", "DeleteOwn",
+				"DeleteOthers", "ListTrash", "Restore", "Purge", "PurgeTrash", "PublishOwn", "Unpublish", "Archive",
+				"PublishScheduled", "BackfillSlugs", "ListRevisions", "ListOwnDraftRevisions", "ListOthersDraftRevisions",
+				"GetRevision", "GetOwnDraftRevision", "GetOthersDraftRevision", "DiffRevisions", "DiffOwnDraftRevisions",
+				"DiffOthersDraftRevisions", "RestoreOwnRevision", "RestoreOthersRevision", "BatchDeleteOwn",
+				"BatchDeleteOthers",
+			},
+		},
+		{
+			name: "Admin",
+			ctx:  principalCtx(uuid.NewString(), entity.UserRoleAdmin),
+			allowed: []string{
+				"Create", "CreateScheduled", "List", "Search", "Get", "GetOwnDraft", "GetOthersDraft", "GetBySlug",
+				"GetBySlugOwnDraft", "GetBySlugOthersDraft", "UpdateOwn", "UpdateOthers", "ScheduleOwn", "DeleteOwn",
+				"DeleteOthers", "ListTrash", "Restore", "Purge", "PurgeTrash", "PublishOwn", "Unpublish", "Archive",
+				"PublishScheduled", "BackfillSlugs", "ListRevisions", "ListOwnDraftRevisions", "ListOthersDraftRevisions",
+				"GetRevision", "GetOwnDraftRevision", "GetOthersDraftRevision", "DiffRevisions", "DiffOwnDraftRevisions",
+				"DiffOthersDraftRevisions", "RestoreOwnRevision", "RestoreOthersRevision", "BatchDeleteOwn",
+				"BatchDeleteOthers",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		for _, c := range calls {
+			c := c
+			t.Run(tc.name+" "+c.name, func(t *testing.T) {
+				t.Parallel()
+
+				ownerID := uuid.NewString()
+				if c.owned {
+					ownerID = authorID
+				}
+				post := &entity.Post{ID: postID, Status: entity.PostStatusDraft, OwnerID: &ownerID}
+				if c.owned {
+					post.OwnerAPIKeyID = &writingKeyID
+				}
+				if c.published {
+					post.Status = entity.PostStatusPublished
+				}
+				revision := &entity.PostRevision{PostID: postID, Number: 1, Title: "title", Content: "content"}
+
+				postStorageMock := mocks.NewPostStorage(t)
+				postStorageMock.On("Get", tc.ctx, postID).Return(post, nil).Maybe()
+				postStorageMock.On("Create", tc.ctx, mock.Anything).Return(post, nil).Maybe()
+				postStorageMock.On("List", tc.ctx, mock.Anything).Return([]entity.Post{}, nil).Maybe()
+				postStorageMock.On("Search", tc.ctx, mock.Anything).Return([]entity.PostSearchResult{}, nil).Maybe()
+				postStorageMock.On("GetBySlug", tc.ctx, "slug").Return(post, nil).Maybe()
+				postStorageMock.On("Update", tc.ctx, postID, mock.Anything).Return(post, nil).Maybe()
+				postStorageMock.On("Delete", tc.ctx, postID, 0).Return(nil).Maybe()
+				postStorageMock.On("Restore", tc.ctx, postID, 0).Return(post, nil).Maybe()
+				postStorageMock.On("Purge", tc.ctx, postID, 0).Return(nil).Maybe()
+				postStorageMock.On("PurgeDeletedBefore", tc.ctx, mock.Anything).Return(int64(0), nil).Maybe()
+				postStorageMock.On("UpdateStatus", tc.ctx, postID, mock.Anything, mock.Anything, mock.Anything, 0).Return(post, nil).Maybe()
+				postStorageMock.On("PublishDue", tc.ctx, mock.Anything, publishScheduledBatchSize).Return([]entity.Post{}, nil).Maybe()
+				postStorageMock.On("ListWithoutSlug", tc.ctx, backfillSlugsBatchSize).Return([]entity.Post{}, nil).Maybe()
+				postStorageMock.On("ListRevisions", tc.ctx, postID).Return([]entity.PostRevision{}, nil).Maybe()
+				postStorageMock.On("GetRevision", tc.ctx, postID, 1).Return(revision, nil).Maybe()
+
+				postService := NewPostService(Storages{Post: postStorageMock}, logger)
+				err := c.call(tc.ctx, postService)
+
+				for _, allowed := range tc.allowed {
+					if allowed == c.name {
+						require.NoError(t, err)
+						return
+					}
+				}
+				expectedErr := tc.expectedErr
+				if c.expectedErr != nil {
+					expectedErr = c.expectedErr
+				}
+				require.True(t, errors.Is(err, expectedErr), "expected %v, got %v", expectedErr, err)
+			})
+		}
+	}
+}
+
+func TestModerationService_Policy(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		expectedErr error
+	}{
+		{
+			name:        "Anonymous",
+			ctx:         context.Background(),
+			expectedErr: ErrAuthenticationRequired,
+		},
+		{
+			name:        "Reader",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleReader),
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "Author",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleAuthor),
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "Editor",
+			ctx:  principalCtx(uuid.NewString(), entity.UserRoleEditor),
+		},
+		{
+			name: "Admin",
+			ctx:  principalCtx(uuid.NewString(), entity.UserRoleAdmin),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			commentStorageMock := mocks.NewCommentStorage(t)
+			if tc.expectedErr == nil {
+				commentStorageMock.On("ListForModeration", tc.ctx, mock.Anything).Return([]entity.Comment{}, nil)
+			}
+
+			moderationService := NewModerationService(Storages{Comment: commentStorageMock}, false, logger)
+			_, err := moderationService.ListComments(tc.ctx, ListModerationCommentsOpt{})
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+// TestCatalogService_Policy checks every role against the methods changing categories and authors,
+// which are shared by all posts, so only editors manage them
+func TestCatalogService_Policy(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	categoryID := uuid.NewString()
+	calls := []struct {
+		name string
+		call func(ctx context.Context, storages Storages) error
+	}{
+		{name: "CreateCategory", call: func(ctx context.Context, storages Storages) error {
+			_, err := NewCategoryService(storages, logger).Create(ctx, CreateCategoryOpt{Name: "World"})
+			return err
+		}},
+		{name: "UpdateCategory", call: func(ctx context.Context, storages Storages) error {
+			_, err := NewCategoryService(storages, logger).Update(ctx, categoryID, UpdateCategoryOpt{Name: "Europe"})
+			return err
+		}},
+		{name: "DeleteCategory", call: func(ctx context.Context, storages Storages) error {
+			return NewCategoryService(storages, logger).Delete(ctx, categoryID)
+		}},
+		{name: "CreateAuthor", call: func(ctx context.Context, storages Storages) error {
+			_, err := NewAuthorService(storages, logger).Create(ctx, CreateAuthorOpt{Name: "Jane Doe", Slug: "jane-doe"})
+			return err
+		}},
+	}
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		expectedErr error
+	}{
+		{
+			name:        "Anonymous",
+			ctx:         context.Background(),
+			expectedErr: ErrAuthenticationRequired,
+		},
+		{
+			name:        "Reader",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleReader),
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "Author",
+			ctx:         principalCtx(uuid.NewString(), entity.UserRoleAuthor),
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "Editor",
+			ctx:  principalCtx(uuid.NewString(), entity.UserRoleEditor),
+		},
+		{
+			name:        "Read-only API key",
+			ctx:         apiKeyCtx(entity.APIKeyScopePostsRead),
+			expectedErr: ErrForbidden,
+		},
+		{
+			name:        "Writing API key",
+			ctx:         apiKeyCtx(entity.APIKeyScopePostsWrite),
+			expectedErr: ErrForbidden,
+		},
+		{
+			name: "Admin API key",
+			ctx:  apiKeyCtx(entity.APIKeyScopeAdmin),
+		},
+		{
+			name: "Admin",
+			ctx:  principalCtx(uuid.NewString(), entity.UserRoleAdmin),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		for _, c := range calls {
+			c := c
+			t.Run(tc.name+" "+c.name, func(t *testing.T) {
+				t.Parallel()
+
+				categoryStorageMock := mocks.NewCategoryStorage(t)
+				categoryStorageMock.On("Create", tc.ctx, mock.Anything).Return(&entity.Category{ID: categoryID}, nil).Maybe()
+				categoryStorageMock.On("Update", tc.ctx, categoryID, mock.Anything).Return(&entity.Category{ID: categoryID}, nil).Maybe()
+				categoryStorageMock.On("Delete", tc.ctx, categoryID).Return(nil).Maybe()
+
+				authorStorageMock := mocks.NewAuthorStorage(t)
+				authorStorageMock.On("Create", tc.ctx, mock.Anything).Return(&entity.Author{ID: uuid.NewString()}, nil).Maybe()
+
+				err := c.call(tc.ctx, Storages{Category: categoryStorageMock, Author: authorStorageMock})
+				require.Equal(t, tc.expectedErr, err)
+			})
+		}
+	}
+}
@@ -5,11 +5,26 @@ import (
 	"darkness8129/news-api/app/entity"
 	"darkness8129/news-api/packages/errs"
 	"darkness8129/news-api/packages/logging"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmezard/go-difflib/difflib"
 )
 
 var _ PostService = (*postService)(nil)
 
+const (
+	defaultListPostsLimit = 20
+	maxListPostsLimit     = 100
+
+	publishScheduledBatchSize = 100
+	backfillSlugsBatchSize    = 100
+)
+
 type postService struct {
 	storages Storages
 	logger   logging.Logger
@@ -22,10 +37,77 @@ func NewPostService(storages Storages, logger logging.Logger) *postService {
 func (s *postService) Create(ctx context.Context, opt CreatePostOpt) (*entity.Post, error) {
 	logger := s.logger.Named("Create")
 
-	createdPost, err := s.storages.Post.Create(ctx, &entity.Post{
-		Title:   opt.Title,
-		Content: opt.Content,
-	})
+	// scheduling is publishing in advance, so it's up to editors too
+	role := entity.UserRoleAuthor
+	if opt.PublishAt != nil {
+		role = entity.UserRoleEditor
+	}
+	err := authorize(ctx, role, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if opt.PublishAt != nil && !opt.PublishAt.After(time.Now()) {
+		logger.Info("publishAt is not in the future", "publishAt", opt.PublishAt)
+		return nil, ErrPublishAtNotInFuture
+	}
+
+	if opt.CategoryID != nil {
+		err := checkCategoryExists(ctx, s.storages.Category, *opt.CategoryID)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to check category", "err", err)
+			return nil, fmt.Errorf("failed to check category: %w", err)
+		}
+	}
+
+	post := &entity.Post{
+		Title:      opt.Title,
+		Content:    opt.Content,
+		Status:     entity.PostStatusDraft,
+		PublishAt:  opt.PublishAt,
+		CategoryID: opt.CategoryID,
+	}
+	if principal := PrincipalFromContext(ctx); principal.UserID != "" {
+		post.OwnerID = &principal.UserID
+	} else if principal.APIKeyID != "" {
+		post.OwnerAPIKeyID = &principal.APIKeyID
+	}
+	if len(opt.Tags) > 0 {
+		tags, err := ensureTags(ctx, s.storages.Tag, opt.Tags)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to ensure tags", "err", err)
+			return nil, fmt.Errorf("failed to ensure tags: %w", err)
+		}
+
+		post.Tags = tags
+	}
+	if len(opt.AuthorIDs) > 0 {
+		authors, err := postAuthors(ctx, s.storages.Author, opt.AuthorIDs)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to get post authors", "err", err)
+			return nil, fmt.Errorf("failed to get post authors: %w", err)
+		}
+
+		post.Authors = authors
+	}
+
+	createdPost, err := createPostWithSlug(ctx, s.storages.Post, post)
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
@@ -40,10 +122,17 @@ func (s *postService) Create(ctx context.Context, opt CreatePostOpt) (*entity.Po
 	return createdPost, nil
 }
 
-func (s *postService) List(ctx context.Context) ([]entity.Post, error) {
+func (s *postService) List(ctx context.Context, opt ListPostsOpt) (*ListPostsResult, error) {
 	logger := s.logger.Named("List")
 
-	posts, err := s.storages.Post.List(ctx)
+	err := authorize(ctx, entity.UserRoleReader, entity.APIKeyScopePostsRead)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	// only published posts are public
+	result, err := s.list(ctx, logger, opt, entity.PostsFilter{Status: entity.PostStatusPublished})
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
@@ -54,14 +143,204 @@ func (s *postService) List(ctx context.Context) ([]entity.Post, error) {
 		return nil, fmt.Errorf("failed to list posts: %w", err)
 	}
 
-	logger.Info("successfully listed posts", "posts", posts)
-	return posts, nil
+	logger.Info("successfully listed posts", "result", result)
+	return result, nil
+}
+
+func (s *postService) ListTrash(ctx context.Context, opt ListPostsOpt) (*ListPostsResult, error) {
+	logger := s.logger.Named("ListTrash")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	result, err := s.list(ctx, logger, opt, entity.PostsFilter{OnlyDeleted: true})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list deleted posts", "err", err)
+		return nil, fmt.Errorf("failed to list deleted posts: %w", err)
+	}
+
+	logger.Info("successfully listed deleted posts", "result", result)
+	return result, nil
+}
+
+// list returns a page of posts selected by the base filter extended with opt
+func (s *postService) list(
+	ctx context.Context, logger logging.Logger, opt ListPostsOpt, filter entity.PostsFilter,
+) (*ListPostsResult, error) {
+	limit := opt.Limit
+	if limit <= 0 {
+		limit = defaultListPostsLimit
+	}
+	if limit > maxListPostsLimit {
+		limit = maxListPostsLimit
+	}
+
+	sort := opt.Sort
+	if sort == "" {
+		sort = entity.PostsSortCreatedAtDesc
+	}
+	if !sort.IsValid() {
+		logger.Info("invalid sort", "sort", sort)
+		return nil, ErrListPostsInvalidSort
+	}
+
+	if opt.CreatedFrom != nil && opt.CreatedTo != nil && opt.CreatedTo.Before(*opt.CreatedFrom) {
+		logger.Info("invalid created range", "createdFrom", opt.CreatedFrom, "createdTo", opt.CreatedTo)
+		return nil, ErrListPostsInvalidRange
+	}
+
+	filter.CreatedFrom = opt.CreatedFrom
+	filter.CreatedTo = opt.CreatedTo
+	filter.UpdatedSince = opt.UpdatedSince
+	filter.TitleContains = opt.TitleContains
+	if len(opt.Tags) > 0 {
+		tags, err := normalizeTagNames(opt.Tags)
+		if err != nil {
+			logger.Info("invalid tags", "tags", opt.Tags)
+			return nil, err
+		}
+
+		filter.Tags = tags
+		filter.TagsMatchAll = opt.TagsMatchAll
+	}
+	if opt.CategoryID != "" {
+		_, err := s.storages.Category.Get(ctx, opt.CategoryID)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to get category", "err", err)
+			return nil, fmt.Errorf("failed to get category: %w", err)
+		}
+
+		filter.CategoryID = opt.CategoryID
+	}
+	if opt.AuthorID != "" {
+		_, err := s.storages.Author.Get(ctx, opt.AuthorID)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to get author", "err", err)
+			return nil, fmt.Errorf("failed to get author: %w", err)
+		}
+
+		filter.AuthorID = opt.AuthorID
+	}
+	filter.Sort = sort
+	// one extra post is requested to find out whether the next page exists
+	filter.Limit = limit + 1
+	if opt.Cursor != "" {
+		cursor, err := decodePostsCursor(opt.Cursor)
+		if err != nil {
+			logger.Info("failed to decode cursor", "err", err)
+			return nil, ErrListPostsInvalidCursor
+		}
+
+		if cursor.Sort != sort {
+			logger.Info("cursor was issued for another sort", "cursorSort", cursor.Sort, "sort", sort)
+			return nil, ErrListPostsInvalidCursor
+		}
+
+		filter.After = cursor
+	}
+	logger.Debug("built filter", "filter", filter)
+
+	posts, err := s.storages.Post.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ListPostsResult{Posts: posts}
+	if len(posts) > limit {
+		result.Posts = posts[:limit]
+
+		last := result.Posts[limit-1]
+		result.NextCursor, err = encodePostsCursor(&entity.PostsCursor{
+			Sort:      sort,
+			CreatedAt: last.CreatedAt,
+			UpdatedAt: last.UpdatedAt,
+			Title:     last.Title,
+			ID:        last.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cursor: %w", err)
+		}
+	}
+
+	return &result, nil
+}
+
+func (s *postService) Search(ctx context.Context, opt SearchPostsOpt) ([]entity.PostSearchResult, error) {
+	logger := s.logger.Named("Search")
+
+	err := authorize(ctx, entity.UserRoleReader, entity.APIKeyScopePostsRead)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	query := strings.TrimSpace(opt.Query)
+	if query == "" {
+		logger.Info("empty search query")
+		return nil, ErrSearchPostsEmptyQuery
+	}
+
+	limit := opt.Limit
+	if limit <= 0 {
+		limit = defaultListPostsLimit
+	}
+	if limit > maxListPostsLimit {
+		limit = maxListPostsLimit
+	}
+
+	offset := opt.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	results, err := s.storages.Post.Search(ctx, entity.PostsSearchFilter{
+		Status: entity.PostStatusPublished,
+		Query:  query,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to search posts", "err", err)
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	logger.Info("successfully searched posts", "results", results)
+	return results, nil
 }
 
 func (s *postService) Get(ctx context.Context, id string) (*entity.Post, error) {
 	logger := s.logger.Named("Get")
 
-	post, err := s.storages.Post.Get(ctx, id)
+	err := authorize(ctx, entity.UserRoleReader, entity.APIKeyScopePostsRead)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	post, err := s.getVisible(ctx, id)
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
@@ -76,13 +355,134 @@ func (s *postService) Get(ctx context.Context, id string) (*entity.Post, error)
 	return post, nil
 }
 
+func (s *postService) GetBySlug(ctx context.Context, slug string) (*entity.Post, error) {
+	logger := s.logger.Named("GetBySlug")
+
+	err := authorize(ctx, entity.UserRoleReader, entity.APIKeyScopePostsRead)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	post, err := s.storages.Post.GetBySlug(ctx, slug)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post by slug", "err", err)
+		return nil, fmt.Errorf("failed to get post by slug: %w", err)
+	}
+
+	err = authorizePostRead(ctx, post)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	logger.Info("successfully got post by slug", "post", post)
+	return post, nil
+}
+
 func (s *postService) Update(ctx context.Context, id string, opt UpdatePostOpt) (*entity.Post, error) {
 	logger := s.logger.Named("Update")
 
-	updatedPost, err := s.storages.Post.Update(ctx, id, &entity.Post{
-		Title:   opt.Title,
-		Content: opt.Content,
-	})
+	// scheduling is publishing in advance, so it's up to editors too
+	if opt.PublishAt != nil {
+		err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+		if err != nil {
+			logger.Info(err.Error())
+			return nil, err
+		}
+	}
+
+	err := s.authorizeEdit(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to authorize", "err", err)
+		return nil, fmt.Errorf("failed to authorize: %w", err)
+	}
+
+	if opt.PublishAt != nil {
+		if !opt.PublishAt.After(time.Now()) {
+			logger.Info("publishAt is not in the future", "publishAt", opt.PublishAt)
+			return nil, ErrPublishAtNotInFuture
+		}
+
+		post, err := s.storages.Post.Get(ctx, id)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to get post", "err", err)
+			return nil, fmt.Errorf("failed to get post: %w", err)
+		}
+
+		if post.Status != entity.PostStatusDraft {
+			logger.Info("post is not a draft", "status", post.Status)
+			return nil, ErrSchedulePostNotDraft
+		}
+	}
+
+	if opt.CategoryID != nil {
+		err := checkCategoryExists(ctx, s.storages.Category, *opt.CategoryID)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to check category", "err", err)
+			return nil, fmt.Errorf("failed to check category: %w", err)
+		}
+	}
+
+	update := entity.PostUpdate{
+		Title:          opt.Title,
+		Content:        opt.Content,
+		PublishAt:      opt.PublishAt,
+		ClearPublishAt: opt.ClearPublishAt,
+		CategoryID:     opt.CategoryID,
+		ClearCategory:  opt.ClearCategory,
+		Version:        opt.Version,
+	}
+	if opt.Tags != nil {
+		tags, err := ensureTags(ctx, s.storages.Tag, opt.Tags)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to ensure tags", "err", err)
+			return nil, fmt.Errorf("failed to ensure tags: %w", err)
+		}
+
+		update.Tags = &tags
+	}
+	if opt.AuthorIDs != nil {
+		authors, err := postAuthors(ctx, s.storages.Author, opt.AuthorIDs)
+		if err != nil {
+			if errs.IsCustom(err) {
+				logger.Info(err.Error())
+				return nil, err
+			}
+
+			logger.Error("failed to get post authors", "err", err)
+			return nil, fmt.Errorf("failed to get post authors: %w", err)
+		}
+
+		update.Authors = &authors
+	}
+
+	updatedPost, err := updatePostWithSlug(ctx, s.storages.Post, id, update)
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
@@ -97,10 +497,21 @@ func (s *postService) Update(ctx context.Context, id string, opt UpdatePostOpt)
 	return updatedPost, nil
 }
 
-func (s *postService) Delete(ctx context.Context, id string) error {
+func (s *postService) Delete(ctx context.Context, id string, version int) error {
 	logger := s.logger.Named("Delete")
 
-	err := s.storages.Post.Delete(ctx, id)
+	err := s.authorizeEdit(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to authorize", "err", err)
+		return fmt.Errorf("failed to authorize: %w", err)
+	}
+
+	err = s.storages.Post.Delete(ctx, id, version)
 	if err != nil {
 		if errs.IsCustom(err) {
 			logger.Info(err.Error())
@@ -114,3 +525,584 @@ func (s *postService) Delete(ctx context.Context, id string) error {
 	logger.Info("successfully deleted post", "id", id)
 	return nil
 }
+
+func (s *postService) Restore(ctx context.Context, id string, version int) (*entity.Post, error) {
+	logger := s.logger.Named("Restore")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	restoredPost, err := s.storages.Post.Restore(ctx, id, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to restore post", "err", err)
+		return nil, fmt.Errorf("failed to restore post: %w", err)
+	}
+
+	logger.Info("successfully restored post", "restoredPost", restoredPost)
+	return restoredPost, nil
+}
+
+func (s *postService) Purge(ctx context.Context, id string, version int) error {
+	logger := s.logger.Named("Purge")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return err
+	}
+
+	err = s.storages.Post.Purge(ctx, id, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to purge post", "err", err)
+		return fmt.Errorf("failed to purge post: %w", err)
+	}
+
+	logger.Info("successfully purged post", "id", id)
+	return nil
+}
+
+func (s *postService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	logger := s.logger.Named("PurgeTrash")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return 0, err
+	}
+
+	purged, err := s.storages.Post.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return 0, err
+		}
+
+		logger.Error("failed to purge trash", "err", err)
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	logger.Info("successfully purged trash", "purged", purged)
+	return purged, nil
+}
+
+func (s *postService) Publish(ctx context.Context, id string, version int) (*entity.Post, error) {
+	logger := s.logger.Named("Publish")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	publishedPost, err := s.changeStatus(ctx, id, entity.PostStatusPublished, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to publish post", "err", err)
+		return nil, fmt.Errorf("failed to publish post: %w", err)
+	}
+
+	logger.Info("successfully published post", "publishedPost", publishedPost)
+	return publishedPost, nil
+}
+
+func (s *postService) Unpublish(ctx context.Context, id string, version int) (*entity.Post, error) {
+	logger := s.logger.Named("Unpublish")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	unpublishedPost, err := s.changeStatus(ctx, id, entity.PostStatusDraft, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to unpublish post", "err", err)
+		return nil, fmt.Errorf("failed to unpublish post: %w", err)
+	}
+
+	logger.Info("successfully unpublished post", "unpublishedPost", unpublishedPost)
+	return unpublishedPost, nil
+}
+
+func (s *postService) Archive(ctx context.Context, id string, version int) (*entity.Post, error) {
+	logger := s.logger.Named("Archive")
+
+	err := authorize(ctx, entity.UserRoleEditor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	archivedPost, err := s.changeStatus(ctx, id, entity.PostStatusArchived, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to archive post", "err", err)
+		return nil, fmt.Errorf("failed to archive post: %w", err)
+	}
+
+	logger.Info("successfully archived post", "archivedPost", archivedPost)
+	return archivedPost, nil
+}
+
+func (s *postService) PublishScheduled(ctx context.Context) ([]entity.Post, error) {
+	logger := s.logger.Named("PublishScheduled")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	publishedPosts, err := s.storages.Post.PublishDue(ctx, time.Now(), publishScheduledBatchSize)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to publish scheduled posts", "err", err)
+		return nil, fmt.Errorf("failed to publish scheduled posts: %w", err)
+	}
+
+	logger.Info("successfully published scheduled posts", "count", len(publishedPosts))
+	return publishedPosts, nil
+}
+
+func (s *postService) BackfillSlugs(ctx context.Context) (int, error) {
+	logger := s.logger.Named("BackfillSlugs")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return 0, err
+	}
+
+	count := 0
+	for {
+		posts, err := s.storages.Post.ListWithoutSlug(ctx, backfillSlugsBatchSize)
+		if err != nil {
+			logger.Error("failed to list posts without slug", "err", err)
+			return count, fmt.Errorf("failed to list posts without slug: %w", err)
+		}
+		if len(posts) == 0 {
+			break
+		}
+
+		for _, post := range posts {
+			err = setPostSlug(ctx, s.storages.Post, post.ID, post.Title)
+			if err != nil {
+				logger.Error("failed to set post slug", "err", err, "id", post.ID)
+				return count, fmt.Errorf("failed to set post slug: %w", err)
+			}
+		}
+		count += len(posts)
+	}
+
+	logger.Info("successfully backfilled post slugs", "count", count)
+	return count, nil
+}
+
+func (s *postService) ListRevisions(ctx context.Context, id string) ([]entity.PostRevision, error) {
+	logger := s.logger.Named("ListRevisions")
+
+	err := authorize(ctx, entity.UserRoleReader, entity.APIKeyScopePostsRead)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	// the post is checked, so a missing post isn't confused with a post without edits
+	_, err = s.getVisible(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post", "err", err)
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	revisions, err := s.storages.Post.ListRevisions(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list post revisions", "err", err)
+		return nil, fmt.Errorf("failed to list post revisions: %w", err)
+	}
+
+	logger.Info("successfully listed post revisions", "revisions", revisions)
+	return revisions, nil
+}
+
+func (s *postService) GetRevision(ctx context.Context, id string, number int) (*entity.PostRevision, error) {
+	logger := s.logger.Named("GetRevision")
+
+	err := authorize(ctx, entity.UserRoleReader, entity.APIKeyScopePostsRead)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	// revisions of hidden posts are hidden too
+	_, err = s.getVisible(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post", "err", err)
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	revision, err := s.storages.Post.GetRevision(ctx, id, number)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post revision", "err", err)
+		return nil, fmt.Errorf("failed to get post revision: %w", err)
+	}
+
+	logger.Info("successfully got post revision", "revision", revision)
+	return revision, nil
+}
+
+func (s *postService) DiffRevisions(ctx context.Context, id string, from, to int) (*PostRevisionsDiff, error) {
+	logger := s.logger.Named("DiffRevisions")
+
+	err := authorize(ctx, entity.UserRoleReader, entity.APIKeyScopePostsRead)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	// revisions of hidden posts are hidden too
+	_, err = s.getVisible(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post", "err", err)
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	fromRevision, err := s.storages.Post.GetRevision(ctx, id, from)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get from revision", "err", err)
+		return nil, fmt.Errorf("failed to get from revision: %w", err)
+	}
+
+	toRevision, err := s.storages.Post.GetRevision(ctx, id, to)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get to revision", "err", err)
+		return nil, fmt.Errorf("failed to get to revision: %w", err)
+	}
+
+	titleDiff, err := unifiedDiff("title", fromRevision.Title, toRevision.Title, from, to)
+	if err != nil {
+		logger.Error("failed to diff titles", "err", err)
+		return nil, fmt.Errorf("failed to diff titles: %w", err)
+	}
+
+	contentDiff, err := unifiedDiff("content", fromRevision.Content, toRevision.Content, from, to)
+	if err != nil {
+		logger.Error("failed to diff content", "err", err)
+		return nil, fmt.Errorf("failed to diff content: %w", err)
+	}
+
+	diff := PostRevisionsDiff{
+		From:        from,
+		To:          to,
+		TitleDiff:   titleDiff,
+		ContentDiff: contentDiff,
+	}
+
+	logger.Info("successfully diffed post revisions", "diff", diff)
+	return &diff, nil
+}
+
+func (s *postService) RestoreRevision(ctx context.Context, id string, number, version int) (*entity.Post, error) {
+	logger := s.logger.Named("RestoreRevision")
+
+	err := s.authorizeEdit(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to authorize", "err", err)
+		return nil, fmt.Errorf("failed to authorize: %w", err)
+	}
+
+	revision, err := s.storages.Post.GetRevision(ctx, id, number)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to get post revision", "err", err)
+		return nil, fmt.Errorf("failed to get post revision: %w", err)
+	}
+
+	restoredPost, err := updatePostWithSlug(ctx, s.storages.Post, id, entity.PostUpdate{
+		Title:   &revision.Title,
+		Content: &revision.Content,
+		Version: version,
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to restore post revision", "err", err)
+		return nil, fmt.Errorf("failed to restore post revision: %w", err)
+	}
+
+	logger.Info("successfully restored post revision", "restoredPost", restoredPost)
+	return restoredPost, nil
+}
+
+func (s *postService) Batch(ctx context.Context, opt BatchPostsOpt) ([]PostOperationResult, error) {
+	logger := s.logger.Named("Batch")
+
+	err := authorize(ctx, entity.UserRoleAuthor, entity.APIKeyScopePostsWrite)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if !opt.Atomic {
+		results := make([]PostOperationResult, 0, len(opt.Operations))
+		for _, operation := range opt.Operations {
+			post, err := s.applyOperation(ctx, operation)
+			results = append(results, PostOperationResult{Post: post, Err: err})
+		}
+
+		logger.Info("successfully applied post operations", "count", len(results))
+		return results, nil
+	}
+
+	var results []PostOperationResult
+	err = s.storages.Transaction(ctx, func(storages Storages) error {
+		// the operations are applied by the same service, but on storages of the transaction
+		txService := &postService{storages, s.logger}
+
+		results = make([]PostOperationResult, 0, len(opt.Operations))
+		for i, operation := range opt.Operations {
+			post, err := txService.applyOperation(ctx, operation)
+			if err != nil {
+				if errs.IsCustom(err) {
+					return errs.WithMetadata(err, "operation", i)
+				}
+
+				return fmt.Errorf("failed to apply operation %d: %w", i, err)
+			}
+
+			results = append(results, PostOperationResult{Post: post})
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error(), "operation", errs.Metadata(err)["operation"])
+			return nil, err
+		}
+
+		logger.Error("failed to apply post operations", "err", err)
+		return nil, fmt.Errorf("failed to apply post operations: %w", err)
+	}
+
+	logger.Info("successfully applied post operations atomically", "count", len(results))
+	return results, nil
+}
+
+// applyOperation returns nil post for deleting
+func (s *postService) applyOperation(ctx context.Context, operation PostOperation) (*entity.Post, error) {
+	switch operation.Type {
+	case PostOperationCreate:
+		return s.Create(ctx, operation.Create)
+	case PostOperationUpdate:
+		return s.Update(ctx, operation.ID, operation.Update)
+	case PostOperationDelete:
+		return nil, s.Delete(ctx, operation.ID, operation.Version)
+	default:
+		return nil, ErrUnknownPostOperation
+	}
+}
+
+// authorizeEdit checks that the principal can edit the post, see authorizePostEdit
+func (s *postService) authorizeEdit(ctx context.Context, id string) error {
+	return authorizePostEdit(ctx, func() (*entity.Post, error) {
+		return s.storages.Post.Get(ctx, id)
+	})
+}
+
+// getVisible gets the post if the principal may read it, see authorizePostRead
+func (s *postService) getVisible(ctx context.Context, id string) (*entity.Post, error) {
+	post, err := s.storages.Post.Get(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	err = authorizePostRead(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// changeStatus moves the post to the status if the transition is allowed,
+// PublishedAt is set on publishing and cleared when the post goes back to drafts
+func (s *postService) changeStatus(ctx context.Context, id string, to entity.PostStatus, version int) (*entity.Post, error) {
+	post, err := s.storages.Post.Get(ctx, id)
+	if err != nil {
+		if errs.IsCustom(err) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	// a stale version is reported before the transition, the client saw another status
+	if version != 0 && version != post.Version {
+		return nil, ErrPostVersionMismatch
+	}
+
+	if !post.Status.CanTransitionTo(to) {
+		return nil, ErrInvalidPostStatusTransition
+	}
+
+	publishedAt := post.PublishedAt
+	switch to {
+	case entity.PostStatusPublished:
+		now := time.Now()
+		publishedAt = &now
+	case entity.PostStatusDraft:
+		publishedAt = nil
+	}
+
+	updatedPost, err := s.storages.Post.UpdateStatus(ctx, id, post.Status, to, publishedAt, version)
+	if err != nil {
+		if errs.IsCustom(err) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to update post status: %w", err)
+	}
+
+	return updatedPost, nil
+}
+
+// encodePostsCursor makes an opaque cursor, so clients don't rely on its content
+func encodePostsCursor(c *entity.PostsCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodePostsCursor(s string) (*entity.PostsCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+
+	var c entity.PostsCursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cursor: %w", err)
+	}
+
+	if !c.Sort.IsValid() {
+		return nil, fmt.Errorf("cursor has invalid sort")
+	}
+
+	err = uuid.Validate(c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cursor has invalid ID: %w", err)
+	}
+
+	return &c, nil
+}
+
+// unifiedDiff returns an empty string if texts are equal
+func unifiedDiff(field, from, to string, fromNumber, toNumber int) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fmt.Sprintf("%s@%d", field, fromNumber),
+		ToFile:   fmt.Sprintf("%s@%d", field, toNumber),
+		Context:  3,
+	})
+}
+
+// splitLines keeps line endings and terminates the last line, as the diff output is built from whole lines
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+
+	lines[len(lines)-1] += "\n"
+	return lines
+}
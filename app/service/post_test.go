@@ -4,13 +4,17 @@ import (
 	"context"
 	"darkness8129/news-api/app/entity"
 	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/errs"
 	"darkness8129/news-api/packages/logging"
 	"errors"
 	"log"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,40 +27,221 @@ func TestPostService_Create(t *testing.T) {
 		os.Exit(1)
 	}
 
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	tags := []entity.Tag{{ID: uuid.NewString(), Name: "go"}, {ID: uuid.NewString(), Name: "news"}}
+	categoryID := uuid.NewString()
+	firstAuthorID := uuid.NewString()
+	secondAuthorID := uuid.NewString()
+
 	testCases := []struct {
-		name      string
-		mock      func(m *mocks.PostStorage)
-		input     CreatePostOpt
-		expected  *entity.Post
-		expectErr bool
+		name         string
+		mock         func(m *mocks.PostStorage)
+		tagMock      func(m *mocks.TagStorage)
+		categoryMock func(m *mocks.CategoryStorage)
+		authorMock   func(m *mocks.AuthorStorage)
+		input        CreatePostOpt
+		expected     *entity.Post
+		expectErr    bool
 	}{
 		{
 			name: "Create",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Create", context.Background(), &entity.Post{
+				m.On("Create", editorCtx, &entity.Post{
+					OwnerID: &editorID,
+					Title:   "title",
+					Slug:    "title",
+					Content: "content",
+					Status:  entity.PostStatusDraft,
+				}).Return(&entity.Post{
+					ID:      uuid.NewString(),
+					Title:   "title",
+					Content: "content",
+				}, nil)
+			},
+			input: CreatePostOpt{
+				Title:   "title",
+				Content: "content",
+			},
+			expected: &entity.Post{
+				Title:   "title",
+				Content: "content",
+			},
+		},
+		{
+			name: "Create scheduled",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", editorCtx, &entity.Post{
+					OwnerID:   &editorID,
+					Title:     "title",
+					Slug:      "title",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &tomorrow,
+				}).Return(&entity.Post{
+					ID:        uuid.NewString(),
+					Title:     "title",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &tomorrow,
+				}, nil)
+			},
+			input: CreatePostOpt{
+				Title:     "title",
+				Content:   "content",
+				PublishAt: &tomorrow,
+			},
+			expected: &entity.Post{
+				Title:   "title",
+				Content: "content",
+			},
+		},
+		{
+			name: "Create scheduled in the past",
+			mock: func(m *mocks.PostStorage) {},
+			input: CreatePostOpt{
+				Title:     "title",
+				Content:   "content",
+				PublishAt: &yesterday,
+			},
+			expectErr: true,
+		},
+		{
+			name: "Create with tags",
+			tagMock: func(m *mocks.TagStorage) {
+				m.On("Ensure", editorCtx, []string{"go", "news"}).Return(tags, nil)
+			},
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", editorCtx, &entity.Post{
+					OwnerID: &editorID,
 					Title:   "title",
+					Slug:    "title",
 					Content: "content",
+					Status:  entity.PostStatusDraft,
+					Tags:    tags,
 				}).Return(&entity.Post{
 					ID:      uuid.NewString(),
 					Title:   "title",
 					Content: "content",
+					Tags:    tags,
 				}, nil)
 			},
 			input: CreatePostOpt{
 				Title:   "title",
 				Content: "content",
+				Tags:    []string{" Go ", "go", "News"},
 			},
 			expected: &entity.Post{
 				Title:   "title",
 				Content: "content",
+				Tags:    tags,
+			},
+		},
+		{
+			name: "Create with blank tag",
+			mock: func(m *mocks.PostStorage) {},
+			input: CreatePostOpt{
+				Title:   "title",
+				Content: "content",
+				Tags:    []string{"go", " "},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Create in category",
+			categoryMock: func(m *mocks.CategoryStorage) {
+				m.On("Get", editorCtx, categoryID).Return(&entity.Category{ID: categoryID}, nil)
+			},
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", editorCtx, &entity.Post{
+					OwnerID:    &editorID,
+					Title:      "title",
+					Slug:       "title",
+					Content:    "content",
+					Status:     entity.PostStatusDraft,
+					CategoryID: &categoryID,
+				}).Return(&entity.Post{
+					ID:         uuid.NewString(),
+					Title:      "title",
+					Content:    "content",
+					CategoryID: &categoryID,
+				}, nil)
+			},
+			input: CreatePostOpt{
+				Title:      "title",
+				Content:    "content",
+				CategoryID: &categoryID,
+			},
+			expected: &entity.Post{
+				Title:      "title",
+				Content:    "content",
+				CategoryID: &categoryID,
+			},
+		},
+		{
+			name: "Create with authors",
+			authorMock: func(m *mocks.AuthorStorage) {
+				m.On("GetMany", editorCtx, []string{secondAuthorID, firstAuthorID}).
+					Return([]entity.Author{{ID: firstAuthorID}, {ID: secondAuthorID}}, nil)
+			},
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", editorCtx, &entity.Post{
+					OwnerID: &editorID,
+					Title:   "title",
+					Slug:    "title",
+					Content: "content",
+					Status:  entity.PostStatusDraft,
+					Authors: []entity.PostAuthor{
+						{AuthorID: secondAuthorID, Position: 0},
+						{AuthorID: firstAuthorID, Position: 1},
+					},
+				}).Return(&entity.Post{
+					ID:      uuid.NewString(),
+					Title:   "title",
+					Content: "content",
+				}, nil)
+			},
+			input: CreatePostOpt{
+				Title:     "title",
+				Content:   "content",
+				AuthorIDs: []string{secondAuthorID, firstAuthorID, secondAuthorID},
+			},
+		},
+		{
+			name: "Create with unknown author",
+			authorMock: func(m *mocks.AuthorStorage) {
+				m.On("GetMany", editorCtx, []string{firstAuthorID}).Return([]entity.Author{}, nil)
+			},
+			mock: func(m *mocks.PostStorage) {},
+			input: CreatePostOpt{
+				Title:     "title",
+				Content:   "content",
+				AuthorIDs: []string{firstAuthorID},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Create in unknown category",
+			categoryMock: func(m *mocks.CategoryStorage) {
+				m.On("Get", editorCtx, categoryID).Return(nil, ErrGetCategoryNotFound)
+			},
+			mock: func(m *mocks.PostStorage) {},
+			input: CreatePostOpt{
+				Title:      "title",
+				Content:    "content",
+				CategoryID: &categoryID,
 			},
+			expectErr: true,
 		},
 		{
 			name: "Create with unexpected error in storage",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Create", context.Background(), &entity.Post{
+				m.On("Create", editorCtx, &entity.Post{
+					OwnerID: &editorID,
 					Title:   "title",
+					Slug:    "title",
 					Content: "content",
+					Status:  entity.PostStatusDraft,
 				}).Return(nil, errors.New("error!"))
 			},
 			input: CreatePostOpt{
@@ -74,10 +259,22 @@ func TestPostService_Create(t *testing.T) {
 
 			postStorageMock := mocks.NewPostStorage(t)
 			tc.mock(postStorageMock)
-			storages := Storages{Post: postStorageMock}
+			tagStorageMock := mocks.NewTagStorage(t)
+			if tc.tagMock != nil {
+				tc.tagMock(tagStorageMock)
+			}
+			categoryStorageMock := mocks.NewCategoryStorage(t)
+			if tc.categoryMock != nil {
+				tc.categoryMock(categoryStorageMock)
+			}
+			authorStorageMock := mocks.NewAuthorStorage(t)
+			if tc.authorMock != nil {
+				tc.authorMock(authorStorageMock)
+			}
+			storages := Storages{Post: postStorageMock, Tag: tagStorageMock, Category: categoryStorageMock, Author: authorStorageMock}
 
 			postService := NewPostService(storages, logger)
-			actual, err := postService.Create(context.Background(), tc.input)
+			actual, err := postService.Create(editorCtx, tc.input)
 			if !tc.expectErr {
 				require.NoError(t, err, "failed to create post")
 				require.NotEmpty(t, actual, "post is empty")
@@ -98,16 +295,29 @@ func TestPostService_List(t *testing.T) {
 		os.Exit(1)
 	}
 
+	cursor := &entity.PostsCursor{Sort: entity.PostsSortCreatedAtDesc, CreatedAt: time.Now().UTC(), ID: uuid.NewString()}
+	encodedCursor, err := encodePostsCursor(cursor)
+	require.NoError(t, err, "failed to encode cursor")
+
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour)
+	categoryID := uuid.NewString()
+	authorID := uuid.NewString()
+
 	testCases := []struct {
-		name        string
-		mock        func(m *mocks.PostStorage)
-		expectedLen int
-		expectErr   bool
+		name               string
+		mock               func(m *mocks.PostStorage)
+		categoryMock       func(m *mocks.CategoryStorage)
+		authorMock         func(m *mocks.AuthorStorage)
+		input              ListPostsOpt
+		expectedLen        int
+		expectedNextCursor bool
+		expectErr          bool
 	}{
 		{
 			name: "List",
 			mock: func(m *mocks.PostStorage) {
-				m.On("List", context.Background()).
+				m.On("List", editorCtx, entity.PostsFilter{Status: entity.PostStatusPublished, Sort: entity.PostsSortCreatedAtDesc, Limit: defaultListPostsLimit + 1}).
 					Return([]entity.Post{
 						{
 							Title:   "title",
@@ -121,10 +331,196 @@ func TestPostService_List(t *testing.T) {
 			},
 			expectedLen: 2,
 		},
+		{
+			name: "List with next page",
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{Status: entity.PostStatusPublished, Sort: entity.PostsSortCreatedAtDesc, Limit: 2}).
+					Return([]entity.Post{
+						{
+							ID:        uuid.NewString(),
+							Title:     "title",
+							Content:   "content",
+							CreatedAt: time.Now(),
+						},
+						{
+							ID:        uuid.NewString(),
+							Title:     "title",
+							Content:   "content",
+							CreatedAt: time.Now(),
+						},
+					}, nil)
+			},
+			input:              ListPostsOpt{Limit: 1},
+			expectedLen:        1,
+			expectedNextCursor: true,
+		},
+		{
+			name: "List with cursor",
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{Status: entity.PostStatusPublished, Sort: entity.PostsSortCreatedAtDesc, Limit: 11, After: cursor}).
+					Return([]entity.Post{
+						{
+							Title:   "title",
+							Content: "content",
+						},
+					}, nil)
+			},
+			input:       ListPostsOpt{Limit: 10, Cursor: encodedCursor},
+			expectedLen: 1,
+		},
+		{
+			name: "List with limit above max",
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{Status: entity.PostStatusPublished, Sort: entity.PostsSortCreatedAtDesc, Limit: maxListPostsLimit + 1}).
+					Return([]entity.Post{}, nil)
+			},
+			input:       ListPostsOpt{Limit: maxListPostsLimit + 50},
+			expectedLen: 0,
+		},
+		{
+			name: "List with filters and sort",
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{
+					Status:        entity.PostStatusPublished,
+					CreatedFrom:   &hourAgo,
+					CreatedTo:     &now,
+					UpdatedSince:  &hourAgo,
+					TitleContains: "title",
+					Sort:          entity.PostsSortTitle,
+					Limit:         defaultListPostsLimit + 1,
+				}).Return([]entity.Post{
+					{
+						Title:   "title",
+						Content: "content",
+					},
+				}, nil)
+			},
+			input: ListPostsOpt{
+				CreatedFrom:   &hourAgo,
+				CreatedTo:     &now,
+				UpdatedSince:  &hourAgo,
+				TitleContains: "title",
+				Sort:          entity.PostsSortTitle,
+			},
+			expectedLen: 1,
+		},
+		{
+			name:      "List with unknown sort",
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{Sort: "content"},
+			expectErr: true,
+		},
+		{
+			name:      "List with invalid created range",
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{CreatedFrom: &now, CreatedTo: &hourAgo},
+			expectErr: true,
+		},
+		{
+			name:      "List with cursor issued for another sort",
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{Sort: entity.PostsSortTitle, Cursor: encodedCursor},
+			expectErr: true,
+		},
+		{
+			name:      "List with invalid cursor",
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{Cursor: "invalid"},
+			expectErr: true,
+		},
+		{
+			name: "List with tags",
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{
+					Status:       entity.PostStatusPublished,
+					Tags:         []string{"go", "news"},
+					TagsMatchAll: true,
+					Sort:         entity.PostsSortCreatedAtDesc,
+					Limit:        defaultListPostsLimit + 1,
+				}).Return([]entity.Post{{Title: "title", Content: "content"}}, nil)
+			},
+			input:       ListPostsOpt{Tags: []string{"Go", "news", "go"}, TagsMatchAll: true},
+			expectedLen: 1,
+		},
+		{
+			name:      "List with blank tag",
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{Tags: []string{""}},
+			expectErr: true,
+		},
+		{
+			name: "List in category",
+			categoryMock: func(m *mocks.CategoryStorage) {
+				m.On("Get", editorCtx, categoryID).Return(&entity.Category{ID: categoryID}, nil)
+			},
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{
+					Status:     entity.PostStatusPublished,
+					CategoryID: categoryID,
+					Sort:       entity.PostsSortCreatedAtDesc,
+					Limit:      defaultListPostsLimit + 1,
+				}).Return([]entity.Post{{Title: "title", Content: "content", CategoryID: &categoryID}}, nil)
+			},
+			input:       ListPostsOpt{CategoryID: categoryID},
+			expectedLen: 1,
+		},
+		{
+			name: "List in unknown category",
+			categoryMock: func(m *mocks.CategoryStorage) {
+				m.On("Get", editorCtx, categoryID).Return(nil, ErrGetCategoryNotFound)
+			},
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{CategoryID: categoryID},
+			expectErr: true,
+		},
+		{
+			name: "List in category with unexpected error in storage",
+			categoryMock: func(m *mocks.CategoryStorage) {
+				m.On("Get", editorCtx, categoryID).Return(nil, errors.New("error!"))
+			},
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{CategoryID: categoryID},
+			expectErr: true,
+		},
+		{
+			name: "List by author",
+			authorMock: func(m *mocks.AuthorStorage) {
+				m.On("Get", editorCtx, authorID).Return(&entity.Author{ID: authorID}, nil)
+			},
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{
+					Status:   entity.PostStatusPublished,
+					AuthorID: authorID,
+					Sort:     entity.PostsSortCreatedAtDesc,
+					Limit:    defaultListPostsLimit + 1,
+				}).Return([]entity.Post{{Title: "title", Content: "content"}}, nil)
+			},
+			input:       ListPostsOpt{AuthorID: authorID},
+			expectedLen: 1,
+		},
+		{
+			name: "List by unknown author",
+			authorMock: func(m *mocks.AuthorStorage) {
+				m.On("Get", editorCtx, authorID).Return(nil, ErrGetAuthorNotFound)
+			},
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{AuthorID: authorID},
+			expectErr: true,
+		},
+		{
+			name: "List by author with unexpected error in storage",
+			authorMock: func(m *mocks.AuthorStorage) {
+				m.On("Get", editorCtx, authorID).Return(nil, errors.New("error!"))
+			},
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{AuthorID: authorID},
+			expectErr: true,
+		},
 		{
 			name: "List with unexpected error in storage",
 			mock: func(m *mocks.PostStorage) {
-				m.On("List", context.Background()).Return(nil, errors.New("error!"))
+				m.On("List", editorCtx, entity.PostsFilter{Status: entity.PostStatusPublished, Sort: entity.PostsSortCreatedAtDesc, Limit: defaultListPostsLimit + 1}).
+					Return(nil, errors.New("error!"))
 			},
 			expectedLen: 0,
 			expectErr:   true,
@@ -138,22 +534,49 @@ func TestPostService_List(t *testing.T) {
 
 			postStorageMock := mocks.NewPostStorage(t)
 			tc.mock(postStorageMock)
-			storages := Storages{Post: postStorageMock}
+			categoryStorageMock := mocks.NewCategoryStorage(t)
+			if tc.categoryMock != nil {
+				tc.categoryMock(categoryStorageMock)
+			}
+			authorStorageMock := mocks.NewAuthorStorage(t)
+			if tc.authorMock != nil {
+				tc.authorMock(authorStorageMock)
+			}
+			storages := Storages{Post: postStorageMock, Category: categoryStorageMock, Author: authorStorageMock}
 
 			postService := NewPostService(storages, logger)
-			actual, err := postService.List(context.Background())
+			actual, err := postService.List(editorCtx, tc.input)
 			if !tc.expectErr {
 				require.NoError(t, err, "failed to list posts")
-				require.Equal(t, tc.expectedLen, len(actual), "len is not equal")
+				require.Equal(t, tc.expectedLen, len(actual.Posts), "len is not equal")
+				require.Equal(t, tc.expectedNextCursor, actual.NextCursor != "", "next cursor presence is not equal")
 			} else {
 				require.Error(t, err, "no error")
-				require.Empty(t, actual, "slice is not empty")
+				require.Nil(t, actual, "result is not nil")
 			}
 		})
 	}
 }
 
-func TestPostService_Get(t *testing.T) {
+func TestPostService_PostsCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor := &entity.PostsCursor{Sort: entity.PostsSortTitle, Title: "title", CreatedAt: time.Now().UTC(), ID: uuid.NewString()}
+	encoded, err := encodePostsCursor(cursor)
+	require.NoError(t, err, "failed to encode cursor")
+
+	decoded, err := decodePostsCursor(encoded)
+	require.NoError(t, err, "failed to decode cursor")
+	require.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt), "createdAt is not equal")
+	require.Equal(t, cursor.ID, decoded.ID, "IDs are not equal")
+	require.Equal(t, cursor.Sort, decoded.Sort, "sorts are not equal")
+	require.Equal(t, cursor.Title, decoded.Title, "titles are not equal")
+
+	_, err = decodePostsCursor("bm90LWpzb24")
+	require.Error(t, err, "no error for invalid cursor")
+}
+
+func TestPostService_Search(t *testing.T) {
 	t.Parallel()
 
 	logger, err := logging.NewZapLogger()
@@ -162,43 +585,61 @@ func TestPostService_Get(t *testing.T) {
 		os.Exit(1)
 	}
 
-	postID := uuid.NewString()
-	wrongID := uuid.NewString()
-	post := &entity.Post{
-		ID:      postID,
-		Title:   "title",
-		Content: "content",
-	}
-
 	testCases := []struct {
-		name      string
-		mock      func(m *mocks.PostStorage)
-		inputID   string
-		expected  *entity.Post
-		expectErr bool
+		name        string
+		mock        func(m *mocks.PostStorage)
+		input       SearchPostsOpt
+		expectedLen int
+		expectErr   bool
 	}{
 		{
-			name: "Get",
+			name: "Search",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Get", context.Background(), postID).Return(post, nil)
+				m.On("Search", editorCtx, entity.PostsSearchFilter{
+					Status: entity.PostStatusPublished,
+					Query:  "news",
+					Limit:  defaultListPostsLimit,
+				}).Return([]entity.PostSearchResult{
+					{
+						Post:             entity.Post{Title: "news", Content: "content"},
+						Rank:             0.5,
+						TitleHighlight:   "<mark>news</mark>",
+						ContentHighlight: "content",
+					},
+				}, nil)
 			},
-			inputID:  postID,
-			expected: post,
+			input:       SearchPostsOpt{Query: " news "},
+			expectedLen: 1,
 		},
 		{
-			name: "Get with wrong ID",
+			name: "Search with limit above max and offset",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Get", context.Background(), wrongID).Return(nil, ErrGetPostNotFound)
+				m.On("Search", editorCtx, entity.PostsSearchFilter{
+					Status: entity.PostStatusPublished,
+					Query:  "news",
+					Limit:  maxListPostsLimit,
+					Offset: 10,
+				}).Return([]entity.PostSearchResult{}, nil)
 			},
-			inputID:   wrongID,
+			input:       SearchPostsOpt{Query: "news", Limit: maxListPostsLimit + 1, Offset: 10},
+			expectedLen: 0,
+		},
+		{
+			name:      "Search with empty query",
+			mock:      func(m *mocks.PostStorage) {},
+			input:     SearchPostsOpt{Query: "  "},
 			expectErr: true,
 		},
 		{
-			name: "Get with unexpected error in storage",
+			name: "Search with unexpected error in storage",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Get", context.Background(), postID).Return(nil, errors.New("error!"))
+				m.On("Search", editorCtx, entity.PostsSearchFilter{
+					Status: entity.PostStatusPublished,
+					Query:  "news",
+					Limit:  defaultListPostsLimit,
+				}).Return(nil, errors.New("error!"))
 			},
-			inputID:   postID,
+			input:     SearchPostsOpt{Query: "news"},
 			expectErr: true,
 		},
 	}
@@ -213,19 +654,19 @@ func TestPostService_Get(t *testing.T) {
 			storages := Storages{Post: postStorageMock}
 
 			postService := NewPostService(storages, logger)
-			actual, err := postService.Get(context.Background(), tc.inputID)
+			actual, err := postService.Search(editorCtx, tc.input)
 			if !tc.expectErr {
-				require.NoError(t, err, "failed to get post")
-				require.Equal(t, tc.expected.ID, actual.ID, "IDs are not equal")
+				require.NoError(t, err, "failed to search posts")
+				require.Equal(t, tc.expectedLen, len(actual), "len is not equal")
 			} else {
 				require.Error(t, err, "no error")
-				require.Nil(t, actual, "post is not nil")
+				require.Empty(t, actual, "slice is not empty")
 			}
 		})
 	}
 }
 
-func TestPostService_Update(t *testing.T) {
+func TestPostService_Get(t *testing.T) {
 	t.Parallel()
 
 	logger, err := logging.NewZapLogger()
@@ -235,58 +676,41 @@ func TestPostService_Update(t *testing.T) {
 	}
 
 	postID := uuid.NewString()
-	invalidID := "invalid"
-	input := UpdatePostOpt{
-		Title:   "updated title",
-		Content: "updated content",
-	}
-	updatedPost := &entity.Post{
+	wrongID := uuid.NewString()
+	post := &entity.Post{
 		ID:      postID,
-		Title:   "updated title",
-		Content: "updated content",
+		Title:   "title",
+		Content: "content",
 	}
 
 	testCases := []struct {
 		name      string
 		mock      func(m *mocks.PostStorage)
-		input     UpdatePostOpt
 		inputID   string
 		expected  *entity.Post
 		expectErr bool
 	}{
 		{
-			name: "Update",
+			name: "Get",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Update", context.Background(), postID, &entity.Post{
-					Title:   "updated title",
-					Content: "updated content",
-				}).Return(updatedPost, nil)
+				m.On("Get", editorCtx, postID).Return(post, nil)
 			},
-			input:    input,
-			expected: updatedPost,
 			inputID:  postID,
+			expected: post,
 		},
 		{
-			name: "Update with invalid ID",
+			name: "Get with wrong ID",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Update", context.Background(), invalidID, &entity.Post{
-					Title:   "updated title",
-					Content: "updated content",
-				}).Return(nil, errors.New("invalid id"))
+				m.On("Get", editorCtx, wrongID).Return(nil, ErrGetPostNotFound)
 			},
-			input:     input,
-			inputID:   invalidID,
+			inputID:   wrongID,
 			expectErr: true,
 		},
 		{
-			name: "Update with unexpected error in storage",
+			name: "Get with unexpected error in storage",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Update", context.Background(), postID, &entity.Post{
-					Title:   "updated title",
-					Content: "updated content",
-				}).Return(nil, errors.New("error!"))
+				m.On("Get", editorCtx, postID).Return(nil, errors.New("error!"))
 			},
-			input:     input,
 			inputID:   postID,
 			expectErr: true,
 		},
@@ -302,12 +726,10 @@ func TestPostService_Update(t *testing.T) {
 			storages := Storages{Post: postStorageMock}
 
 			postService := NewPostService(storages, logger)
-			actual, err := postService.Update(context.Background(), tc.inputID, tc.input)
+			actual, err := postService.Get(editorCtx, tc.inputID)
 			if !tc.expectErr {
-				require.NoError(t, err, "failed to update post")
+				require.NoError(t, err, "failed to get post")
 				require.Equal(t, tc.expected.ID, actual.ID, "IDs are not equal")
-				require.Equal(t, tc.expected.Title, actual.Title, "titles are not equal")
-				require.Equal(t, tc.expected.Content, actual.Content, "content is not equal")
 			} else {
 				require.Error(t, err, "no error")
 				require.Nil(t, actual, "post is not nil")
@@ -316,7 +738,7 @@ func TestPostService_Update(t *testing.T) {
 	}
 }
 
-func TestPostService_Delete(t *testing.T) {
+func TestPostService_GetBySlug(t *testing.T) {
 	t.Parallel()
 
 	logger, err := logging.NewZapLogger()
@@ -325,25 +747,408 @@ func TestPostService_Delete(t *testing.T) {
 		os.Exit(1)
 	}
 
-	postID := uuid.NewString()
+	post := &entity.Post{ID: uuid.NewString(), Title: "new title", Slug: "new-title"}
 
 	testCases := []struct {
 		name      string
 		mock      func(m *mocks.PostStorage)
-		inputID   string
+		input     string
 		expectErr bool
 	}{
 		{
-			name: "Delete",
+			name: "GetBySlug with current slug",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Delete", context.Background(), postID).Return(nil)
+				m.On("GetBySlug", editorCtx, "new-title").Return(post, nil)
+			},
+			input: "new-title",
+		},
+		{
+			name: "GetBySlug with previous slug",
+			mock: func(m *mocks.PostStorage) {
+				m.On("GetBySlug", editorCtx, "old-title").Return(post, nil)
+			},
+			input: "old-title",
+		},
+		{
+			name: "GetBySlug with unknown slug",
+			mock: func(m *mocks.PostStorage) {
+				m.On("GetBySlug", editorCtx, "unknown").Return(nil, ErrGetPostNotFound)
+			},
+			input:     "unknown",
+			expectErr: true,
+		},
+		{
+			name: "GetBySlug with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("GetBySlug", editorCtx, "new-title").Return(nil, errors.New("error!"))
+			},
+			input:     "new-title",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.GetBySlug(editorCtx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to get post")
+				require.Equal(t, post.Slug, actual.Slug, "slugs are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "post is not nil")
+			}
+		})
+	}
+}
+
+func TestPostService_Update(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	invalidID := "invalid"
+	title := "updated title"
+	titleSlug := "updated-title"
+	content := "updated content"
+	emptyContent := ""
+	input := UpdatePostOpt{
+		Title:   &title,
+		Content: &content,
+	}
+	updatedPost := &entity.Post{
+		ID:      postID,
+		Title:   "updated title",
+		Content: "updated content",
+	}
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	tags := []entity.Tag{{ID: uuid.NewString(), Name: "go"}}
+	noTags := []entity.Tag{}
+	authorID := uuid.NewString()
+	authors := []entity.PostAuthor{{AuthorID: authorID, Position: 0}}
+	noAuthors := []entity.PostAuthor{}
+
+	testCases := []struct {
+		name       string
+		mock       func(m *mocks.PostStorage)
+		tagMock    func(m *mocks.TagStorage)
+		authorMock func(m *mocks.AuthorStorage)
+		input      UpdatePostOpt
+		inputID    string
+		expected   *entity.Post
+		expectErr  bool
+	}{
+		{
+			name: "Update",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:   &title,
+					Slug:    &titleSlug,
+					Content: &content,
+				}).Return(updatedPost, nil)
+			},
+			input:    input,
+			expected: updatedPost,
+			inputID:  postID,
+		},
+		{
+			name: "Update only title",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title: &title,
+					Slug:  &titleSlug,
+				}).Return(updatedPost, nil)
+			},
+			input:    UpdatePostOpt{Title: &title},
+			expected: updatedPost,
+			inputID:  postID,
+		},
+		{
+			name: "Update clearing content",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Content: &emptyContent,
+				}).Return(&entity.Post{ID: postID, Title: "title"}, nil)
+			},
+			input:    UpdatePostOpt{Content: &emptyContent},
+			expected: &entity.Post{ID: postID, Title: "title"},
+			inputID:  postID,
+		},
+		{
+			name: "Update cancelling scheduled publishing",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					ClearPublishAt: true,
+				}).Return(updatedPost, nil)
+			},
+			input:    UpdatePostOpt{ClearPublishAt: true},
+			expected: updatedPost,
+			inputID:  postID,
+		},
+		{
+			name: "Update with version",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:   &title,
+					Slug:    &titleSlug,
+					Version: 2,
+				}).Return(updatedPost, nil)
+			},
+			input:    UpdatePostOpt{Title: &title, Version: 2},
+			expected: updatedPost,
+			inputID:  postID,
+		},
+		{
+			name: "Update with version mismatch",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:   &title,
+					Slug:    &titleSlug,
+					Version: 2,
+				}).Return(nil, ErrPostVersionMismatch)
+			},
+			input:     UpdatePostOpt{Title: &title, Version: 2},
+			inputID:   postID,
+			expectErr: true,
+		},
+		{
+			name: "Update scheduling draft",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{
+					ID:     postID,
+					Status: entity.PostStatusDraft,
+				}, nil)
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:     &title,
+					Slug:      &titleSlug,
+					Content:   &content,
+					PublishAt: &tomorrow,
+				}).Return(updatedPost, nil)
+			},
+			input: UpdatePostOpt{
+				Title:     &title,
+				Content:   &content,
+				PublishAt: &tomorrow,
+			},
+			expected: updatedPost,
+			inputID:  postID,
+		},
+		{
+			name: "Update scheduling published post",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{
+					ID:     postID,
+					Status: entity.PostStatusPublished,
+				}, nil)
+			},
+			input: UpdatePostOpt{
+				Title:     &title,
+				Content:   &content,
+				PublishAt: &tomorrow,
+			},
+			inputID:   postID,
+			expectErr: true,
+		},
+		{
+			name: "Update scheduling in the past",
+			mock: func(m *mocks.PostStorage) {},
+			input: UpdatePostOpt{
+				Title:     &title,
+				Content:   &content,
+				PublishAt: &yesterday,
+			},
+			inputID:   postID,
+			expectErr: true,
+		},
+		{
+			name: "Update tags",
+			tagMock: func(m *mocks.TagStorage) {
+				m.On("Ensure", editorCtx, []string{"go"}).Return(tags, nil)
+			},
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{Tags: &tags}).Return(updatedPost, nil)
+			},
+			input:    UpdatePostOpt{Tags: []string{"Go"}},
+			inputID:  postID,
+			expected: updatedPost,
+		},
+		{
+			name: "Update removing tags",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{Tags: &noTags}).Return(updatedPost, nil)
+			},
+			input:    UpdatePostOpt{Tags: []string{}},
+			inputID:  postID,
+			expected: updatedPost,
+		},
+		{
+			name: "Update authors",
+			authorMock: func(m *mocks.AuthorStorage) {
+				m.On("GetMany", editorCtx, []string{authorID}).Return([]entity.Author{{ID: authorID}}, nil)
+			},
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{Authors: &authors}).Return(updatedPost, nil)
+			},
+			input:    UpdatePostOpt{AuthorIDs: []string{authorID}},
+			inputID:  postID,
+			expected: updatedPost,
+		},
+		{
+			name: "Update removing authors",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{Authors: &noAuthors}).Return(updatedPost, nil)
+			},
+			input:    UpdatePostOpt{AuthorIDs: []string{}},
+			inputID:  postID,
+			expected: updatedPost,
+		},
+		{
+			name: "Update with unknown author",
+			authorMock: func(m *mocks.AuthorStorage) {
+				m.On("GetMany", editorCtx, []string{authorID}).Return([]entity.Author{}, nil)
+			},
+			mock:      func(m *mocks.PostStorage) {},
+			input:     UpdatePostOpt{AuthorIDs: []string{authorID}},
+			inputID:   postID,
+			expectErr: true,
+		},
+		{
+			name: "Update with unexpected error in tag storage",
+			tagMock: func(m *mocks.TagStorage) {
+				m.On("Ensure", editorCtx, []string{"go"}).Return(nil, errors.New("error!"))
+			},
+			mock:      func(m *mocks.PostStorage) {},
+			input:     UpdatePostOpt{Tags: []string{"go"}},
+			inputID:   postID,
+			expectErr: true,
+		},
+		{
+			name: "Update with invalid ID",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, invalidID, entity.PostUpdate{
+					Title:   &title,
+					Slug:    &titleSlug,
+					Content: &content,
+				}).Return(nil, errors.New("invalid id"))
+			},
+			input:     input,
+			inputID:   invalidID,
+			expectErr: true,
+		},
+		{
+			name: "Update with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:   &title,
+					Slug:    &titleSlug,
+					Content: &content,
+				}).Return(nil, errors.New("error!"))
+			},
+			input:     input,
+			inputID:   postID,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			tagStorageMock := mocks.NewTagStorage(t)
+			if tc.tagMock != nil {
+				tc.tagMock(tagStorageMock)
+			}
+			authorStorageMock := mocks.NewAuthorStorage(t)
+			if tc.authorMock != nil {
+				tc.authorMock(authorStorageMock)
+			}
+			storages := Storages{Post: postStorageMock, Tag: tagStorageMock, Author: authorStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.Update(editorCtx, tc.inputID, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to update post")
+				require.Equal(t, tc.expected.ID, actual.ID, "IDs are not equal")
+				require.Equal(t, tc.expected.Title, actual.Title, "titles are not equal")
+				require.Equal(t, tc.expected.Content, actual.Content, "content is not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "post is not nil")
+			}
+		})
+	}
+}
+
+func TestPostService_Delete(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+
+	testCases := []struct {
+		name         string
+		mock         func(m *mocks.PostStorage)
+		inputID      string
+		inputVersion int
+		expectErr    bool
+	}{
+		{
+			name: "Delete",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Delete", editorCtx, postID, 0).Return(nil)
 			},
 			inputID: postID,
 		},
+		{
+			name: "Delete with version",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Delete", editorCtx, postID, 2).Return(nil)
+			},
+			inputID:      postID,
+			inputVersion: 2,
+		},
+		{
+			name: "Delete with version mismatch",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Delete", editorCtx, postID, 2).Return(ErrPostVersionMismatch)
+			},
+			inputID:      postID,
+			inputVersion: 2,
+			expectErr:    true,
+		},
+		{
+			name: "Delete with wrong ID",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Delete", editorCtx, postID, 0).Return(ErrGetPostNotFound)
+			},
+			inputID:   postID,
+			expectErr: true,
+		},
 		{
 			name: "Delete with unexpected error in storage",
 			mock: func(m *mocks.PostStorage) {
-				m.On("Delete", context.Background(), postID).Return(errors.New("error!"))
+				m.On("Delete", editorCtx, postID, 0).Return(errors.New("error!"))
 			},
 			inputID:   postID,
 			expectErr: true,
@@ -360,7 +1165,7 @@ func TestPostService_Delete(t *testing.T) {
 			storages := Storages{Post: postStorageMock}
 
 			postService := NewPostService(storages, logger)
-			err = postService.Delete(context.Background(), tc.inputID)
+			err = postService.Delete(editorCtx, tc.inputID, tc.inputVersion)
 			if !tc.expectErr {
 				require.NoError(t, err, "failed to delete post")
 			} else {
@@ -369,3 +1174,955 @@ func TestPostService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestPostService_Batch(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	title := "new title"
+	titleSlug := "new-title"
+
+	createOperation := PostOperation{Type: PostOperationCreate, Create: CreatePostOpt{Title: "title", Content: "content"}}
+	updateOperation := PostOperation{Type: PostOperationUpdate, ID: postID, Update: UpdatePostOpt{Title: &title}}
+	deleteOperation := PostOperation{Type: PostOperationDelete, ID: postID, Version: 2}
+
+	mockCreate := func(m *mocks.PostStorage) {
+		m.On("Create", editorCtx, &entity.Post{
+			OwnerID: &editorID,
+			Title:   "title",
+			Slug:    "title",
+			Content: "content",
+			Status:  entity.PostStatusDraft,
+		}).Return(&entity.Post{ID: postID, Title: "title", Content: "content"}, nil)
+	}
+
+	testCases := []struct {
+		name           string
+		mock           func(m *mocks.PostStorage)
+		transactionErr error
+		input          BatchPostsOpt
+		expected       []PostOperationResult
+		expectErr      bool
+		// expectedOperation is the index of the failed operation in the error metadata
+		expectedOperation interface{}
+	}{
+		{
+			name: "Batch in best-effort mode",
+			mock: func(m *mocks.PostStorage) {
+				mockCreate(m)
+				m.On("Update", editorCtx, postID, entity.PostUpdate{Title: &title, Slug: &titleSlug}).Return(nil, ErrGetPostNotFound)
+				m.On("Delete", editorCtx, postID, 2).Return(nil)
+			},
+			input: BatchPostsOpt{
+				Operations: []PostOperation{createOperation, updateOperation, deleteOperation, {Type: "move"}},
+			},
+			expected: []PostOperationResult{
+				{Post: &entity.Post{ID: postID, Title: "title", Content: "content"}},
+				{Err: ErrGetPostNotFound},
+				{},
+				{Err: ErrUnknownPostOperation},
+			},
+		},
+		{
+			name: "Batch in atomic mode",
+			mock: func(m *mocks.PostStorage) {
+				mockCreate(m)
+				m.On("Delete", editorCtx, postID, 2).Return(nil)
+			},
+			input: BatchPostsOpt{
+				Operations: []PostOperation{createOperation, deleteOperation},
+				Atomic:     true,
+			},
+			expected: []PostOperationResult{
+				{Post: &entity.Post{ID: postID, Title: "title", Content: "content"}},
+				{},
+			},
+		},
+		{
+			name: "Batch in atomic mode with failed operation",
+			mock: func(m *mocks.PostStorage) {
+				mockCreate(m)
+				m.On("Update", editorCtx, postID, entity.PostUpdate{Title: &title, Slug: &titleSlug}).Return(nil, ErrGetPostNotFound)
+			},
+			input: BatchPostsOpt{
+				Operations: []PostOperation{createOperation, updateOperation, deleteOperation},
+				Atomic:     true,
+			},
+			expectErr:         true,
+			expectedOperation: 1,
+		},
+		{
+			name: "Batch in atomic mode with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Delete", editorCtx, postID, 2).Return(errors.New("error!"))
+			},
+			input: BatchPostsOpt{
+				Operations: []PostOperation{deleteOperation, createOperation},
+				Atomic:     true,
+			},
+			expectErr: true,
+		},
+		{
+			name:           "Batch in atomic mode with failed transaction",
+			mock:           func(m *mocks.PostStorage) {},
+			transactionErr: errors.New("error!"),
+			input: BatchPostsOpt{
+				Operations: []PostOperation{createOperation},
+				Atomic:     true,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+			storages.Transaction = func(ctx context.Context, fn func(storages Storages) error) error {
+				if tc.transactionErr != nil {
+					return tc.transactionErr
+				}
+
+				return fn(storages)
+			}
+
+			postService := NewPostService(storages, logger)
+			results, err := postService.Batch(editorCtx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to apply post operations")
+				require.Equal(t, tc.expected, results, "results are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Equal(t, tc.expectedOperation, errs.Metadata(err)["operation"], "failed operation is not equal")
+			}
+		})
+	}
+}
+
+func TestPostService_ChangeStatus(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	publishedAt := time.Now()
+	postWithStatus := func(status entity.PostStatus, publishedAt *time.Time) *entity.Post {
+		return &entity.Post{ID: postID, Title: "title", Content: "content", Status: status, PublishedAt: publishedAt}
+	}
+
+	testCases := []struct {
+		name           string
+		mock           func(m *mocks.PostStorage)
+		change         func(s PostService) (*entity.Post, error)
+		expectedStatus entity.PostStatus
+		expectedErr    error
+		expectErr      bool
+	}{
+		{
+			name: "Publish draft",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusDraft, nil), nil)
+				m.On("UpdateStatus", editorCtx, postID, entity.PostStatusDraft, entity.PostStatusPublished, mock.AnythingOfType("*time.Time"), 0).
+					Return(postWithStatus(entity.PostStatusPublished, &publishedAt), nil)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Publish(editorCtx, postID, 0)
+			},
+			expectedStatus: entity.PostStatusPublished,
+		},
+		{
+			name: "Publish published",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusPublished, &publishedAt), nil)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Publish(editorCtx, postID, 0)
+			},
+			expectedErr: ErrInvalidPostStatusTransition,
+			expectErr:   true,
+		},
+		{
+			name: "Unpublish published",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusPublished, &publishedAt), nil)
+				m.On("UpdateStatus", editorCtx, postID, entity.PostStatusPublished, entity.PostStatusDraft, (*time.Time)(nil), 0).
+					Return(postWithStatus(entity.PostStatusDraft, nil), nil)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Unpublish(editorCtx, postID, 0)
+			},
+			expectedStatus: entity.PostStatusDraft,
+		},
+		{
+			name: "Archive published",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusPublished, &publishedAt), nil)
+				m.On("UpdateStatus", editorCtx, postID, entity.PostStatusPublished, entity.PostStatusArchived, &publishedAt, 0).
+					Return(postWithStatus(entity.PostStatusArchived, &publishedAt), nil)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Archive(editorCtx, postID, 0)
+			},
+			expectedStatus: entity.PostStatusArchived,
+		},
+		{
+			name: "Archive draft",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusDraft, nil), nil)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Archive(editorCtx, postID, 0)
+			},
+			expectedErr: ErrInvalidPostStatusTransition,
+			expectErr:   true,
+		},
+		{
+			name: "Unpublish archived",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusArchived, &publishedAt), nil)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Unpublish(editorCtx, postID, 0)
+			},
+			expectedErr: ErrInvalidPostStatusTransition,
+			expectErr:   true,
+		},
+		{
+			name: "Publish with status changed concurrently",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusDraft, nil), nil)
+				m.On("UpdateStatus", editorCtx, postID, entity.PostStatusDraft, entity.PostStatusPublished, mock.AnythingOfType("*time.Time"), 0).
+					Return(nil, ErrUpdatePostStatusConflict)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Publish(editorCtx, postID, 0)
+			},
+			expectedErr: ErrUpdatePostStatusConflict,
+			expectErr:   true,
+		},
+		{
+			name: "Publish with stale version",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusDraft, Version: 2}, nil)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Publish(editorCtx, postID, 1)
+			},
+			expectedErr: ErrPostVersionMismatch,
+			expectErr:   true,
+		},
+		{
+			name: "Publish with version changed concurrently",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID, Status: entity.PostStatusDraft, Version: 1}, nil)
+				m.On("UpdateStatus", editorCtx, postID, entity.PostStatusDraft, entity.PostStatusPublished, mock.AnythingOfType("*time.Time"), 1).
+					Return(nil, ErrPostVersionMismatch)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Publish(editorCtx, postID, 1)
+			},
+			expectedErr: ErrPostVersionMismatch,
+			expectErr:   true,
+		},
+		{
+			name: "Publish with wrong ID",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(nil, ErrGetPostNotFound)
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Publish(editorCtx, postID, 0)
+			},
+			expectedErr: ErrGetPostNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "Publish with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(postWithStatus(entity.PostStatusDraft, nil), nil)
+				m.On("UpdateStatus", editorCtx, postID, entity.PostStatusDraft, entity.PostStatusPublished, mock.AnythingOfType("*time.Time"), 0).
+					Return(nil, errors.New("error!"))
+			},
+			change: func(s PostService) (*entity.Post, error) {
+				return s.Publish(editorCtx, postID, 0)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := tc.change(postService)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to change post status")
+				require.Equal(t, tc.expectedStatus, actual.Status, "statuses are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "post is not nil")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+			}
+		})
+	}
+}
+
+func TestPostService_PublishScheduled(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.PostStorage)
+		expectedLen int
+		expectErr   bool
+	}{
+		{
+			name: "PublishScheduled",
+			mock: func(m *mocks.PostStorage) {
+				m.On("PublishDue", systemCtx, mock.AnythingOfType("time.Time"), publishScheduledBatchSize).
+					Return([]entity.Post{
+						{
+							Title:   "title",
+							Content: "content",
+							Status:  entity.PostStatusPublished,
+						},
+					}, nil)
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "PublishScheduled with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("PublishDue", systemCtx, mock.AnythingOfType("time.Time"), publishScheduledBatchSize).
+					Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.PublishScheduled(systemCtx)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to publish scheduled posts")
+				require.Equal(t, tc.expectedLen, len(actual), "len is not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Empty(t, actual, "slice is not empty")
+			}
+		})
+	}
+}
+
+func TestPostService_BackfillSlugs(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	firstID := uuid.NewString()
+	secondID := uuid.NewString()
+	posts := []entity.Post{{ID: firstID, Title: "Hello, World!"}, {ID: secondID, Title: "Привіт"}}
+
+	testCases := []struct {
+		name          string
+		mock          func(m *mocks.PostStorage)
+		expectedCount int
+		expectErr     bool
+	}{
+		{
+			name: "BackfillSlugs",
+			mock: func(m *mocks.PostStorage) {
+				m.On("ListWithoutSlug", systemCtx, backfillSlugsBatchSize).Return(posts, nil).Once()
+				m.On("SetSlug", systemCtx, firstID, "hello-world").Return(nil)
+				m.On("SetSlug", systemCtx, secondID, "pryvit").Return(nil)
+				m.On("ListWithoutSlug", systemCtx, backfillSlugsBatchSize).Return([]entity.Post{}, nil).Once()
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "BackfillSlugs with slug taken by another post",
+			mock: func(m *mocks.PostStorage) {
+				m.On("ListWithoutSlug", systemCtx, backfillSlugsBatchSize).Return(posts[:1], nil).Once()
+				m.On("SetSlug", systemCtx, firstID, "hello-world").Return(ErrPostSlugExists).Once()
+				m.On("SetSlug", systemCtx, firstID, mock.MatchedBy(func(slug string) bool {
+					return strings.HasPrefix(slug, "hello-world-")
+				})).Return(nil).Once()
+				m.On("ListWithoutSlug", systemCtx, backfillSlugsBatchSize).Return([]entity.Post{}, nil).Once()
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "BackfillSlugs without posts to backfill",
+			mock: func(m *mocks.PostStorage) {
+				m.On("ListWithoutSlug", systemCtx, backfillSlugsBatchSize).Return([]entity.Post{}, nil)
+			},
+		},
+		{
+			name: "BackfillSlugs with unexpected error in storage on listing",
+			mock: func(m *mocks.PostStorage) {
+				m.On("ListWithoutSlug", systemCtx, backfillSlugsBatchSize).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+		{
+			name: "BackfillSlugs with unexpected error in storage on setting",
+			mock: func(m *mocks.PostStorage) {
+				m.On("ListWithoutSlug", systemCtx, backfillSlugsBatchSize).Return(posts[:1], nil)
+				m.On("SetSlug", systemCtx, firstID, "hello-world").Return(errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.BackfillSlugs(systemCtx)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to backfill post slugs")
+				require.Equal(t, tc.expectedCount, actual, "counts are not equal")
+			} else {
+				require.Error(t, err, "no error")
+			}
+		})
+	}
+}
+
+func TestPostService_ListRevisions(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.PostStorage)
+		expectedLen int
+		expectErr   bool
+	}{
+		{
+			name: "ListRevisions",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID}, nil)
+				m.On("ListRevisions", editorCtx, postID).Return([]entity.PostRevision{
+					{PostID: postID, Number: 1, Title: "title", Content: "content"},
+					{PostID: postID, Number: 2, Title: "title updated", Content: "content"},
+				}, nil)
+			},
+			expectedLen: 2,
+		},
+		{
+			name: "ListRevisions with wrong ID",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(nil, ErrGetPostNotFound)
+			},
+			expectErr: true,
+		},
+		{
+			name: "ListRevisions with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID}, nil)
+				m.On("ListRevisions", editorCtx, postID).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.ListRevisions(editorCtx, postID)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list post revisions")
+				require.Equal(t, tc.expectedLen, len(actual), "len is not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Empty(t, actual, "slice is not empty")
+			}
+		})
+	}
+}
+
+func TestPostService_DiffRevisions(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	firstRevision := &entity.PostRevision{PostID: postID, Number: 1, Title: "title", Content: "line 1\nline 2\n"}
+	secondRevision := &entity.PostRevision{PostID: postID, Number: 2, Title: "title", Content: "line 1\nline 2 updated\n"}
+
+	testCases := []struct {
+		name                string
+		mock                func(m *mocks.PostStorage)
+		expectedTitleDiff   string
+		expectedContentDiff string
+		expectErr           bool
+	}{
+		{
+			name: "DiffRevisions",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID}, nil)
+				m.On("GetRevision", editorCtx, postID, 1).Return(firstRevision, nil)
+				m.On("GetRevision", editorCtx, postID, 2).Return(secondRevision, nil)
+			},
+			expectedTitleDiff: "",
+			expectedContentDiff: "--- content@1\n" +
+				"+++ content@2\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				" line 1\n" +
+				"-line 2\n" +
+				"+line 2 updated\n",
+		},
+		{
+			name: "DiffRevisions with wrong revision",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID}, nil)
+				m.On("GetRevision", editorCtx, postID, 1).Return(firstRevision, nil)
+				m.On("GetRevision", editorCtx, postID, 2).Return(nil, ErrGetPostRevisionNotFound)
+			},
+			expectErr: true,
+		},
+		{
+			name: "DiffRevisions with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Get", editorCtx, postID).Return(&entity.Post{ID: postID}, nil)
+				m.On("GetRevision", editorCtx, postID, 1).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.DiffRevisions(editorCtx, postID, 1, 2)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to diff post revisions")
+				require.Equal(t, tc.expectedTitleDiff, actual.TitleDiff, "title diffs are not equal")
+				require.Equal(t, tc.expectedContentDiff, actual.ContentDiff, "content diffs are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "diff is not nil")
+			}
+		})
+	}
+}
+
+func TestPostService_RestoreRevision(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	revision := &entity.PostRevision{PostID: postID, Number: 1, Title: "title", Content: "content"}
+	revisionSlug := "title"
+	restoredPost := &entity.Post{ID: postID, Title: "title", Content: "content"}
+
+	testCases := []struct {
+		name         string
+		mock         func(m *mocks.PostStorage)
+		inputVersion int
+		expected     *entity.Post
+		expectErr    bool
+	}{
+		{
+			name: "RestoreRevision",
+			mock: func(m *mocks.PostStorage) {
+				m.On("GetRevision", editorCtx, postID, 1).Return(revision, nil)
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:   &revision.Title,
+					Slug:    &revisionSlug,
+					Content: &revision.Content,
+				}).Return(restoredPost, nil)
+			},
+			expected: restoredPost,
+		},
+		{
+			name: "RestoreRevision of changed post",
+			mock: func(m *mocks.PostStorage) {
+				m.On("GetRevision", editorCtx, postID, 1).Return(revision, nil)
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:   &revision.Title,
+					Slug:    &revisionSlug,
+					Content: &revision.Content,
+					Version: 2,
+				}).Return(nil, ErrPostVersionMismatch)
+			},
+			inputVersion: 2,
+			expectErr:    true,
+		},
+		{
+			name: "RestoreRevision with wrong revision",
+			mock: func(m *mocks.PostStorage) {
+				m.On("GetRevision", editorCtx, postID, 1).Return(nil, ErrGetPostRevisionNotFound)
+			},
+			expectErr: true,
+		},
+		{
+			name: "RestoreRevision with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("GetRevision", editorCtx, postID, 1).Return(revision, nil)
+				m.On("Update", editorCtx, postID, entity.PostUpdate{
+					Title:   &revision.Title,
+					Slug:    &revisionSlug,
+					Content: &revision.Content,
+				}).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.RestoreRevision(editorCtx, postID, 1, tc.inputVersion)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to restore post revision")
+				require.Equal(t, tc.expected.Title, actual.Title, "titles are not equal")
+				require.Equal(t, tc.expected.Content, actual.Content, "content is not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "post is not nil")
+			}
+		})
+	}
+}
+
+func TestPostService_ListTrash(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	testCases := []struct {
+		name        string
+		mock        func(m *mocks.PostStorage)
+		input       ListPostsOpt
+		expectedLen int
+		expectErr   bool
+	}{
+		{
+			name: "ListTrash",
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{
+					OnlyDeleted: true,
+					Sort:        entity.PostsSortCreatedAtDesc,
+					Limit:       defaultListPostsLimit + 1,
+				}).Return([]entity.Post{
+					{
+						Title:   "title",
+						Content: "content",
+					},
+				}, nil)
+			},
+			expectedLen: 1,
+		},
+		{
+			name:      "ListTrash with unknown sort",
+			mock:      func(m *mocks.PostStorage) {},
+			input:     ListPostsOpt{Sort: "content"},
+			expectErr: true,
+		},
+		{
+			name: "ListTrash with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("List", editorCtx, entity.PostsFilter{
+					OnlyDeleted: true,
+					Sort:        entity.PostsSortCreatedAtDesc,
+					Limit:       defaultListPostsLimit + 1,
+				}).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.ListTrash(editorCtx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list deleted posts")
+				require.Equal(t, tc.expectedLen, len(actual.Posts), "len is not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "result is not nil")
+			}
+		})
+	}
+}
+
+func TestPostService_Restore(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+	post := &entity.Post{ID: postID, Title: "title", Content: "content"}
+
+	testCases := []struct {
+		name         string
+		mock         func(m *mocks.PostStorage)
+		inputVersion int
+		expectErr    bool
+	}{
+		{
+			name: "Restore",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Restore", editorCtx, postID, 0).Return(post, nil)
+			},
+		},
+		{
+			name: "Restore not deleted post",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Restore", editorCtx, postID, 0).Return(nil, ErrGetDeletedPostNotFound)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Restore with stale version",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Restore", editorCtx, postID, 1).Return(nil, ErrPostVersionMismatch)
+			},
+			inputVersion: 1,
+			expectErr:    true,
+		},
+		{
+			name: "Restore with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Restore", editorCtx, postID, 0).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.Restore(editorCtx, postID, tc.inputVersion)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to restore post")
+				require.Equal(t, postID, actual.ID, "IDs are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "post is not nil")
+			}
+		})
+	}
+}
+
+func TestPostService_Purge(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	postID := uuid.NewString()
+
+	testCases := []struct {
+		name      string
+		mock      func(m *mocks.PostStorage)
+		expectErr bool
+	}{
+		{
+			name: "Purge",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Purge", editorCtx, postID, 0).Return(nil)
+			},
+		},
+		{
+			name: "Purge with wrong ID",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Purge", editorCtx, postID, 0).Return(ErrGetPostNotFound)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Purge with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Purge", editorCtx, postID, 0).Return(errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			err := postService.Purge(editorCtx, postID, 0)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to purge post")
+			} else {
+				require.Error(t, err, "no error")
+			}
+		})
+	}
+}
+
+func TestPostService_PurgeTrash(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	retention := 24 * time.Hour
+	beforeRetention := mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= retention && time.Since(before) < retention+time.Minute
+	})
+
+	testCases := []struct {
+		name           string
+		mock           func(m *mocks.PostStorage)
+		expectedPurged int64
+		expectErr      bool
+	}{
+		{
+			name: "PurgeTrash",
+			mock: func(m *mocks.PostStorage) {
+				m.On("PurgeDeletedBefore", systemCtx, beforeRetention).Return(int64(2), nil)
+			},
+			expectedPurged: 2,
+		},
+		{
+			name: "PurgeTrash with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("PurgeDeletedBefore", systemCtx, beforeRetention).Return(int64(0), errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+			storages := Storages{Post: postStorageMock}
+
+			postService := NewPostService(storages, logger)
+			actual, err := postService.PurgeTrash(systemCtx, retention)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to purge trash")
+				require.Equal(t, tc.expectedPurged, actual, "purged counts are not equal")
+			} else {
+				require.Error(t, err, "no error")
+			}
+		})
+	}
+}
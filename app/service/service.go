@@ -4,53 +4,797 @@ import (
 	"context"
 	"darkness8129/news-api/app/entity"
 	"darkness8129/news-api/packages/errs"
+	"time"
 )
 
 const (
-	postNotFoundErrCode = "post_not_found"
+	postNotFoundErrCode             = "post_not_found"
+	deletedPostNotFoundErrCode      = "deleted_post_not_found"
+	invalidCursorErrCode            = "invalid_cursor"
+	invalidSortErrCode              = "invalid_sort"
+	invalidRangeErrCode             = "invalid_range"
+	emptyQueryErrCode               = "empty_query"
+	invalidStatusTransitionErrCode  = "invalid_status_transition"
+	postStatusConflictErrCode       = "post_status_conflict"
+	invalidPublishAtErrCode         = "invalid_publish_at"
+	postNotDraftErrCode             = "post_not_draft"
+	postRevisionNotFoundErrCode     = "post_revision_not_found"
+	postVersionMismatchErrCode      = "post_version_mismatch"
+	idempotencyKeyReusedErrCode     = "idempotency_key_reused"
+	idempotencyKeyInProgressErrCode = "idempotency_key_in_progress"
+	idempotencyKeyExistsErrCode     = "idempotency_key_exists"
+	idempotencyKeyNotFoundErrCode   = "idempotency_key_not_found"
+	unknownPostOperationErrCode     = "unknown_post_operation"
+	invalidTagErrCode               = "invalid_tag"
+	categoryNotFoundErrCode         = "category_not_found"
+	unknownCategoryErrCode          = "unknown_category"
+	categoryCycleErrCode            = "category_cycle"
+	categoryNotEmptyErrCode         = "category_not_empty"
+	authorNotFoundErrCode           = "author_not_found"
+	unknownAuthorErrCode            = "unknown_author"
+	invalidSlugErrCode              = "invalid_slug"
+	authorSlugExistsErrCode         = "author_slug_exists"
+	postSlugExistsErrCode           = "post_slug_exists"
+	commentNotFoundErrCode          = "comment_not_found"
+	unknownParentCommentErrCode     = "unknown_parent_comment"
+	invalidCommentTokenErrCode      = "invalid_comment_token"
+	invalidModerationStatusErrCode  = "invalid_moderation_status"
+	moderationReasonRequiredErrCode = "moderation_reason_required"
+	userNotFoundErrCode             = "user_not_found"
+	userEmailExistsErrCode          = "user_email_exists"
+	invalidCredentialsErrCode       = "invalid_credentials"
+	invalidTokenErrCode             = "invalid_token"
+	refreshTokenNotFoundErrCode     = "refresh_token_not_found"
+	refreshTokenUsedErrCode         = "refresh_token_used"
+	refreshTokenReusedErrCode       = "refresh_token_reused"
+	authenticationRequiredErrCode   = "authentication_required"
+	forbiddenErrCode                = "forbidden"
+	invalidRoleErrCode              = "invalid_role"
+	ownRoleChangeErrCode            = "own_role_change"
+	apiKeyNotFoundErrCode           = "api_key_not_found"
+	invalidAPIKeyErrCode            = "invalid_api_key"
+	invalidScopeErrCode             = "invalid_scope"
+	invalidExpiresAtErrCode         = "invalid_expires_at"
+	oidcDisabledErrCode             = "oidc_disabled"
+	invalidOIDCStateErrCode         = "invalid_oidc_state"
+	oidcLoginRejectedErrCode        = "oidc_login_rejected"
+	oidcEmailNotVerifiedErrCode     = "oidc_email_not_verified"
+	oidcAuthRequestNotFoundErrCode  = "oidc_auth_request_not_found"
 	// other err codes should be here
 )
 
 type Services struct {
-	Post PostService
+	Post        PostService
+	Idempotency IdempotencyService
+	Tag         TagService
+	Category    CategoryService
+	Author      AuthorService
+	Comment     CommentService
+	Moderation  ModerationService
+	Auth        AuthService
+	User        UserService
+	APIKey      APIKeyService
 	// other services should be here
 }
 
+// PostService checks the principal of the context in every method: anyone can read posts,
+// authors create posts and edit their own ones, editors edit, schedule, publish and trash any post,
+// PurgeTrash, PublishScheduled and BackfillSlugs are left to background jobs with SystemContext
 type PostService interface {
 	Create(ctx context.Context, opt CreatePostOpt) (*entity.Post, error)
-	List(ctx context.Context) ([]entity.Post, error)
+	List(ctx context.Context, opt ListPostsOpt) (*ListPostsResult, error)
+	Search(ctx context.Context, opt SearchPostsOpt) ([]entity.PostSearchResult, error)
 	Get(ctx context.Context, id string) (*entity.Post, error)
+	// GetBySlug finds the post by its current or any previous slug,
+	// the returned post has the current one, so old links can be redirected
+	GetBySlug(ctx context.Context, slug string) (*entity.Post, error)
 	Update(ctx context.Context, id string, opt UpdatePostOpt) (*entity.Post, error)
-	Delete(ctx context.Context, id string) error
+	// Delete moves the post to the trash, from where it can be restored.
+	// Version is the expected current version of the post, 0 skips the check.
+	Delete(ctx context.Context, id string, version int) error
+	ListTrash(ctx context.Context, opt ListPostsOpt) (*ListPostsResult, error)
+	// Restore returns the post from the trash.
+	// Version is the expected current version of the post, 0 skips the check.
+	Restore(ctx context.Context, id string, version int) (*entity.Post, error)
+	// Purge deletes the post permanently, it can be in the trash or not.
+	// Version is the expected current version of the post, 0 skips the check.
+	Purge(ctx context.Context, id string, version int) error
+	// PurgeTrash permanently deletes posts which have been in the trash longer than retention
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+	// Publish, Unpublish and Archive change the status of the post.
+	// Version is the expected current version of the post, 0 skips the check.
+	Publish(ctx context.Context, id string, version int) (*entity.Post, error)
+	Unpublish(ctx context.Context, id string, version int) (*entity.Post, error)
+	Archive(ctx context.Context, id string, version int) (*entity.Post, error)
+	// PublishScheduled publishes drafts whose PublishAt has come, it's safe to call concurrently
+	PublishScheduled(ctx context.Context) ([]entity.Post, error)
+	// BackfillSlugs generates slugs for posts created before slugs, including trashed ones,
+	// and returns their number. It's run on start, after the first run there are no such posts.
+	BackfillSlugs(ctx context.Context) (int, error)
+	ListRevisions(ctx context.Context, id string) ([]entity.PostRevision, error)
+	GetRevision(ctx context.Context, id string, number int) (*entity.PostRevision, error)
+	DiffRevisions(ctx context.Context, id string, from, to int) (*PostRevisionsDiff, error)
+	// RestoreRevision rolls the post content back to the revision, it's recorded as a new revision.
+	// Version is the expected current version of the post, 0 skips the check.
+	RestoreRevision(ctx context.Context, id string, number, version int) (*entity.Post, error)
+	// Batch applies the operations in their order. In atomic mode all of them are applied in one
+	// transaction and the first failed one is returned as an error with its index in the "operation"
+	// metadata, otherwise every operation is applied on its own and its error is put in the result.
+	Batch(ctx context.Context, opt BatchPostsOpt) ([]PostOperationResult, error)
 }
 
 // expected errors for this service should be here
+var (
+	ErrListPostsInvalidCursor      = errs.New(errs.Options{Message: "invalid cursor", Code: invalidCursorErrCode, Kind: errs.KindInvalid})
+	ErrListPostsInvalidSort        = errs.New(errs.Options{Message: "invalid sort", Code: invalidSortErrCode, Kind: errs.KindInvalid})
+	ErrListPostsInvalidRange       = errs.New(errs.Options{Message: "createdTo is earlier than createdFrom", Code: invalidRangeErrCode, Kind: errs.KindInvalid})
+	ErrSearchPostsEmptyQuery       = errs.New(errs.Options{Message: "search query is empty", Code: emptyQueryErrCode, Kind: errs.KindInvalid})
+	ErrInvalidPostStatusTransition = errs.New(errs.Options{Message: "invalid post status transition", Code: invalidStatusTransitionErrCode, Kind: errs.KindConflict})
+	ErrPublishAtNotInFuture        = errs.New(errs.Options{Message: "publishAt must be in the future", Code: invalidPublishAtErrCode, Kind: errs.KindInvalid})
+	ErrSchedulePostNotDraft        = errs.New(errs.Options{Message: "only drafts can be scheduled for publishing", Code: postNotDraftErrCode, Kind: errs.KindConflict})
+	ErrUnknownPostOperation        = errs.New(errs.Options{Message: "unknown post operation", Code: unknownPostOperationErrCode, Kind: errs.KindInvalid})
+	ErrInvalidTag                  = errs.New(errs.Options{Message: "tag must not be blank", Code: invalidTagErrCode, Kind: errs.KindInvalid})
+)
 
 type CreatePostOpt struct {
 	Title   string
 	Content string
+	// PublishAt schedules publishing of the created draft, optional
+	PublishAt *time.Time
+	// CategoryID is the primary category of the post, optional
+	CategoryID *string
+	// AuthorIDs are bylines of the post in their order, duplicates are skipped
+	AuthorIDs []string
+	// Tags are names of the post tags, missing tags are created
+	Tags []string
+}
+
+// ListPostsOpt describes a requested page of posts. Cursor is an opaque value
+// taken from the NextCursor of the previous page, empty for the first page.
+// The cursor is bound to the sort it was issued for.
+type ListPostsOpt struct {
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	UpdatedSince  *time.Time
+	TitleContains string
+	// Tags selects posts with any of the tags, or with all of them if TagsMatchAll is set
+	Tags         []string
+	TagsMatchAll bool
+	// CategoryID selects posts of the category and all its descendants
+	CategoryID string
+	// AuthorID selects posts with the author in the bylines
+	AuthorID string
+	// Sort is entity.PostsSortCreatedAtDesc by default
+	Sort entity.PostsSort
+
+	Limit  int
+	Cursor string
 }
 
+type ListPostsResult struct {
+	Posts []entity.Post
+	// NextCursor is empty when there are no more posts
+	NextCursor string
+}
+
+// SearchPostsOpt describes a full-text query, web search syntax is supported
+// (quoted phrases, "or", "-" for exclusion)
+type SearchPostsOpt struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// UpdatePostOpt lists fields to change, nil fields are left as they are and
+// pointers to empty strings clear the fields
 type UpdatePostOpt struct {
-	Title   string
+	Title   *string
+	Content *string
+	// PublishAt schedules publishing of the draft, optional
+	PublishAt *time.Time
+	// ClearPublishAt cancels scheduled publishing, it's ignored when PublishAt is set
+	ClearPublishAt bool
+	CategoryID     *string
+	// ClearCategory removes the post from its category, it's ignored when CategoryID is set
+	ClearCategory bool
+	// AuthorIDs replace all bylines of the post, nil leaves them as they are and an empty slice removes them
+	AuthorIDs []string
+	// Tags replace all tags of the post, nil leaves them as they are and an empty slice removes them
+	Tags []string
+	// Version is the expected current version of the post, 0 skips the check
+	Version int
+}
+
+type PostOperationType string
+
+const (
+	PostOperationCreate PostOperationType = "create"
+	PostOperationUpdate PostOperationType = "update"
+	PostOperationDelete PostOperationType = "delete"
+)
+
+type BatchPostsOpt struct {
+	Operations []PostOperation
+	// Atomic makes the batch all-or-nothing, nothing is changed if any operation fails
+	Atomic bool
+}
+
+// PostOperation describes one operation of a batch, Create is used only for creating,
+// Update only for updating, ID is required for updating and deleting
+type PostOperation struct {
+	Type   PostOperationType
+	ID     string
+	Create CreatePostOpt
+	Update UpdatePostOpt
+	// Version is the expected current version of the deleted post, 0 skips the check
+	Version int
+}
+
+// PostOperationResult holds the created or updated post, Post is nil for deleting or if Err is set
+type PostOperationResult struct {
+	Post *entity.Post
+	Err  error
+}
+
+// PostRevisionsDiff holds unified diffs of the post fields between two revisions
+type PostRevisionsDiff struct {
+	From        int
+	To          int
+	TitleDiff   string
+	ContentDiff string
+}
+
+// IdempotencyService makes repeated requests with the same idempotency key return the first response
+type IdempotencyService interface {
+	// Begin returns nil if the request is new and must be handled, then Complete or Release must be called.
+	// The completed key is returned for a repeated request, so its response can be replayed.
+	Begin(ctx context.Context, opt BeginIdempotentRequestOpt) (*entity.IdempotencyKey, error)
+	Complete(ctx context.Context, opt CompleteIdempotentRequestOpt) error
+	// Release forgets the key, so the request can be retried, e.g. when it failed unexpectedly
+	Release(ctx context.Context, clientID, key string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+var (
+	ErrIdempotencyKeyReused     = errs.New(errs.Options{Message: "idempotency key is already used for another request", Code: idempotencyKeyReusedErrCode, Kind: errs.KindUnprocessable})
+	ErrIdempotencyKeyInProgress = errs.New(errs.Options{Message: "request with this idempotency key is in progress", Code: idempotencyKeyInProgressErrCode, Kind: errs.KindConflict})
+)
+
+type BeginIdempotentRequestOpt struct {
+	ClientID string
+	Key      string
+	// RequestHash identifies the request, the key can't be reused for a request with another hash
+	RequestHash string
+}
+
+type CompleteIdempotentRequestOpt struct {
+	ClientID       string
+	Key            string
+	ResponseStatus int
+	ResponseHeader map[string][]string
+	ResponseBody   []byte
+}
+
+// TagService manages tags of posts, tag names are case insensitive
+type TagService interface {
+	// List returns all tags with numbers of published posts labeled with them, the most used go first
+	List(ctx context.Context) ([]entity.TagUsage, error)
+}
+
+// CategoryService manages the tree of categories
+type CategoryService interface {
+	Create(ctx context.Context, opt CreateCategoryOpt) (*entity.Category, error)
+	// List returns all categories ordered by name, the tree is built from their parents
+	List(ctx context.Context) ([]entity.Category, error)
+	// Get returns the category with its direct children
+	Get(ctx context.Context, id string) (*entity.Category, error)
+	// Update renames and moves the category, it can't be moved under itself or its descendants
+	Update(ctx context.Context, id string, opt UpdateCategoryOpt) (*entity.Category, error)
+	// Delete deletes only categories without children and posts, including posts in the trash
+	Delete(ctx context.Context, id string) error
+}
+
+var (
+	// ErrUnknownCategory is returned when a post or a category refers to a category which doesn't exist
+	ErrUnknownCategory = errs.New(errs.Options{Message: "category doesn't exist", Code: unknownCategoryErrCode, Kind: errs.KindUnprocessable})
+	ErrCategoryCycle   = errs.New(errs.Options{Message: "category can't be moved under itself or its descendant", Code: categoryCycleErrCode, Kind: errs.KindConflict})
+)
+
+type CreateCategoryOpt struct {
+	Name string
+	// ParentID is nil for top-level categories
+	ParentID *string
+}
+
+type UpdateCategoryOpt struct {
+	Name string
+	// ParentID is nil for top-level categories
+	ParentID *string
+}
+
+type AuthorService interface {
+	Create(ctx context.Context, opt CreateAuthorOpt) (*entity.Author, error)
+	// List returns all authors ordered by name
+	List(ctx context.Context) ([]entity.Author, error)
+	Get(ctx context.Context, id string) (*entity.Author, error)
+}
+
+var (
+	// ErrUnknownAuthor is returned when a post refers to an author who doesn't exist
+	ErrUnknownAuthor = errs.New(errs.Options{Message: "author doesn't exist", Code: unknownAuthorErrCode, Kind: errs.KindUnprocessable})
+	ErrInvalidSlug   = errs.New(errs.Options{Message: "slug must consist of lowercase letters, digits and single hyphens", Code: invalidSlugErrCode, Kind: errs.KindInvalid})
+)
+
+type CreateAuthorOpt struct {
+	Name      string
+	Slug      string
+	Bio       string
+	AvatarURL string
+}
+
+type CommentService interface {
+	// Create returns the secret token of the comment, it's required to edit or delete the comment.
+	// Comments of pre-moderated posts are created pending.
+	Create(ctx context.Context, opt CreateCommentOpt) (*CreateCommentResult, error)
+	// List returns one level of the post thread page by page, oldest first, only approved comments are listed
+	List(ctx context.Context, opt ListCommentsOpt) (*ListCommentsResult, error)
+	// Update and Delete return ErrInvalidCommentToken if the token isn't the one given on creation,
+	// edited approved comments of pre-moderated posts become pending again
+	Update(ctx context.Context, opt UpdateCommentOpt) (*entity.Comment, error)
+	Delete(ctx context.Context, opt DeleteCommentOpt) error
+}
+
+var (
+	ErrUnknownParentComment      = errs.New(errs.Options{Message: "parent comment doesn't exist", Code: unknownParentCommentErrCode, Kind: errs.KindUnprocessable})
+	ErrInvalidCommentToken       = errs.New(errs.Options{Message: "invalid comment token", Code: invalidCommentTokenErrCode, Kind: errs.KindForbidden})
+	ErrListCommentsInvalidCursor = errs.New(errs.Options{Message: "invalid cursor", Code: invalidCursorErrCode, Kind: errs.KindInvalid})
+)
+
+type CreateCommentOpt struct {
+	PostID string
+	// ParentID is the comment the reply answers, nil for top-level comments
+	ParentID   *string
+	AuthorName string
+	Content    string
+}
+
+type CreateCommentResult struct {
+	Comment *entity.Comment
+	// Token is given only once, just its hash is stored
+	Token string
+}
+
+type ListCommentsOpt struct {
+	PostID string
+	// ParentID selects replies of the comment, nil selects top-level comments
+	ParentID *string
+	// Limit is 20 by default and 100 at most
+	Limit  int
+	Cursor string
+}
+
+type ListCommentsResult struct {
+	Comments []entity.Comment
+	// NextCursor is empty for the last page
+	NextCursor string
+}
+
+type UpdateCommentOpt struct {
+	PostID  string
+	ID      string
+	Token   string
 	Content string
 }
 
+type DeleteCommentOpt struct {
+	PostID string
+	ID     string
+	Token  string
+}
+
+type ModerationService interface {
+	// ListComments returns comments of all posts and thread levels with the status page by page, oldest first
+	ListComments(ctx context.Context, opt ListModerationCommentsOpt) (*ListCommentsResult, error)
+	// ModerateComment records the decision in the moderation history, rejecting requires a reason
+	ModerateComment(ctx context.Context, opt ModerateCommentOpt) (*entity.Comment, error)
+	// CommentHistory returns moderation decisions on the comment, the newest first
+	CommentHistory(ctx context.Context, id string) ([]entity.CommentModeration, error)
+	GetPostPolicy(ctx context.Context, postID string) (*PostModerationPolicy, error)
+	SetPostPolicy(ctx context.Context, opt SetPostModerationPolicyOpt) (*PostModerationPolicy, error)
+}
+
+var (
+	ErrInvalidModerationStatus  = errs.New(errs.Options{Message: "comment can be approved, rejected or marked as spam only", Code: invalidModerationStatusErrCode, Kind: errs.KindInvalid})
+	ErrModerationReasonRequired = errs.New(errs.Options{Message: "reason is required to reject a comment", Code: moderationReasonRequiredErrCode, Kind: errs.KindInvalid})
+)
+
+type ListModerationCommentsOpt struct {
+	// Status is pending by default
+	Status entity.CommentStatus
+	// PostID selects comments of the post only, empty means any post
+	PostID string
+	// Limit is 20 by default and 100 at most
+	Limit  int
+	Cursor string
+}
+
+type ModerateCommentOpt struct {
+	ID string
+	// Status is approved, rejected or spam
+	Status entity.CommentStatus
+	// Reason is required unless the comment is approved
+	Reason string
+}
+
+type PostModerationPolicy struct {
+	// Premoderation is the policy of the post, nil means the global one
+	Premoderation *bool
+	// Effective tells whether new comments of the post wait for approval
+	Effective bool
+}
+
+type SetPostModerationPolicyOpt struct {
+	PostID string
+	// Premoderation nil makes the post follow the global policy
+	Premoderation *bool
+}
+
+type AuthService interface {
+	Register(ctx context.Context, opt RegisterOpt) (*entity.User, error)
+	// Login returns ErrInvalidCredentials for unknown emails and wrong passwords alike
+	Login(ctx context.Context, opt LoginOpt) (*AuthTokens, error)
+	// Refresh exchanges the refresh token for new tokens, each refresh token can be used once.
+	// Using it again revokes all tokens issued since the same login and returns ErrRefreshTokenReused.
+	Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error)
+	// Authenticate checks the access token without storages, so it's valid till it expires
+	Authenticate(ctx context.Context, accessToken string) (*Principal, error)
+	GetUser(ctx context.Context, id string) (*entity.User, error)
+	// StartOIDCLogin returns the URL of the identity provider the user is sent to sign in,
+	// it returns ErrOIDCDisabled if no provider is configured
+	StartOIDCLogin(ctx context.Context) (string, error)
+	// CompleteOIDCLogin exchanges the code the provider sent back for tokens, each state can be used once.
+	// The user is found by the subject or linked by the verified email, unknown users are created.
+	CompleteOIDCLogin(ctx context.Context, opt CompleteOIDCLoginOpt) (*AuthTokens, error)
+}
+
+var (
+	ErrInvalidCredentials   = errs.New(errs.Options{Message: "invalid email or password", Code: invalidCredentialsErrCode, Kind: errs.KindUnauthorized})
+	ErrInvalidToken         = errs.New(errs.Options{Message: "invalid or expired token", Code: invalidTokenErrCode, Kind: errs.KindUnauthorized})
+	ErrRefreshTokenReused   = errs.New(errs.Options{Message: "refresh token is already used, sign in again", Code: refreshTokenReusedErrCode, Kind: errs.KindUnauthorized})
+	ErrOIDCDisabled         = errs.New(errs.Options{Message: "sign-in through the identity provider isn't configured", Code: oidcDisabledErrCode, Kind: errs.KindNotFound})
+	ErrInvalidOIDCState     = errs.New(errs.Options{Message: "invalid or expired sign-in state, start the sign-in again", Code: invalidOIDCStateErrCode, Kind: errs.KindUnauthorized})
+	ErrOIDCLoginRejected    = errs.New(errs.Options{Message: "identity provider rejected the sign-in", Code: oidcLoginRejectedErrCode, Kind: errs.KindUnauthorized})
+	ErrOIDCEmailNotVerified = errs.New(errs.Options{Message: "identity provider didn't share a verified email", Code: oidcEmailNotVerifiedErrCode, Kind: errs.KindForbidden})
+)
+
+type RegisterOpt struct {
+	Email    string
+	Name     string
+	Password string
+}
+
+type LoginOpt struct {
+	Email    string
+	Password string
+}
+
+type CompleteOIDCLoginOpt struct {
+	State string
+	Code  string
+	// Error is the error the provider sent back instead of the code, the state is used up anyway
+	Error string
+}
+
+type AuthTokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// Principal is the authenticated user or API key of the request
+type Principal struct {
+	// UserID and Email are empty for API keys
+	UserID string
+	Email  string
+	// Role is taken from the access token, so a changed role applies after the next refresh.
+	// API keys get the role of their widest scope.
+	Role entity.UserRole
+	// APIKeyID is set for requests made with an API key
+	APIKeyID string
+	// Scopes limit API keys, users have no scopes and are limited by their role only
+	Scopes []entity.APIKeyScope
+}
+
+// HasScope tells whether one of the scopes includes the scope, it's always true for users
+func (p *Principal) HasScope(scope entity.APIKeyScope) bool {
+	if p.APIKeyID == "" {
+		return true
+	}
+
+	for _, s := range p.Scopes {
+		if s.Includes(scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errors of the access policy, every service checks the principal of the context on its own
+var (
+	ErrAuthenticationRequired = errs.New(errs.Options{Message: "authentication required", Code: authenticationRequiredErrCode, Kind: errs.KindUnauthorized})
+	ErrForbidden              = errs.New(errs.Options{Message: "not allowed for your role", Code: forbiddenErrCode, Kind: errs.KindForbidden})
+)
+
+type UserService interface {
+	// List is available to admins only
+	List(ctx context.Context, opt ListUsersOpt) ([]entity.User, error)
+	// SetRole is available to admins only, they can't change their own role, so there is always an admin left
+	SetRole(ctx context.Context, opt SetUserRoleOpt) (*entity.User, error)
+}
+
+var (
+	ErrInvalidUserRole = errs.New(errs.Options{Message: "role must be reader, author, editor or admin", Code: invalidRoleErrCode, Kind: errs.KindInvalid})
+	ErrChangeOwnRole   = errs.New(errs.Options{Message: "you can't change your own role", Code: ownRoleChangeErrCode, Kind: errs.KindForbidden})
+)
+
+type ListUsersOpt struct {
+	// Role is optional, all users are listed without it
+	Role entity.UserRole
+}
+
+type SetUserRoleOpt struct {
+	UserID string
+	Role   entity.UserRole
+}
+
+// APIKeyPrefix starts every API key, so keys can be told from access tokens and found by secret scanners
+const APIKeyPrefix = "nak_"
+
+type APIKeyService interface {
+	// Create is available to admins only, the returned key is never shown again
+	Create(ctx context.Context, opt CreateAPIKeyOpt) (*CreatedAPIKey, error)
+	// List returns revoked and expired keys too, the newest first, it's available to admins only
+	List(ctx context.Context) ([]entity.APIKey, error)
+	// Revoke is available to admins only, revoking a revoked key keeps its revocation time
+	Revoke(ctx context.Context, id string) (*entity.APIKey, error)
+	// Authenticate returns ErrInvalidAPIKey for unknown, expired and revoked keys alike and tracks the key usage
+	Authenticate(ctx context.Context, key string) (*Principal, error)
+}
+
+var (
+	ErrInvalidAPIKey              = errs.New(errs.Options{Message: "invalid, expired or revoked API key", Code: invalidAPIKeyErrCode, Kind: errs.KindUnauthorized})
+	ErrInvalidAPIKeyScope         = errs.New(errs.Options{Message: "scope must be posts:read, posts:write or admin", Code: invalidScopeErrCode, Kind: errs.KindInvalid})
+	ErrAPIKeyExpiresAtNotInFuture = errs.New(errs.Options{Message: "expiresAt must be in the future", Code: invalidExpiresAtErrCode, Kind: errs.KindInvalid})
+)
+
+type CreateAPIKeyOpt struct {
+	Name   string
+	Scopes []entity.APIKeyScope
+	// ExpiresAt is optional, keys without it are valid till they are revoked
+	ExpiresAt *time.Time
+}
+
+type CreatedAPIKey struct {
+	APIKey *entity.APIKey
+	// Key is the secret itself, only its hash is stored
+	Key string
+}
+
 type Storages struct {
-	Post PostStorage
+	Post            PostStorage
+	IdempotencyKey  IdempotencyKeyStorage
+	Tag             TagStorage
+	Category        CategoryStorage
+	Author          AuthorStorage
+	Comment         CommentStorage
+	User            UserStorage
+	RefreshToken    RefreshTokenStorage
+	APIKey          APIKeyStorage
+	OIDCAuthRequest OIDCAuthRequestStorage
 	// other storages should be here
+
+	// Transaction calls fn with storages working in one transaction,
+	// it's committed if fn returns nil and rolled back otherwise
+	Transaction func(ctx context.Context, fn func(storages Storages) error) error
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name PostStorage --output ./mocks
 type PostStorage interface {
+	// Create and Update return ErrPostSlugExists if the slug belongs to another post
 	Create(ctx context.Context, post *entity.Post) (*entity.Post, error)
-	List(ctx context.Context) ([]entity.Post, error)
+	List(ctx context.Context, filter entity.PostsFilter) ([]entity.Post, error)
+	Search(ctx context.Context, filter entity.PostsSearchFilter) ([]entity.PostSearchResult, error)
 	Get(ctx context.Context, id string) (*entity.Post, error)
-	Update(ctx context.Context, id string, post *entity.Post) (*entity.Post, error)
+	// GetBySlug finds the post by its current or any previous slug
+	GetBySlug(ctx context.Context, slug string) (*entity.Post, error)
+	// Update writes a new revision in the same transaction if the title or content is changed
+	// Update and Delete return ErrPostVersionMismatch if the version is passed and the post has another one
+	Update(ctx context.Context, id string, update entity.PostUpdate) (*entity.Post, error)
+	Delete(ctx context.Context, id string, version int) error
+	// UpdateStatus changes the status only if the post still has the from status,
+	// otherwise ErrUpdatePostStatusConflict is returned. ErrPostVersionMismatch is returned
+	// if the version is passed and the post has another one.
+	UpdateStatus(
+		ctx context.Context, id string, from, to entity.PostStatus, publishedAt *time.Time, version int,
+	) (*entity.Post, error)
+	// PublishDue publishes at most limit drafts scheduled at or before now, each post is
+	// published exactly once even if it's called from several app instances at the same time
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]entity.Post, error)
+	ListRevisions(ctx context.Context, postID string) ([]entity.PostRevision, error)
+	GetRevision(ctx context.Context, postID string, number int) (*entity.PostRevision, error)
+	// Restore returns a soft-deleted post back, ErrGetDeletedPostNotFound is returned for posts which aren't deleted
+	// and ErrPostVersionMismatch if the version is passed and the post has another one
+	Restore(ctx context.Context, id string, version int) (*entity.Post, error)
+	Purge(ctx context.Context, id string, version int) error
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	// SetCommentsPremoderation doesn't change the version of the post, the policy isn't a part of the post content
+	SetCommentsPremoderation(ctx context.Context, id string, premoderation *bool) error
+	// ListWithoutSlug lists at most limit posts without a slug, including trashed ones
+	ListWithoutSlug(ctx context.Context, limit int) ([]entity.Post, error)
+	// SetSlug reserves the slug for the post and makes it the current one without changing the version,
+	// ErrPostSlugExists is returned if the slug belongs to another post
+	SetSlug(ctx context.Context, id, slug string) error
+}
+
+var (
+	ErrGetPostNotFound          = errs.New(errs.Options{Message: "post not found", Code: postNotFoundErrCode, Kind: errs.KindNotFound})
+	ErrUpdatePostStatusConflict = errs.New(errs.Options{Message: "post status has been changed", Code: postStatusConflictErrCode, Kind: errs.KindConflict})
+	ErrGetPostRevisionNotFound  = errs.New(errs.Options{Message: "post revision not found", Code: postRevisionNotFoundErrCode, Kind: errs.KindNotFound})
+	ErrGetDeletedPostNotFound   = errs.New(errs.Options{Message: "deleted post not found", Code: deletedPostNotFoundErrCode, Kind: errs.KindNotFound})
+	ErrPostVersionMismatch      = errs.New(errs.Options{Message: "post has been changed", Code: postVersionMismatchErrCode, Kind: errs.KindPreconditionFailed})
+	ErrPostSlugExists           = errs.New(errs.Options{Message: "post with this slug already exists", Code: postSlugExistsErrCode, Kind: errs.KindConflict})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name IdempotencyKeyStorage --output ./mocks
+type IdempotencyKeyStorage interface {
+	// Create returns ErrCreateIdempotencyKeyExists if the client has the key already,
+	// an expired key is replaced as if it didn't exist
+	Create(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, error)
+	Get(ctx context.Context, clientID, key string) (*entity.IdempotencyKey, error)
+	// Complete stores the response of the key and marks it completed
+	Complete(ctx context.Context, key *entity.IdempotencyKey) error
+	Delete(ctx context.Context, clientID, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+var (
+	ErrCreateIdempotencyKeyExists = errs.New(errs.Options{Message: "idempotency key already exists", Code: idempotencyKeyExistsErrCode, Kind: errs.KindConflict})
+	ErrGetIdempotencyKeyNotFound  = errs.New(errs.Options{Message: "idempotency key not found", Code: idempotencyKeyNotFoundErrCode, Kind: errs.KindNotFound})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name TagStorage --output ./mocks
+type TagStorage interface {
+	// Ensure creates the tags which don't exist yet and returns the tags with all the names
+	Ensure(ctx context.Context, names []string) ([]entity.Tag, error)
+	// List counts only published posts which aren't deleted, the most used tags go first
+	List(ctx context.Context) ([]entity.TagUsage, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name CategoryStorage --output ./mocks
+type CategoryStorage interface {
+	Create(ctx context.Context, category *entity.Category) (*entity.Category, error)
+	List(ctx context.Context) ([]entity.Category, error)
+	// Get loads direct children of the category too
+	Get(ctx context.Context, id string) (*entity.Category, error)
+	Update(ctx context.Context, id string, update entity.CategoryUpdate) (*entity.Category, error)
+	// Delete returns ErrDeleteCategoryNotEmpty if the category has children or posts
+	Delete(ctx context.Context, id string) error
+	// SubtreeIDs returns IDs of the category and all its descendants
+	SubtreeIDs(ctx context.Context, id string) ([]string, error)
+}
+
+var (
+	ErrGetCategoryNotFound    = errs.New(errs.Options{Message: "category not found", Code: categoryNotFoundErrCode, Kind: errs.KindNotFound})
+	ErrDeleteCategoryNotEmpty = errs.New(errs.Options{Message: "category has subcategories or posts", Code: categoryNotEmptyErrCode, Kind: errs.KindConflict})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name AuthorStorage --output ./mocks
+type AuthorStorage interface {
+	// Create returns ErrCreateAuthorSlugExists if another author has the slug
+	Create(ctx context.Context, author *entity.Author) (*entity.Author, error)
+	List(ctx context.Context) ([]entity.Author, error)
+	Get(ctx context.Context, id string) (*entity.Author, error)
+	// GetMany returns the authors which exist, unknown IDs are skipped
+	GetMany(ctx context.Context, ids []string) ([]entity.Author, error)
+}
+
+var (
+	ErrGetAuthorNotFound      = errs.New(errs.Options{Message: "author not found", Code: authorNotFoundErrCode, Kind: errs.KindNotFound})
+	ErrCreateAuthorSlugExists = errs.New(errs.Options{Message: "author with this slug already exists", Code: authorSlugExistsErrCode, Kind: errs.KindConflict})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name CommentStorage --output ./mocks
+type CommentStorage interface {
+	// Create, Update and Delete keep the comments count of the post in the same transaction,
+	// see entity.Comment.IsCounted
+	Create(ctx context.Context, comment *entity.Comment) (*entity.Comment, error)
+	List(ctx context.Context, filter entity.CommentsFilter) ([]entity.Comment, error)
+	// ListForModeration doesn't return deleted comments
+	ListForModeration(ctx context.Context, filter entity.ModerationQueueFilter) ([]entity.Comment, error)
+	// Get returns deleted comments too, they have DeletedAt set
+	Get(ctx context.Context, id string) (*entity.Comment, error)
+	// Update of a deleted comment is ErrGetCommentNotFound
+	Update(ctx context.Context, id string, update entity.CommentUpdate) (*entity.Comment, error)
+	// Delete clears the content of the comment, deleting a deleted comment is ErrGetCommentNotFound
 	Delete(ctx context.Context, id string) error
+	// ListModerations returns the moderation history of the comment, the newest first
+	ListModerations(ctx context.Context, commentID string) ([]entity.CommentModeration, error)
+}
+
+var (
+	ErrGetCommentNotFound = errs.New(errs.Options{Message: "comment not found", Code: commentNotFoundErrCode, Kind: errs.KindNotFound})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name UserStorage --output ./mocks
+type UserStorage interface {
+	Create(ctx context.Context, user *entity.User) (*entity.User, error)
+	Get(ctx context.Context, id string) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	// GetByOIDCSubject returns ErrGetUserNotFound if no user is linked to the subject
+	GetByOIDCSubject(ctx context.Context, subject string) (*entity.User, error)
+	// LinkOIDCSubject returns ErrGetUserNotFound if the user doesn't exist
+	LinkOIDCSubject(ctx context.Context, id, subject string) (*entity.User, error)
+	// List returns users ordered by email
+	List(ctx context.Context, filter entity.UsersFilter) ([]entity.User, error)
+	// UpdateRole returns ErrGetUserNotFound if the user doesn't exist
+	UpdateRole(ctx context.Context, id string, role entity.UserRole) (*entity.User, error)
+}
+
+var (
+	ErrGetUserNotFound       = errs.New(errs.Options{Message: "user not found", Code: userNotFoundErrCode, Kind: errs.KindNotFound})
+	ErrCreateUserEmailExists = errs.New(errs.Options{Message: "user with this email already exists", Code: userEmailExistsErrCode, Kind: errs.KindConflict})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name RefreshTokenStorage --output ./mocks
+type RefreshTokenStorage interface {
+	Create(ctx context.Context, token *entity.RefreshToken) (*entity.RefreshToken, error)
+	// Use marks the token used, it returns ErrUseRefreshTokenUsed if the token is already used or revoked
+	Use(ctx context.Context, id string, usedAt time.Time) (*entity.RefreshToken, error)
+	// RevokeFamily revokes all tokens of the family the token belongs to
+	RevokeFamily(ctx context.Context, id string, revokedAt time.Time) error
+}
+
+var (
+	ErrUseRefreshTokenNotFound = errs.New(errs.Options{Message: "refresh token not found", Code: refreshTokenNotFoundErrCode, Kind: errs.KindNotFound})
+	ErrUseRefreshTokenUsed     = errs.New(errs.Options{Message: "refresh token is already used", Code: refreshTokenUsedErrCode, Kind: errs.KindConflict})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name APIKeyStorage --output ./mocks
+type APIKeyStorage interface {
+	Create(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error)
+	// List returns keys ordered from the newest
+	List(ctx context.Context) ([]entity.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	// Revoke keeps the revocation time of revoked keys
+	Revoke(ctx context.Context, id string, revokedAt time.Time) (*entity.APIKey, error)
+	// Touch records the usage of the key
+	Touch(ctx context.Context, id string, usedAt time.Time) error
+}
+
+var (
+	ErrGetAPIKeyNotFound = errs.New(errs.Options{Message: "API key not found", Code: apiKeyNotFoundErrCode, Kind: errs.KindNotFound})
+	// other expected errors for this storage should be here
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.27.1 --dir . --name OIDCAuthRequestStorage --output ./mocks
+type OIDCAuthRequestStorage interface {
+	// Create deletes expired requests too, so abandoned sign-ins don't pile up
+	Create(ctx context.Context, request *entity.OIDCAuthRequest) (*entity.OIDCAuthRequest, error)
+	// Take deletes the request and returns it, unknown, taken and expired requests
+	// result in ErrTakeOIDCAuthRequestNotFound
+	Take(ctx context.Context, state string, now time.Time) (*entity.OIDCAuthRequest, error)
 }
 
 var (
-	ErrGetPostNotFound = errs.New(errs.Options{Message: "post not found", Code: postNotFoundErrCode})
+	ErrTakeOIDCAuthRequestNotFound = errs.New(errs.Options{Message: "sign-in request not found", Code: oidcAuthRequestNotFoundErrCode, Kind: errs.KindNotFound})
 	// other expected errors for this storage should be here
 )
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// maxSlugLength leaves room for a suffix in the slug column
+	maxSlugLength = 80
+	// slugAttempts limits retries with random suffixes when slugs are taken by other posts
+	slugAttempts = 5
+	// defaultSlug is used for titles without any letters or digits which can be transliterated
+	defaultSlug = "post"
+)
+
+// slugTransliterations covers letters which don't decompose into ASCII ones,
+// Cyrillic follows the Ukrainian national transliteration, empty strings drop letters
+var slugTransliterations = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e", 'є': "ie", 'ж': "zh", 'з': "z",
+	'и': "y", 'і': "i", 'ї': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p",
+	'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ь': "", 'ю': "iu", 'я': "ia", 'ё': "io", 'ы': "y", 'э': "e", 'ъ': "",
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+	'\'': "", '’': "",
+}
+
+// slugify transliterates the title into lowercase ASCII words joined with hyphens, e.g.
+// "Café: Привіт, світ!" becomes "cafe-pryvit-svit". Diacritics are dropped, other characters separate words.
+func slugify(title string) string {
+	var slug strings.Builder
+	separate := false
+	for _, r := range strings.ToLower(title) {
+		word, ok := slugTransliterations[r]
+		if !ok {
+			// letters with diacritics decompose into a base letter and combining marks
+			for _, d := range norm.NFKD.String(string(r)) {
+				if d >= 'a' && d <= 'z' || d >= '0' && d <= '9' {
+					word += string(d)
+				}
+			}
+			if word == "" {
+				separate = slug.Len() > 0
+				continue
+			}
+		}
+
+		if separate && word != "" {
+			slug.WriteByte('-')
+			separate = false
+		}
+		slug.WriteString(word)
+	}
+
+	result := slug.String()
+	if len(result) > maxSlugLength {
+		result = result[:maxSlugLength]
+		// a cut word is dropped if there are whole ones
+		if i := strings.LastIndexByte(result, '-'); i > 0 {
+			result = result[:i]
+		}
+	}
+	if result == "" {
+		return defaultSlug
+	}
+
+	return result
+}
+
+// suffixedSlug makes another slug for the same title, random suffixes don't reveal the number of posts
+func suffixedSlug(slug string) string {
+	return slug + "-" + uuid.NewString()[:6]
+}
+
+// createPostWithSlug creates the post with a slug generated from its title,
+// a suffix is added to the slug if it's taken by another post
+func createPostWithSlug(ctx context.Context, storage PostStorage, post *entity.Post) (*entity.Post, error) {
+	slug := slugify(post.Title)
+	post.Slug = slug
+
+	createdPost, err := storage.Create(ctx, post)
+	for attempt := 1; errors.Is(err, ErrPostSlugExists) && attempt < slugAttempts; attempt++ {
+		post.Slug = suffixedSlug(slug)
+		createdPost, err = storage.Create(ctx, post)
+	}
+
+	return createdPost, err
+}
+
+// updatePostWithSlug updates the post with a slug generated from the new title, the storage
+// applies it only if the title is changed. A suffix is added to the slug if it's taken by another post.
+func updatePostWithSlug(ctx context.Context, storage PostStorage, id string, update entity.PostUpdate) (*entity.Post, error) {
+	if update.Title == nil {
+		return storage.Update(ctx, id, update)
+	}
+
+	slug := slugify(*update.Title)
+	update.Slug = &slug
+
+	updatedPost, err := storage.Update(ctx, id, update)
+	for attempt := 1; errors.Is(err, ErrPostSlugExists) && attempt < slugAttempts; attempt++ {
+		suffixed := suffixedSlug(slug)
+		update.Slug = &suffixed
+		updatedPost, err = storage.Update(ctx, id, update)
+	}
+
+	return updatedPost, err
+}
+
+// setPostSlug sets a slug generated from the title to the post without one,
+// a suffix is added to the slug if it's taken by another post
+func setPostSlug(ctx context.Context, storage PostStorage, id, title string) error {
+	slug := slugify(title)
+
+	err := storage.SetSlug(ctx, id, slug)
+	for attempt := 1; errors.Is(err, ErrPostSlugExists) && attempt < slugAttempts; attempt++ {
+		err = storage.SetSlug(ctx, id, suffixedSlug(slug))
+	}
+
+	return err
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Slugify words", input: "Breaking News: Go 1.22 Released!", expected: "breaking-news-go-1-22-released"},
+		{name: "Slugify diacritics", input: "Café crème à Zürich", expected: "cafe-creme-a-zurich"},
+		{name: "Slugify special Latin letters", input: "Straße in Łódź", expected: "strasse-in-lodz"},
+		{name: "Slugify Cyrillic", input: "Привіт, світ! Щастя є", expected: "pryvit-svit-shchastia-ie"},
+		{name: "Slugify apostrophes", input: "Don't panic, м'ята", expected: "dont-panic-miata"},
+		{name: "Slugify separators only", input: " --- !!! ", expected: defaultSlug},
+		{name: "Slugify untransliterable title", input: "新闻", expected: defaultSlug},
+		{name: "Slugify long title", input: strings.Repeat("word ", 30), expected: strings.TrimSuffix(strings.Repeat("word-", 16), "-")},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, slugify(tc.input), "slugs are not equal")
+		})
+	}
+}
+
+func TestCreatePostWithSlug(t *testing.T) {
+	t.Parallel()
+
+	suffixedSlug := regexp.MustCompile(`^title-[0-9a-f]{6}$`)
+	withSlug := func(slug string) interface{} {
+		return mock.MatchedBy(func(post *entity.Post) bool { return post.Slug == slug })
+	}
+	withSuffixedSlug := mock.MatchedBy(func(post *entity.Post) bool { return suffixedSlug.MatchString(post.Slug) })
+
+	testCases := []struct {
+		name      string
+		mock      func(m *mocks.PostStorage)
+		expectErr bool
+	}{
+		{
+			name: "Create with free slug",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", context.Background(), withSlug("title")).Return(&entity.Post{ID: uuid.NewString(), Slug: "title"}, nil)
+			},
+		},
+		{
+			name: "Create with taken slug",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", context.Background(), withSlug("title")).Return(nil, ErrPostSlugExists).Once()
+				m.On("Create", context.Background(), withSuffixedSlug).Return(&entity.Post{ID: uuid.NewString()}, nil).Once()
+			},
+		},
+		{
+			name: "Create with all slugs taken",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", context.Background(), withSlug("title")).Return(nil, ErrPostSlugExists).Once()
+				m.On("Create", context.Background(), withSuffixedSlug).Return(nil, ErrPostSlugExists).Times(slugAttempts - 1)
+			},
+			expectErr: true,
+		},
+		{
+			name: "Create with unexpected error in storage",
+			mock: func(m *mocks.PostStorage) {
+				m.On("Create", context.Background(), withSlug("title")).Return(nil, errors.New("error!")).Once()
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postStorageMock := mocks.NewPostStorage(t)
+			tc.mock(postStorageMock)
+
+			actual, err := createPostWithSlug(context.Background(), postStorageMock, &entity.Post{Title: "Title"})
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to create post")
+				require.NotEmpty(t, actual, "post is empty")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "post is not nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"fmt"
+	"strings"
+)
+
+var _ TagService = (*tagService)(nil)
+
+type tagService struct {
+	storages Storages
+	logger   logging.Logger
+}
+
+func NewTagService(storages Storages, logger logging.Logger) *tagService {
+	return &tagService{storages, logger.Named("tagService")}
+}
+
+func (s *tagService) List(ctx context.Context) ([]entity.TagUsage, error) {
+	logger := s.logger.Named("List")
+
+	tags, err := s.storages.Tag.List(ctx)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list tags", "err", err)
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	logger.Info("successfully listed tags", "tags", tags)
+	return tags, nil
+}
+
+// normalizeTagNames trims and lowercases the names and drops duplicates keeping the order,
+// ErrInvalidTag is returned for blank names
+func normalizeTagNames(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return nil, ErrInvalidTag
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+
+	return normalized, nil
+}
+
+// ensureTags returns the tags with the names creating missing ones, it's empty if there are no names
+func ensureTags(ctx context.Context, storage TagStorage, names []string) ([]entity.Tag, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return []entity.Tag{}, nil
+	}
+
+	tags, err := storage.Ensure(ctx, names)
+	if err != nil {
+		if errs.IsCustom(err) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to ensure tags: %w", err)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagService_List(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	tags := []entity.TagUsage{
+		{Tag: entity.Tag{ID: uuid.NewString(), Name: "go"}, PostsCount: 2},
+		{Tag: entity.Tag{ID: uuid.NewString(), Name: "news"}, PostsCount: 0},
+	}
+
+	testCases := []struct {
+		name      string
+		mock      func(m *mocks.TagStorage)
+		expected  []entity.TagUsage
+		expectErr bool
+	}{
+		{
+			name: "List",
+			mock: func(m *mocks.TagStorage) {
+				m.On("List", context.Background()).Return(tags, nil)
+			},
+			expected: tags,
+		},
+		{
+			name: "List with unexpected error in storage",
+			mock: func(m *mocks.TagStorage) {
+				m.On("List", context.Background()).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tagStorageMock := mocks.NewTagStorage(t)
+			tc.mock(tagStorageMock)
+			storages := Storages{Tag: tagStorageMock}
+
+			tagService := NewTagService(storages, logger)
+			actual, err := tagService.List(context.Background())
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list tags")
+				require.Equal(t, tc.expected, actual, "tags are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "tags are not nil")
+			}
+		})
+	}
+}
+
+func TestNormalizeTagNames(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		input     []string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "Normalize",
+			input:    []string{" Go", "news", "GO", "go "},
+			expected: []string{"go", "news"},
+		},
+		{
+			name:     "Normalize empty",
+			input:    nil,
+			expected: []string{},
+		},
+		{
+			name:      "Normalize blank",
+			input:     []string{"go", "\t"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := normalizeTagNames(tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to normalize tag names")
+				require.Equal(t, tc.expected, actual, "names are not equal")
+			} else {
+				require.ErrorIs(t, err, ErrInvalidTag, "unexpected error")
+			}
+		})
+	}
+}
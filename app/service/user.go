@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"fmt"
+)
+
+var _ UserService = (*userService)(nil)
+
+type userService struct {
+	storages Storages
+	logger   logging.Logger
+}
+
+func NewUserService(storages Storages, logger logging.Logger) *userService {
+	return &userService{storages, logger.Named("userService")}
+}
+
+func (s *userService) List(ctx context.Context, opt ListUsersOpt) ([]entity.User, error) {
+	logger := s.logger.Named("List")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if opt.Role != "" && !opt.Role.IsValid() {
+		logger.Info("invalid role", "role", opt.Role)
+		return nil, ErrInvalidUserRole
+	}
+
+	users, err := s.storages.User.List(ctx, entity.UsersFilter{Role: opt.Role})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to list users", "err", err)
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	logger.Info("successfully listed users", "count", len(users))
+	return users, nil
+}
+
+func (s *userService) SetRole(ctx context.Context, opt SetUserRoleOpt) (*entity.User, error) {
+	logger := s.logger.Named("SetRole")
+
+	err := authorize(ctx, entity.UserRoleAdmin, entity.APIKeyScopeAdmin)
+	if err != nil {
+		logger.Info(err.Error())
+		return nil, err
+	}
+
+	if !opt.Role.IsValid() {
+		logger.Info("invalid role", "role", opt.Role)
+		return nil, ErrInvalidUserRole
+	}
+
+	if PrincipalFromContext(ctx).UserID == opt.UserID {
+		logger.Info("own role change", "userID", opt.UserID)
+		return nil, ErrChangeOwnRole
+	}
+
+	updatedUser, err := s.storages.User.UpdateRole(ctx, opt.UserID, opt.Role)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to update user role", "err", err)
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	logger.Info("successfully set user role", "userID", updatedUser.ID, "role", updatedUser.Role)
+	return updatedUser, nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service/mocks"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_List(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	adminCtx := principalCtx(uuid.NewString(), entity.UserRoleAdmin)
+	users := []entity.User{
+		{ID: uuid.NewString(), Email: "jane@example.com", Role: entity.UserRoleEditor},
+		{ID: uuid.NewString(), Email: "john@example.com", Role: entity.UserRoleEditor},
+	}
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		mock        func(m *mocks.UserStorage)
+		input       ListUsersOpt
+		expected    []entity.User
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "List",
+			ctx:  adminCtx,
+			mock: func(m *mocks.UserStorage) {
+				m.On("List", adminCtx, entity.UsersFilter{}).Return(users, nil)
+			},
+			expected: users,
+		},
+		{
+			name: "List by role",
+			ctx:  adminCtx,
+			mock: func(m *mocks.UserStorage) {
+				m.On("List", adminCtx, entity.UsersFilter{Role: entity.UserRoleEditor}).Return(users, nil)
+			},
+			input:    ListUsersOpt{Role: entity.UserRoleEditor},
+			expected: users,
+		},
+		{
+			name:        "List by invalid role",
+			ctx:         adminCtx,
+			input:       ListUsersOpt{Role: "root"},
+			expectedErr: ErrInvalidUserRole,
+			expectErr:   true,
+		},
+		{
+			name: "List with unexpected error in storage",
+			ctx:  adminCtx,
+			mock: func(m *mocks.UserStorage) {
+				m.On("List", adminCtx, entity.UsersFilter{}).Return(nil, errors.New("error!"))
+			},
+			expectErr: true,
+		},
+		{
+			name:        "List by editor",
+			ctx:         editorCtx,
+			expectedErr: ErrForbidden,
+			expectErr:   true,
+		},
+		{
+			name:        "List anonymously",
+			ctx:         context.Background(),
+			expectedErr: ErrAuthenticationRequired,
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userStorageMock := mocks.NewUserStorage(t)
+			if tc.mock != nil {
+				tc.mock(userStorageMock)
+			}
+			storages := Storages{User: userStorageMock}
+
+			userService := NewUserService(storages, logger)
+			actual, err := userService.List(tc.ctx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to list users")
+				require.Equal(t, tc.expected, actual, "users are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "users are not nil")
+			}
+		})
+	}
+}
+
+func TestUserService_SetRole(t *testing.T) {
+	t.Parallel()
+
+	logger, err := logging.NewZapLogger()
+	if err != nil {
+		log.Fatal(err)
+		os.Exit(1)
+	}
+
+	adminID := uuid.NewString()
+	adminCtx := principalCtx(adminID, entity.UserRoleAdmin)
+	userID := uuid.NewString()
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		mock        func(m *mocks.UserStorage)
+		input       SetUserRoleOpt
+		expected    *entity.User
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name: "SetRole",
+			ctx:  adminCtx,
+			mock: func(m *mocks.UserStorage) {
+				m.On("UpdateRole", adminCtx, userID, entity.UserRoleEditor).
+					Return(&entity.User{ID: userID, Role: entity.UserRoleEditor}, nil)
+			},
+			input:    SetUserRoleOpt{UserID: userID, Role: entity.UserRoleEditor},
+			expected: &entity.User{ID: userID, Role: entity.UserRoleEditor},
+		},
+		{
+			name: "SetRole of non-existent user",
+			ctx:  adminCtx,
+			mock: func(m *mocks.UserStorage) {
+				m.On("UpdateRole", adminCtx, userID, entity.UserRoleEditor).Return(nil, ErrGetUserNotFound)
+			},
+			input:       SetUserRoleOpt{UserID: userID, Role: entity.UserRoleEditor},
+			expectedErr: ErrGetUserNotFound,
+			expectErr:   true,
+		},
+		{
+			name: "SetRole with unexpected error in storage",
+			ctx:  adminCtx,
+			mock: func(m *mocks.UserStorage) {
+				m.On("UpdateRole", adminCtx, userID, entity.UserRoleEditor).Return(nil, errors.New("error!"))
+			},
+			input:     SetUserRoleOpt{UserID: userID, Role: entity.UserRoleEditor},
+			expectErr: true,
+		},
+		{
+			name:        "SetRole with invalid role",
+			ctx:         adminCtx,
+			input:       SetUserRoleOpt{UserID: userID, Role: "root"},
+			expectedErr: ErrInvalidUserRole,
+			expectErr:   true,
+		},
+		{
+			name:        "SetRole of yourself",
+			ctx:         adminCtx,
+			input:       SetUserRoleOpt{UserID: adminID, Role: entity.UserRoleReader},
+			expectedErr: ErrChangeOwnRole,
+			expectErr:   true,
+		},
+		{
+			name:        "SetRole by editor",
+			ctx:         editorCtx,
+			input:       SetUserRoleOpt{UserID: userID, Role: entity.UserRoleAdmin},
+			expectedErr: ErrForbidden,
+			expectErr:   true,
+		},
+		{
+			name:        "SetRole anonymously",
+			ctx:         context.Background(),
+			input:       SetUserRoleOpt{UserID: userID, Role: entity.UserRoleAdmin},
+			expectedErr: ErrAuthenticationRequired,
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userStorageMock := mocks.NewUserStorage(t)
+			if tc.mock != nil {
+				tc.mock(userStorageMock)
+			}
+			storages := Storages{User: userStorageMock}
+
+			userService := NewUserService(storages, logger)
+			actual, err := userService.SetRole(tc.ctx, tc.input)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to set user role")
+				require.Equal(t, tc.expected, actual, "users are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
+				require.Nil(t, actual, "user is not nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.APIKeyStorage = (*apiKeyStorage)(nil)
+
+type apiKeyStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewAPIKeyStorage(db *gorm.DB, logger logging.Logger) *apiKeyStorage {
+	return &apiKeyStorage{db, logger.Named("apiKeyStorage")}
+}
+
+func (s *apiKeyStorage) Create(ctx context.Context, key *entity.APIKey) (*entity.APIKey, error) {
+	logger := s.logger.Named("Create")
+
+	err := s.db.Create(key).Error
+	if err != nil {
+		logger.Error("failed to create API key", "err", err)
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	logger.Info("successfully created API key", "keyID", key.ID)
+	return key, nil
+}
+
+func (s *apiKeyStorage) List(ctx context.Context) ([]entity.APIKey, error) {
+	logger := s.logger.Named("List")
+
+	var keys []entity.APIKey
+	err := s.db.
+		Order("created_at DESC, id DESC").
+		Find(&keys).Error
+	if err != nil {
+		logger.Error("failed to list API keys", "err", err)
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	logger.Info("successfully listed API keys", "count", len(keys))
+	return keys, nil
+}
+
+func (s *apiKeyStorage) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	logger := s.logger.Named("GetByHash")
+
+	var key entity.APIKey
+	err := s.db.
+		Where(entity.APIKey{KeyHash: keyHash}).
+		First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("API key not found")
+		return nil, service.ErrGetAPIKeyNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get API key", "err", err)
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	logger.Info("successfully got API key", "keyID", key.ID)
+	return &key, nil
+}
+
+func (s *apiKeyStorage) Revoke(ctx context.Context, id string, revokedAt time.Time) (*entity.APIKey, error) {
+	logger := s.logger.Named("Revoke")
+
+	var key entity.APIKey
+	result := s.db.
+		Model(&key).
+		Clauses(clause.Returning{}).
+		Where(entity.APIKey{ID: id}).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", revokedAt))
+	if result.Error != nil {
+		logger.Error("failed to revoke API key", "err", result.Error)
+		return nil, fmt.Errorf("failed to revoke API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("API key not found", "id", id)
+		return nil, service.ErrGetAPIKeyNotFound
+	}
+
+	logger.Info("successfully revoked API key", "keyID", key.ID)
+	return &key, nil
+}
+
+func (s *apiKeyStorage) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	logger := s.logger.Named("Touch")
+
+	// UpdateColumn keeps UpdatedAt, usage isn't a change of the key
+	err := s.db.
+		Model(&entity.APIKey{}).
+		Where(entity.APIKey{ID: id}).
+		UpdateColumn("last_used_at", usedAt).Error
+	if err != nil {
+		logger.Error("failed to touch API key", "err", err)
+		return fmt.Errorf("failed to touch API key: %w", err)
+	}
+
+	logger.Info("successfully touched API key", "keyID", id)
+	return nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createAPIKey(t *testing.T, name string) *entity.APIKey {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM api_keys;").Error
+		require.NoError(t, err, "failed to clear api_keys table")
+	})
+
+	key, err := apiKeysStorage.Create(context.Background(), &entity.APIKey{
+		Name:    name,
+		Prefix:  "nak_prefix",
+		KeyHash: uuid.NewString(),
+		Scopes:  []entity.APIKeyScope{entity.APIKeyScopePostsRead, entity.APIKeyScopePostsWrite},
+	})
+	require.NoError(t, err, "failed to create API key")
+
+	return key
+}
+
+func TestAPIKeyStorage_Create(t *testing.T) {
+	key := createAPIKey(t, "partner")
+	require.NotEmpty(t, key.ID, "ID is empty")
+
+	actual, err := apiKeysStorage.GetByHash(context.Background(), key.KeyHash)
+	require.NoError(t, err, "failed to get API key")
+	require.Equal(t, key.ID, actual.ID, "IDs are not equal")
+	require.Equal(t, key.Scopes, actual.Scopes, "scopes are not equal")
+
+	_, err = apiKeysStorage.GetByHash(context.Background(), "unknown")
+	require.ErrorIs(t, err, service.ErrGetAPIKeyNotFound, "unexpected error")
+}
+
+func TestAPIKeyStorage_List(t *testing.T) {
+	first := createAPIKey(t, "first")
+	second := createAPIKey(t, "second")
+
+	actual, err := apiKeysStorage.List(context.Background())
+	require.NoError(t, err, "failed to list API keys")
+	require.Len(t, actual, 2, "unexpected number of API keys")
+	require.Equal(t, second.ID, actual[0].ID, "API keys aren't ordered from the newest")
+	require.Equal(t, first.ID, actual[1].ID, "API keys aren't ordered from the newest")
+}
+
+func TestAPIKeyStorage_Revoke(t *testing.T) {
+	key := createAPIKey(t, "partner")
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+
+	actual, err := apiKeysStorage.Revoke(context.Background(), key.ID, revokedAt)
+	require.NoError(t, err, "failed to revoke API key")
+	require.NotNil(t, actual.RevokedAt, "API key isn't revoked")
+	require.True(t, revokedAt.Equal(*actual.RevokedAt), "revocation times are not equal")
+	require.Equal(t, key.Name, actual.Name, "revoked key isn't returned")
+
+	actual, err = apiKeysStorage.Revoke(context.Background(), key.ID, time.Now())
+	require.NoError(t, err, "failed to revoke API key again")
+	require.True(t, revokedAt.Equal(*actual.RevokedAt), "revocation time is changed")
+
+	_, err = apiKeysStorage.Revoke(context.Background(), uuid.NewString(), time.Now())
+	require.ErrorIs(t, err, service.ErrGetAPIKeyNotFound, "unexpected error")
+}
+
+func TestAPIKeyStorage_Touch(t *testing.T) {
+	key := createAPIKey(t, "partner")
+	usedAt := time.Now().Truncate(time.Microsecond)
+
+	err := apiKeysStorage.Touch(context.Background(), key.ID, usedAt)
+	require.NoError(t, err, "failed to touch API key")
+
+	actual, err := apiKeysStorage.GetByHash(context.Background(), key.KeyHash)
+	require.NoError(t, err, "failed to get API key")
+	require.NotNil(t, actual.LastUsedAt, "last usage isn't recorded")
+	require.True(t, usedAt.Equal(*actual.LastUsedAt), "last usage times are not equal")
+}
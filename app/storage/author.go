@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.AuthorStorage = (*authorStorage)(nil)
+
+type authorStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewAuthorStorage(db *gorm.DB, logger logging.Logger) *authorStorage {
+	return &authorStorage{db, logger.Named("authorStorage")}
+}
+
+func (s *authorStorage) Create(ctx context.Context, author *entity.Author) (*entity.Author, error) {
+	logger := s.logger.Named("Create")
+
+	// the unique slug index makes concurrent requests with the same slug create only one author
+	result := s.db.
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoNothing: true}).
+		Create(author)
+	if result.Error != nil {
+		logger.Error("failed to create author", "err", result.Error)
+		return nil, fmt.Errorf("failed to create author: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("author slug already exists", "slug", author.Slug)
+		return nil, service.ErrCreateAuthorSlugExists
+	}
+
+	logger.Info("successfully created author", "author", author)
+	return author, nil
+}
+
+func (s *authorStorage) List(ctx context.Context) ([]entity.Author, error) {
+	logger := s.logger.Named("List")
+
+	var authors []entity.Author
+	err := s.db.
+		Order("name, id").
+		Find(&authors).Error
+	if err != nil {
+		logger.Error("failed to list authors", "err", err)
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+
+	logger.Info("successfully listed authors", "authors", authors)
+	return authors, nil
+}
+
+func (s *authorStorage) Get(ctx context.Context, id string) (*entity.Author, error) {
+	logger := s.logger.Named("Get")
+
+	var author entity.Author
+	err := s.db.
+		Where(entity.Author{ID: id}).
+		First(&author).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("author not found", "id", id)
+		return nil, service.ErrGetAuthorNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get author", "err", err)
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+
+	logger.Info("successfully got author", "author", author)
+	return &author, nil
+}
+
+func (s *authorStorage) GetMany(ctx context.Context, ids []string) ([]entity.Author, error) {
+	logger := s.logger.Named("GetMany")
+
+	var authors []entity.Author
+	err := s.db.
+		Where("id IN ?", ids).
+		Find(&authors).Error
+	if err != nil {
+		logger.Error("failed to get authors", "err", err)
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+
+	logger.Info("successfully got authors", "authors", authors)
+	return authors, nil
+}
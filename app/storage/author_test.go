@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createAuthors creates authors with the names, their slugs are the lowercased names
+func createAuthors(t *testing.T, names ...string) []*entity.Author {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM posts;").Error
+		require.NoError(t, err, "failed to clear posts table")
+		err = db.Exec("DELETE FROM authors;").Error
+		require.NoError(t, err, "failed to clear authors table")
+	})
+
+	authors := make([]*entity.Author, 0, len(names))
+	for _, name := range names {
+		author, err := authorsStorage.Create(context.Background(), &entity.Author{Name: name, Slug: strings.ToLower(name)})
+		require.NoError(t, err, "failed to create author")
+		authors = append(authors, author)
+	}
+
+	return authors
+}
+
+func TestAuthorStorage_Create(t *testing.T) {
+	authors := createAuthors(t, "jane")
+	require.NotEmpty(t, authors[0].ID, "ID is empty")
+
+	_, err := authorsStorage.Create(context.Background(), &entity.Author{Name: "Jane", Slug: "jane"})
+	require.ErrorIs(t, err, service.ErrCreateAuthorSlugExists, "unexpected error")
+}
+
+func TestAuthorStorage_List(t *testing.T) {
+	createAuthors(t, "john", "jane")
+
+	actual, err := authorsStorage.List(context.Background())
+	require.NoError(t, err, "failed to list authors")
+	require.Len(t, actual, 2, "len is not equal")
+	require.Equal(t, "jane", actual[0].Name, "authors are not ordered by name")
+}
+
+func TestAuthorStorage_Get(t *testing.T) {
+	authors := createAuthors(t, "jane")
+
+	actual, err := authorsStorage.Get(context.Background(), authors[0].ID)
+	require.NoError(t, err, "failed to get author")
+	require.Equal(t, "jane", actual.Slug, "slugs are not equal")
+
+	_, err = authorsStorage.Get(context.Background(), "00000000-0000-0000-0000-000000000000")
+	require.ErrorIs(t, err, service.ErrGetAuthorNotFound, "unexpected error")
+}
+
+func TestAuthorStorage_GetMany(t *testing.T) {
+	authors := createAuthors(t, "jane", "john")
+
+	actual, err := authorsStorage.GetMany(context.Background(), []string{authors[0].ID, "00000000-0000-0000-0000-000000000000"})
+	require.NoError(t, err, "failed to get authors")
+	require.Len(t, actual, 1, "unknown author is not skipped")
+	require.Equal(t, authors[0].ID, actual[0].ID, "IDs are not equal")
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.CategoryStorage = (*categoryStorage)(nil)
+
+// categorySubtreeQuery selects IDs of the category passed as the only argument and all its descendants
+const categorySubtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE id = ?
+		UNION ALL
+		SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+	)
+	SELECT id FROM subtree`
+
+type categoryStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewCategoryStorage(db *gorm.DB, logger logging.Logger) *categoryStorage {
+	return &categoryStorage{db, logger.Named("categoryStorage")}
+}
+
+func (s *categoryStorage) Create(ctx context.Context, category *entity.Category) (*entity.Category, error) {
+	logger := s.logger.Named("Create")
+
+	err := s.db.Create(category).Error
+	if err != nil {
+		logger.Error("failed to create category", "err", err)
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	logger.Info("successfully created category", "category", category)
+	return category, nil
+}
+
+func (s *categoryStorage) List(ctx context.Context) ([]entity.Category, error) {
+	logger := s.logger.Named("List")
+
+	var categories []entity.Category
+	err := s.db.
+		Order("name, id").
+		Find(&categories).Error
+	if err != nil {
+		logger.Error("failed to list categories", "err", err)
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+
+	logger.Info("successfully listed categories", "categories", categories)
+	return categories, nil
+}
+
+func (s *categoryStorage) Get(ctx context.Context, id string) (*entity.Category, error) {
+	logger := s.logger.Named("Get")
+
+	var category entity.Category
+	err := s.db.
+		Preload("Children", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name, id")
+		}).
+		Where(entity.Category{ID: id}).
+		First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("category not found", "id", id)
+		return nil, service.ErrGetCategoryNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get category", "err", err)
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	logger.Info("successfully got category", "category", category)
+	return &category, nil
+}
+
+func (s *categoryStorage) Update(ctx context.Context, id string, update entity.CategoryUpdate) (*entity.Category, error) {
+	logger := s.logger.Named("Update")
+
+	// a map is used, so a nil parent is written too instead of being skipped
+	result := s.db.
+		Model(&entity.Category{}).
+		Where(entity.Category{ID: id}).
+		Updates(map[string]interface{}{
+			"name":      update.Name,
+			"parent_id": update.ParentID,
+		})
+	if result.Error != nil {
+		logger.Error("failed to update category", "err", result.Error)
+		return nil, fmt.Errorf("failed to update category: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("category not found", "id", id)
+		return nil, service.ErrGetCategoryNotFound
+	}
+	logger.Debug("updated category")
+
+	updatedCategory, err := s.Get(ctx, id)
+	if err != nil {
+		logger.Error("failed to get updated category", "err", err)
+		return nil, fmt.Errorf("failed to get updated category: %w", err)
+	}
+
+	logger.Info("successfully updated category", "updatedCategory", updatedCategory)
+	return updatedCategory, nil
+}
+
+func (s *categoryStorage) Delete(ctx context.Context, id string) error {
+	logger := s.logger.Named("Delete")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// the lock keeps children and posts from being added to the category while it's checked
+		var category entity.Category
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(entity.Category{ID: id}).
+			First(&category).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return service.ErrGetCategoryNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+
+		var childrenCount int64
+		err = tx.
+			Model(&entity.Category{}).
+			Where("parent_id = ?", id).
+			Count(&childrenCount).Error
+		if err != nil {
+			return fmt.Errorf("failed to count subcategories: %w", err)
+		}
+
+		// posts in the trash are counted too, as they can be restored
+		var postsCount int64
+		err = tx.
+			Unscoped().
+			Model(&entity.Post{}).
+			Where("category_id = ?", id).
+			Count(&postsCount).Error
+		if err != nil {
+			return fmt.Errorf("failed to count posts: %w", err)
+		}
+
+		if childrenCount > 0 || postsCount > 0 {
+			return service.ErrDeleteCategoryNotEmpty
+		}
+
+		err = tx.Delete(&category).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete category: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to delete category", "err", err)
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	logger.Info("successfully deleted category", "id", id)
+	return nil
+}
+
+func (s *categoryStorage) SubtreeIDs(ctx context.Context, id string) ([]string, error) {
+	logger := s.logger.Named("SubtreeIDs")
+
+	var ids []string
+	err := s.db.Raw(categorySubtreeQuery, id).Scan(&ids).Error
+	if err != nil {
+		logger.Error("failed to get category subtree", "err", err)
+		return nil, fmt.Errorf("failed to get category subtree: %w", err)
+	}
+
+	logger.Info("successfully got category subtree", "ids", ids)
+	return ids, nil
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createCategoryTree creates World > Europe > France and a separate Sport category
+func createCategoryTree(t *testing.T) map[string]*entity.Category {
+	t.Cleanup(func() {
+		// parents can't be deleted before their children, so the tree is truncated with posts referring to it
+		err := db.Exec("TRUNCATE categories CASCADE;").Error
+		require.NoError(t, err, "failed to clear categories table")
+	})
+
+	categories := make(map[string]*entity.Category)
+	create := func(name string, parent *entity.Category) {
+		category := &entity.Category{Name: name}
+		if parent != nil {
+			category.ParentID = &parent.ID
+		}
+
+		created, err := categoriesStorage.Create(context.Background(), category)
+		require.NoError(t, err, "failed to create category")
+		categories[name] = created
+	}
+	create("World", nil)
+	create("Europe", categories["World"])
+	create("France", categories["Europe"])
+	create("Sport", nil)
+
+	return categories
+}
+
+func TestCategoryStorage_Get(t *testing.T) {
+	categories := createCategoryTree(t)
+
+	actual, err := categoriesStorage.Get(context.Background(), categories["World"].ID)
+	require.NoError(t, err, "failed to get category")
+	require.Len(t, actual.Children, 1, "children are not loaded")
+	require.Equal(t, categories["Europe"].ID, actual.Children[0].ID, "IDs are not equal")
+
+	_, err = categoriesStorage.Get(context.Background(), "00000000-0000-0000-0000-000000000000")
+	require.ErrorIs(t, err, service.ErrGetCategoryNotFound, "unexpected error")
+}
+
+func TestCategoryStorage_SubtreeIDs(t *testing.T) {
+	categories := createCategoryTree(t)
+
+	actual, err := categoriesStorage.SubtreeIDs(context.Background(), categories["World"].ID)
+	require.NoError(t, err, "failed to get subtree")
+	require.ElementsMatch(t, []string{categories["World"].ID, categories["Europe"].ID, categories["France"].ID}, actual, "subtree is not equal")
+
+	actual, err = categoriesStorage.SubtreeIDs(context.Background(), "00000000-0000-0000-0000-000000000000")
+	require.NoError(t, err, "failed to get subtree")
+	require.Empty(t, actual, "subtree of unknown category is not empty")
+}
+
+func TestCategoryStorage_Update(t *testing.T) {
+	categories := createCategoryTree(t)
+
+	updated, err := categoriesStorage.Update(context.Background(), categories["France"].ID, entity.CategoryUpdate{Name: "France"})
+	require.NoError(t, err, "failed to update category")
+	require.Nil(t, updated.ParentID, "category is not moved to top-level")
+
+	updated, err = categoriesStorage.Update(context.Background(), categories["France"].ID, entity.CategoryUpdate{
+		Name:     "Europe/France",
+		ParentID: &categories["Europe"].ID,
+	})
+	require.NoError(t, err, "failed to update category")
+	require.Equal(t, "Europe/France", updated.Name, "names are not equal")
+	require.Equal(t, categories["Europe"].ID, *updated.ParentID, "parents are not equal")
+
+	_, err = categoriesStorage.Update(context.Background(), "00000000-0000-0000-0000-000000000000", entity.CategoryUpdate{Name: "name"})
+	require.ErrorIs(t, err, service.ErrGetCategoryNotFound, "unexpected error")
+}
+
+func TestCategoryStorage_Delete(t *testing.T) {
+	categories := createCategoryTree(t)
+
+	// a category with children can't be deleted
+	err := categoriesStorage.Delete(context.Background(), categories["Europe"].ID)
+	require.ErrorIs(t, err, service.ErrDeleteCategoryNotEmpty, "unexpected error")
+
+	// a category with a post in the trash can't be deleted
+	post, err := storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", CategoryID: &categories["France"].ID})
+	require.NoError(t, err, "failed to create post")
+	err = storage.Delete(context.Background(), post.ID, 0)
+	require.NoError(t, err, "failed to delete post")
+
+	err = categoriesStorage.Delete(context.Background(), categories["France"].ID)
+	require.ErrorIs(t, err, service.ErrDeleteCategoryNotEmpty, "unexpected error")
+
+	err = categoriesStorage.Delete(context.Background(), categories["Sport"].ID)
+	require.NoError(t, err, "failed to delete category")
+
+	err = categoriesStorage.Delete(context.Background(), categories["Sport"].ID)
+	require.ErrorIs(t, err, service.ErrGetCategoryNotFound, "unexpected error")
+}
+
+func TestPostStorage_ListByCategory(t *testing.T) {
+	categories := createCategoryTree(t)
+
+	for _, name := range []string{"World", "France", "Sport"} {
+		_, err := storage.Create(context.Background(), &entity.Post{Title: name, Content: "content", CategoryID: &categories[name].ID})
+		require.NoError(t, err, "failed to create post")
+	}
+	_, err := storage.Create(context.Background(), &entity.Post{Title: "none", Content: "content"})
+	require.NoError(t, err, "failed to create post")
+
+	testCases := []struct {
+		name     string
+		category string
+		expected []string
+	}{
+		{
+			name:     "List with descendants",
+			category: "World",
+			expected: []string{"France", "World"},
+		},
+		{
+			name:     "List of category without posts",
+			category: "Europe",
+			expected: []string{"France"},
+		},
+		{
+			name:     "List of leaf category",
+			category: "Sport",
+			expected: []string{"Sport"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			posts, err := storage.List(context.Background(), entity.PostsFilter{
+				CategoryID: categories[tc.category].ID,
+				Sort:       entity.PostsSortTitle,
+			})
+			require.NoError(t, err, "failed to list posts")
+
+			titles := make([]string, 0, len(posts))
+			for _, post := range posts {
+				titles = append(titles, post.Title)
+			}
+			require.Equal(t, tc.expected, titles, "posts are not equal")
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.CommentStorage = (*commentStorage)(nil)
+
+type commentStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewCommentStorage(db *gorm.DB, logger logging.Logger) *commentStorage {
+	return &commentStorage{db, logger.Named("commentStorage")}
+}
+
+func (s *commentStorage) Create(ctx context.Context, comment *entity.Comment) (*entity.Comment, error) {
+	logger := s.logger.Named("Create")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(comment).Error
+		if err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+		if !comment.IsCounted() {
+			return nil
+		}
+
+		return changePostCommentsCount(tx, comment.PostID, 1)
+	})
+	if err != nil {
+		logger.Error("failed to create comment", "err", err)
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	logger.Info("successfully created comment", "comment", comment)
+	return comment, nil
+}
+
+func (s *commentStorage) List(ctx context.Context, filter entity.CommentsFilter) ([]entity.Comment, error) {
+	logger := s.logger.Named("List")
+
+	query := selectRepliesCount(s.db).Where("post_id = ?", filter.PostID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ParentID != nil {
+		query = query.Where("parent_id = ?", *filter.ParentID)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+	if filter.After != nil {
+		// row comparison keeps keyset pagination stable for comments created at the same time
+		query = query.Where("(created_at, id) > (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+	query = query.Order("created_at, id")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var comments []entity.Comment
+	err := query.Find(&comments).Error
+	if err != nil {
+		logger.Error("failed to list comments", "err", err)
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	logger.Info("successfully listed comments", "comments", comments)
+	return comments, nil
+}
+
+func (s *commentStorage) ListForModeration(ctx context.Context, filter entity.ModerationQueueFilter) ([]entity.Comment, error) {
+	logger := s.logger.Named("ListForModeration")
+
+	query := selectRepliesCount(s.db).Where("status = ? AND deleted_at IS NULL", filter.Status)
+	if filter.PostID != "" {
+		query = query.Where("post_id = ?", filter.PostID)
+	}
+	if filter.After != nil {
+		query = query.Where("(created_at, id) > (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+	query = query.Order("created_at, id")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var comments []entity.Comment
+	err := query.Find(&comments).Error
+	if err != nil {
+		logger.Error("failed to list comments", "err", err)
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	logger.Info("successfully listed comments", "comments", comments)
+	return comments, nil
+}
+
+func (s *commentStorage) Get(ctx context.Context, id string) (*entity.Comment, error) {
+	logger := s.logger.Named("Get")
+
+	var comment entity.Comment
+	err := selectRepliesCount(s.db).
+		Where(entity.Comment{ID: id}).
+		First(&comment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("comment not found", "id", id)
+		return nil, service.ErrGetCommentNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get comment", "err", err)
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	logger.Info("successfully got comment", "comment", comment)
+	return &comment, nil
+}
+
+func (s *commentStorage) Update(ctx context.Context, id string, update entity.CommentUpdate) (*entity.Comment, error) {
+	logger := s.logger.Named("Update")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		comment, err := lockComment(tx, id)
+		if err != nil {
+			return err
+		}
+		wasCounted := comment.IsCounted()
+
+		now := time.Now()
+		columns := make(map[string]interface{})
+		if update.Content != nil {
+			columns["content"] = *update.Content
+			columns["edited_at"] = now
+		}
+		if update.Status != nil {
+			comment.Status = *update.Status
+		}
+		if update.Moderation != nil {
+			update.Moderation.CommentID = id
+			err = tx.Create(update.Moderation).Error
+			if err != nil {
+				return fmt.Errorf("failed to create comment moderation: %w", err)
+			}
+
+			comment.Status = update.Moderation.Status
+			columns["moderation_reason"] = update.Moderation.Reason
+			columns["moderated_at"] = now
+		}
+		columns["status"] = comment.Status
+
+		err = tx.
+			Model(&entity.Comment{}).
+			Where(entity.Comment{ID: id}).
+			Updates(columns).Error
+		if err != nil {
+			return fmt.Errorf("failed to update comment: %w", err)
+		}
+
+		switch {
+		case wasCounted && !comment.IsCounted():
+			return changePostCommentsCount(tx, comment.PostID, -1)
+		case !wasCounted && comment.IsCounted():
+			return changePostCommentsCount(tx, comment.PostID, 1)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to update comment", "err", err)
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	updatedComment, err := s.Get(ctx, id)
+	if err != nil {
+		logger.Error("failed to get updated comment", "err", err)
+		return nil, fmt.Errorf("failed to get updated comment: %w", err)
+	}
+
+	logger.Info("successfully updated comment", "updatedComment", updatedComment)
+	return updatedComment, nil
+}
+
+func (s *commentStorage) Delete(ctx context.Context, id string) error {
+	logger := s.logger.Named("Delete")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		comment, err := lockComment(tx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Model(&entity.Comment{}).
+			Where(entity.Comment{ID: id}).
+			Updates(map[string]interface{}{
+				"content":    "",
+				"deleted_at": time.Now(),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if !comment.IsCounted() {
+			return nil
+		}
+
+		return changePostCommentsCount(tx, comment.PostID, -1)
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to delete comment", "err", err)
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	logger.Info("successfully deleted comment")
+	return nil
+}
+
+func (s *commentStorage) ListModerations(ctx context.Context, commentID string) ([]entity.CommentModeration, error) {
+	logger := s.logger.Named("ListModerations")
+
+	var moderations []entity.CommentModeration
+	err := s.db.
+		Where(entity.CommentModeration{CommentID: commentID}).
+		Order("created_at DESC").
+		Find(&moderations).Error
+	if err != nil {
+		logger.Error("failed to list comment moderations", "err", err)
+		return nil, fmt.Errorf("failed to list comment moderations: %w", err)
+	}
+
+	logger.Info("successfully listed comment moderations", "moderations", moderations)
+	return moderations, nil
+}
+
+// lockComment keeps concurrent changes of the comment from changing the comments count twice,
+// deleted comments can't be changed
+func lockComment(tx *gorm.DB, id string) (*entity.Comment, error) {
+	var comment entity.Comment
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&comment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, service.ErrGetCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// selectRepliesCount makes the query fill RepliesCount of the comments
+func selectRepliesCount(query *gorm.DB) *gorm.DB {
+	return query.
+		Model(&entity.Comment{}).
+		Select(`comments.*, (
+			SELECT COUNT(*) FROM comments AS replies
+			WHERE replies.parent_id = comments.id AND replies.status = ? AND replies.deleted_at IS NULL
+		) AS replies_count`, entity.CommentStatusApproved)
+}
+
+// changePostCommentsCount doesn't change the version and the update time of the post,
+// comments aren't a part of the post content
+func changePostCommentsCount(tx *gorm.DB, postID string, delta int) error {
+	err := tx.
+		Model(&entity.Post{}).
+		Where("id = ?", postID).
+		UpdateColumn("comments_count", gorm.Expr("comments_count + ?", delta)).Error
+	if err != nil {
+		return fmt.Errorf("failed to change post comments count: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createCommentedPost creates a post to comment, its comments are deleted with it
+func createCommentedPost(t *testing.T) *entity.Post {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM posts;").Error
+		require.NoError(t, err, "failed to clear posts table")
+	})
+
+	post, err := storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", Slug: "title"})
+	require.NoError(t, err, "failed to create post")
+
+	return post
+}
+
+func createComment(t *testing.T, postID string, parentID *string, content string) *entity.Comment {
+	return createCommentWithStatus(t, postID, parentID, content, entity.CommentStatusApproved)
+}
+
+func createCommentWithStatus(t *testing.T, postID string, parentID *string, content string, status entity.CommentStatus) *entity.Comment {
+	comment, err := commentsStorage.Create(context.Background(), &entity.Comment{
+		PostID:     postID,
+		ParentID:   parentID,
+		AuthorName: "Jane",
+		Content:    content,
+		TokenHash:  "hash",
+		Status:     status,
+	})
+	require.NoError(t, err, "failed to create comment")
+
+	return comment
+}
+
+func requireCommentsCount(t *testing.T, postID string, expected int) {
+	post, err := storage.Get(context.Background(), postID)
+	require.NoError(t, err, "failed to get post")
+	require.Equal(t, expected, post.CommentsCount, "unexpected comments count")
+}
+
+func TestCommentStorage_Create(t *testing.T) {
+	post := createCommentedPost(t)
+
+	comment := createComment(t, post.ID, nil, "first")
+	require.NotEmpty(t, comment.ID, "ID is empty")
+	createComment(t, post.ID, &comment.ID, "reply")
+
+	requireCommentsCount(t, post.ID, 2)
+
+	// comments don't change the post
+	actual, err := storage.Get(context.Background(), post.ID)
+	require.NoError(t, err, "failed to get post")
+	require.Equal(t, post.Version, actual.Version, "version is changed")
+}
+
+func TestCommentStorage_List(t *testing.T) {
+	post := createCommentedPost(t)
+
+	first := createComment(t, post.ID, nil, "first")
+	second := createComment(t, post.ID, nil, "second")
+	third := createComment(t, post.ID, nil, "third")
+	reply := createComment(t, post.ID, &first.ID, "reply")
+	createComment(t, post.ID, &reply.ID, "reply to reply")
+
+	actual, err := commentsStorage.List(context.Background(), entity.CommentsFilter{PostID: post.ID})
+	require.NoError(t, err, "failed to list comments")
+	require.Len(t, actual, 3, "replies are listed with top-level comments")
+	require.Equal(t, first.ID, actual[0].ID, "comments are not ordered by creation")
+	require.Equal(t, int64(1), actual[0].RepliesCount, "unexpected replies count")
+	require.Equal(t, int64(0), actual[1].RepliesCount, "unexpected replies count")
+
+	actual, err = commentsStorage.List(context.Background(), entity.CommentsFilter{PostID: post.ID, ParentID: &first.ID})
+	require.NoError(t, err, "failed to list replies")
+	require.Len(t, actual, 1, "len is not equal")
+	require.Equal(t, reply.ID, actual[0].ID, "IDs are not equal")
+	require.Equal(t, int64(1), actual[0].RepliesCount, "unexpected replies count")
+
+	actual, err = commentsStorage.List(context.Background(), entity.CommentsFilter{
+		PostID: post.ID,
+		Limit:  1,
+		After:  &entity.CommentsCursor{CreatedAt: first.CreatedAt, ID: first.ID},
+	})
+	require.NoError(t, err, "failed to list next page")
+	require.Len(t, actual, 1, "limit is not applied")
+	require.Equal(t, second.ID, actual[0].ID, "unexpected next page")
+
+	actual, err = commentsStorage.List(context.Background(), entity.CommentsFilter{
+		PostID: post.ID,
+		After:  &entity.CommentsCursor{CreatedAt: third.CreatedAt, ID: third.ID},
+	})
+	require.NoError(t, err, "failed to list last page")
+	require.Empty(t, actual, "comments after the last one are listed")
+}
+
+func TestCommentStorage_Update(t *testing.T) {
+	post := createCommentedPost(t)
+	comment := createComment(t, post.ID, nil, "first")
+
+	edited := "edited"
+	actual, err := commentsStorage.Update(context.Background(), comment.ID, entity.CommentUpdate{Content: &edited})
+	require.NoError(t, err, "failed to update comment")
+	require.Equal(t, "edited", actual.Content, "contents are not equal")
+	require.NotNil(t, actual.EditedAt, "edit time is not set")
+
+	requireCommentsCount(t, post.ID, 1)
+
+	// edits of pre-moderated comments hide them until approval
+	pending := entity.CommentStatusPending
+	actual, err = commentsStorage.Update(context.Background(), comment.ID, entity.CommentUpdate{Content: &edited, Status: &pending})
+	require.NoError(t, err, "failed to update comment")
+	require.Equal(t, entity.CommentStatusPending, actual.Status, "statuses are not equal")
+	requireCommentsCount(t, post.ID, 0)
+
+	_, err = commentsStorage.Update(context.Background(), "00000000-0000-0000-0000-000000000000", entity.CommentUpdate{Content: &edited})
+	require.ErrorIs(t, err, service.ErrGetCommentNotFound, "unexpected error")
+}
+
+func TestCommentStorage_Delete(t *testing.T) {
+	post := createCommentedPost(t)
+	comment := createComment(t, post.ID, nil, "first")
+	reply := createComment(t, post.ID, &comment.ID, "reply")
+	requireCommentsCount(t, post.ID, 2)
+
+	err := commentsStorage.Delete(context.Background(), comment.ID)
+	require.NoError(t, err, "failed to delete comment")
+	requireCommentsCount(t, post.ID, 1)
+
+	// the deleted comment stays as a placeholder for its replies
+	actual, err := commentsStorage.Get(context.Background(), comment.ID)
+	require.NoError(t, err, "failed to get deleted comment")
+	require.NotNil(t, actual.DeletedAt, "deletion time is not set")
+	require.Empty(t, actual.Content, "content is kept")
+	require.Equal(t, int64(1), actual.RepliesCount, "unexpected replies count")
+
+	_, err = commentsStorage.Get(context.Background(), reply.ID)
+	require.NoError(t, err, "reply is deleted")
+
+	err = commentsStorage.Delete(context.Background(), comment.ID)
+	require.ErrorIs(t, err, service.ErrGetCommentNotFound, "comment is deleted twice")
+	requireCommentsCount(t, post.ID, 1)
+
+	edited := "edited"
+	_, err = commentsStorage.Update(context.Background(), comment.ID, entity.CommentUpdate{Content: &edited})
+	require.ErrorIs(t, err, service.ErrGetCommentNotFound, "deleted comment is updated")
+
+	// not approved comments aren't counted, so their deletion doesn't change the count
+	pending := createCommentWithStatus(t, post.ID, nil, "pending", entity.CommentStatusPending)
+	err = commentsStorage.Delete(context.Background(), pending.ID)
+	require.NoError(t, err, "failed to delete comment")
+	requireCommentsCount(t, post.ID, 1)
+}
+
+func TestCommentStorage_Moderation(t *testing.T) {
+	post := createCommentedPost(t)
+	comment := createCommentWithStatus(t, post.ID, nil, "first", entity.CommentStatusPending)
+	approved := createComment(t, post.ID, nil, "second")
+	requireCommentsCount(t, post.ID, 1)
+
+	// pending comments and their replies aren't public
+	actual, err := commentsStorage.List(context.Background(), entity.CommentsFilter{PostID: post.ID, Status: entity.CommentStatusApproved})
+	require.NoError(t, err, "failed to list comments")
+	require.Len(t, actual, 1, "pending comment is listed")
+	createCommentWithStatus(t, post.ID, &approved.ID, "pending reply", entity.CommentStatusPending)
+	actual, err = commentsStorage.List(context.Background(), entity.CommentsFilter{PostID: post.ID, Status: entity.CommentStatusApproved})
+	require.NoError(t, err, "failed to list comments")
+	require.Equal(t, int64(0), actual[0].RepliesCount, "pending reply is counted")
+
+	queue, err := commentsStorage.ListForModeration(context.Background(), entity.ModerationQueueFilter{Status: entity.CommentStatusPending})
+	require.NoError(t, err, "failed to list moderation queue")
+	require.Len(t, queue, 2, "pending comments of all levels are not listed")
+	require.Equal(t, comment.ID, queue[0].ID, "queue is not ordered by creation")
+
+	moderated, err := commentsStorage.Update(context.Background(), comment.ID, entity.CommentUpdate{
+		Moderation: &entity.CommentModeration{Status: entity.CommentStatusApproved},
+	})
+	require.NoError(t, err, "failed to approve comment")
+	require.Equal(t, entity.CommentStatusApproved, moderated.Status, "statuses are not equal")
+	require.NotNil(t, moderated.ModeratedAt, "moderation time is not set")
+	requireCommentsCount(t, post.ID, 2)
+
+	moderated, err = commentsStorage.Update(context.Background(), comment.ID, entity.CommentUpdate{
+		Moderation: &entity.CommentModeration{Status: entity.CommentStatusRejected, Reason: "rude"},
+	})
+	require.NoError(t, err, "failed to reject comment")
+	require.Equal(t, "rude", moderated.ModerationReason, "reasons are not equal")
+	requireCommentsCount(t, post.ID, 1)
+
+	// rejecting a rejected comment doesn't change the count twice
+	_, err = commentsStorage.Update(context.Background(), comment.ID, entity.CommentUpdate{
+		Moderation: &entity.CommentModeration{Status: entity.CommentStatusSpam, Reason: "ads"},
+	})
+	require.NoError(t, err, "failed to mark comment as spam")
+	requireCommentsCount(t, post.ID, 1)
+
+	history, err := commentsStorage.ListModerations(context.Background(), comment.ID)
+	require.NoError(t, err, "failed to list moderations")
+	require.Len(t, history, 3, "len is not equal")
+	require.Equal(t, entity.CommentStatusSpam, history[0].Status, "history is not ordered from the newest")
+	require.Equal(t, "rude", history[1].Reason, "rejection reason is not kept")
+
+	queue, err = commentsStorage.ListForModeration(context.Background(), entity.ModerationQueueFilter{Status: entity.CommentStatusSpam, PostID: post.ID})
+	require.NoError(t, err, "failed to list moderation queue")
+	require.Len(t, queue, 1, "len is not equal")
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.IdempotencyKeyStorage = (*idempotencyKeyStorage)(nil)
+
+type idempotencyKeyStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewIdempotencyKeyStorage(db *gorm.DB, logger logging.Logger) *idempotencyKeyStorage {
+	return &idempotencyKeyStorage{db, logger.Named("idempotencyKeyStorage")}
+}
+
+func (s *idempotencyKeyStorage) Create(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, error) {
+	logger := s.logger.Named("Create")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("client_id = ? AND key = ? AND expires_at <= ?", key.ClientID, key.Key, time.Now()).
+			Delete(&entity.IdempotencyKey{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete expired idempotency key: %w", err)
+		}
+
+		// the primary key makes concurrent requests with the same key create it only once
+		result := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(key)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create idempotency key: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return service.ErrCreateIdempotencyKeyExists
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
+		logger.Error("failed to create idempotency key", "err", err)
+		return nil, fmt.Errorf("failed to create idempotency key: %w", err)
+	}
+
+	logger.Info("successfully created idempotency key", "key", key)
+	return key, nil
+}
+
+func (s *idempotencyKeyStorage) Get(ctx context.Context, clientID, key string) (*entity.IdempotencyKey, error) {
+	logger := s.logger.Named("Get")
+
+	var idempotencyKey entity.IdempotencyKey
+	err := s.db.
+		Where(entity.IdempotencyKey{ClientID: clientID, Key: key}).
+		First(&idempotencyKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("idempotency key not found", "clientID", clientID, "key", key)
+		return nil, service.ErrGetIdempotencyKeyNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get idempotency key", "err", err)
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	logger.Info("successfully got idempotency key", "clientID", clientID, "key", key)
+	return &idempotencyKey, nil
+}
+
+func (s *idempotencyKeyStorage) Complete(ctx context.Context, key *entity.IdempotencyKey) error {
+	logger := s.logger.Named("Complete")
+
+	key.Completed = true
+	result := s.db.
+		Model(&entity.IdempotencyKey{}).
+		Where(entity.IdempotencyKey{ClientID: key.ClientID, Key: key.Key}).
+		Select("completed", "response_status", "response_header", "response_body").
+		Updates(key)
+	if result.Error != nil {
+		logger.Error("failed to complete idempotency key", "err", result.Error)
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Info("idempotency key not found", "clientID", key.ClientID, "key", key.Key)
+		return service.ErrGetIdempotencyKeyNotFound
+	}
+
+	logger.Info("successfully completed idempotency key", "clientID", key.ClientID, "key", key.Key)
+	return nil
+}
+
+func (s *idempotencyKeyStorage) Delete(ctx context.Context, clientID, key string) error {
+	logger := s.logger.Named("Delete")
+
+	result := s.db.
+		Where(entity.IdempotencyKey{ClientID: clientID, Key: key}).
+		Delete(&entity.IdempotencyKey{})
+	if result.Error != nil {
+		logger.Error("failed to delete idempotency key", "err", result.Error)
+		return fmt.Errorf("failed to delete idempotency key: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		logger.Info("idempotency key not found", "clientID", clientID, "key", key)
+		return service.ErrGetIdempotencyKeyNotFound
+	}
+
+	logger.Info("successfully deleted idempotency key", "clientID", clientID, "key", key)
+	return nil
+}
+
+func (s *idempotencyKeyStorage) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	logger := s.logger.Named("DeleteExpired")
+
+	result := s.db.
+		Where("expires_at <= ?", now).
+		Delete(&entity.IdempotencyKey{})
+	if result.Error != nil {
+		logger.Error("failed to delete expired idempotency keys", "err", result.Error)
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+
+	logger.Info("successfully deleted expired idempotency keys", "deleted", result.RowsAffected)
+	return result.RowsAffected, nil
+}
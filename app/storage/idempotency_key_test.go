@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeyStorage(t *testing.T) {
+	newKey := func(expiresAt time.Time) *entity.IdempotencyKey {
+		return &entity.IdempotencyKey{
+			ClientID:    "client",
+			Key:         "key",
+			RequestHash: "hash",
+			ExpiresAt:   expiresAt,
+		}
+	}
+	tomorrow := time.Now().Add(24 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	testCases := []struct {
+		name        string
+		keyToCreate *entity.IdempotencyKey
+		test        func(t *testing.T)
+	}{
+		{
+			name:        "Create existing key",
+			keyToCreate: newKey(tomorrow),
+			test: func(t *testing.T) {
+				actual, err := idempotencyStorage.Create(context.Background(), newKey(tomorrow))
+				require.ErrorIs(t, err, service.ErrCreateIdempotencyKeyExists, "unexpected error")
+				require.Nil(t, actual, "key is not nil")
+			},
+		},
+		{
+			name:        "Create key of another client",
+			keyToCreate: newKey(tomorrow),
+			test: func(t *testing.T) {
+				key := newKey(tomorrow)
+				key.ClientID = "another client"
+
+				_, err := idempotencyStorage.Create(context.Background(), key)
+				require.NoError(t, err, "failed to create key")
+			},
+		},
+		{
+			name:        "Create expired key",
+			keyToCreate: newKey(yesterday),
+			test: func(t *testing.T) {
+				key := newKey(tomorrow)
+				key.RequestHash = "another hash"
+
+				_, err := idempotencyStorage.Create(context.Background(), key)
+				require.NoError(t, err, "failed to create key")
+
+				actual, err := idempotencyStorage.Get(context.Background(), "client", "key")
+				require.NoError(t, err, "failed to get key")
+				require.Equal(t, "another hash", actual.RequestHash, "hashes are not equal")
+				require.False(t, actual.Completed, "key is completed")
+			},
+		},
+		{
+			name:        "Get",
+			keyToCreate: newKey(tomorrow),
+			test: func(t *testing.T) {
+				actual, err := idempotencyStorage.Get(context.Background(), "client", "key")
+				require.NoError(t, err, "failed to get key")
+				require.Equal(t, "hash", actual.RequestHash, "hashes are not equal")
+				require.False(t, actual.Completed, "key is completed")
+			},
+		},
+		{
+			name:        "Get with wrong key",
+			keyToCreate: newKey(tomorrow),
+			test: func(t *testing.T) {
+				actual, err := idempotencyStorage.Get(context.Background(), "client", "wrong key")
+				require.ErrorIs(t, err, service.ErrGetIdempotencyKeyNotFound, "unexpected error")
+				require.Nil(t, actual, "key is not nil")
+			},
+		},
+		{
+			name:        "Complete",
+			keyToCreate: newKey(tomorrow),
+			test: func(t *testing.T) {
+				err := idempotencyStorage.Complete(context.Background(), &entity.IdempotencyKey{
+					ClientID:       "client",
+					Key:            "key",
+					ResponseStatus: 200,
+					ResponseHeader: map[string][]string{"Content-Type": {"application/json"}},
+					ResponseBody:   []byte(`{"post":{}}`),
+				})
+				require.NoError(t, err, "failed to complete key")
+
+				actual, err := idempotencyStorage.Get(context.Background(), "client", "key")
+				require.NoError(t, err, "failed to get key")
+				require.True(t, actual.Completed, "key is not completed")
+				require.Equal(t, 200, actual.ResponseStatus, "statuses are not equal")
+				require.Equal(t, []string{"application/json"}, actual.ResponseHeader["Content-Type"], "headers are not equal")
+				require.Equal(t, []byte(`{"post":{}}`), actual.ResponseBody, "bodies are not equal")
+				require.Equal(t, "hash", actual.RequestHash, "hashes are not equal")
+			},
+		},
+		{
+			name:        "Complete with wrong key",
+			keyToCreate: newKey(tomorrow),
+			test: func(t *testing.T) {
+				err := idempotencyStorage.Complete(context.Background(), &entity.IdempotencyKey{
+					ClientID: "client",
+					Key:      "wrong key",
+				})
+				require.ErrorIs(t, err, service.ErrGetIdempotencyKeyNotFound, "unexpected error")
+			},
+		},
+		{
+			name:        "Delete",
+			keyToCreate: newKey(tomorrow),
+			test: func(t *testing.T) {
+				err := idempotencyStorage.Delete(context.Background(), "client", "key")
+				require.NoError(t, err, "failed to delete key")
+
+				_, err = idempotencyStorage.Get(context.Background(), "client", "key")
+				require.ErrorIs(t, err, service.ErrGetIdempotencyKeyNotFound, "key is not deleted")
+			},
+		},
+		{
+			name:        "DeleteExpired",
+			keyToCreate: newKey(yesterday),
+			test: func(t *testing.T) {
+				key := newKey(tomorrow)
+				key.Key = "another key"
+				_, err := idempotencyStorage.Create(context.Background(), key)
+				require.NoError(t, err, "failed to create key")
+
+				deleted, err := idempotencyStorage.DeleteExpired(context.Background(), time.Now())
+				require.NoError(t, err, "failed to delete expired keys")
+				require.Equal(t, int64(1), deleted, "deleted counts are not equal")
+
+				_, err = idempotencyStorage.Get(context.Background(), "client", "another key")
+				require.NoError(t, err, "not expired key is deleted")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := db.Exec("DELETE FROM idempotency_keys;").Error
+				require.NoError(t, err, "failed to clear idempotency keys table")
+			})
+
+			_, err := idempotencyStorage.Create(context.Background(), tc.keyToCreate)
+			require.NoError(t, err, "failed to create key")
+
+			tc.test(t)
+		})
+	}
+}
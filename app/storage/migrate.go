@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"darkness8129/news-api/app/entity"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Migrate creates and updates tables of all entities in one transaction. Columns added to existing tables
+// get defaults for new rows, so the rows which existed before are backfilled here when they need other values.
+func Migrate(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// posts created before the lifecycle were public, while new posts start as drafts
+		backfillPostStatus := tx.Migrator().HasTable(&entity.Post{}) && !tx.Migrator().HasColumn(&entity.Post{}, "Status")
+
+		err := tx.AutoMigrate(&entity.Post{}, &entity.PostRevision{}, &entity.IdempotencyKey{}, &entity.Tag{}, &entity.Category{}, &entity.Author{}, &entity.PostAuthor{}, &entity.PostSlug{}, &entity.Comment{}, &entity.CommentModeration{}, &entity.User{}, &entity.RefreshToken{}, &entity.APIKey{}, &entity.OIDCAuthRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to automigrate: %w", err)
+		}
+
+		if backfillPostStatus {
+			err = tx.Exec("UPDATE posts SET status = ?, published_at = created_at", entity.PostStatusPublished).Error
+			if err != nil {
+				return fmt.Errorf("failed to backfill post status: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.OIDCAuthRequestStorage = (*oidcAuthRequestStorage)(nil)
+
+type oidcAuthRequestStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewOIDCAuthRequestStorage(db *gorm.DB, logger logging.Logger) *oidcAuthRequestStorage {
+	return &oidcAuthRequestStorage{db, logger.Named("oidcAuthRequestStorage")}
+}
+
+func (s *oidcAuthRequestStorage) Create(ctx context.Context, request *entity.OIDCAuthRequest) (*entity.OIDCAuthRequest, error) {
+	logger := s.logger.Named("Create")
+
+	err := s.db.
+		Where("expires_at <= ?", time.Now()).
+		Delete(&entity.OIDCAuthRequest{}).Error
+	if err != nil {
+		logger.Error("failed to delete expired OIDC auth requests", "err", err)
+		return nil, fmt.Errorf("failed to delete expired OIDC auth requests: %w", err)
+	}
+
+	err = s.db.Create(request).Error
+	if err != nil {
+		logger.Error("failed to create OIDC auth request", "err", err)
+		return nil, fmt.Errorf("failed to create OIDC auth request: %w", err)
+	}
+
+	logger.Info("successfully created OIDC auth request")
+	return request, nil
+}
+
+func (s *oidcAuthRequestStorage) Take(ctx context.Context, state string, now time.Time) (*entity.OIDCAuthRequest, error) {
+	logger := s.logger.Named("Take")
+
+	// deleting with returning makes concurrent callbacks with the same state succeed only once
+	var request entity.OIDCAuthRequest
+	result := s.db.
+		Clauses(clause.Returning{}).
+		Where("state = ? AND expires_at > ?", state, now).
+		Delete(&request)
+	if result.Error != nil {
+		logger.Error("failed to take OIDC auth request", "err", result.Error)
+		return nil, fmt.Errorf("failed to take OIDC auth request: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("OIDC auth request not found")
+		return nil, service.ErrTakeOIDCAuthRequestNotFound
+	}
+
+	logger.Info("successfully took OIDC auth request")
+	return &request, nil
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createOIDCAuthRequest(t *testing.T, expiresAt time.Time) *entity.OIDCAuthRequest {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM oidc_auth_requests;").Error
+		require.NoError(t, err, "failed to clear oidc_auth_requests table")
+	})
+
+	request, err := oidcRequestsStorage.Create(context.Background(), &entity.OIDCAuthRequest{
+		State:        uuid.NewString(),
+		CodeVerifier: "verifier",
+		Nonce:        "nonce",
+		ExpiresAt:    expiresAt,
+	})
+	require.NoError(t, err, "failed to create OIDC auth request")
+
+	return request
+}
+
+func TestOIDCAuthRequestStorage_Take(t *testing.T) {
+	request := createOIDCAuthRequest(t, time.Now().Add(time.Minute))
+
+	actual, err := oidcRequestsStorage.Take(context.Background(), request.State, time.Now())
+	require.NoError(t, err, "failed to take OIDC auth request")
+	require.Equal(t, request.CodeVerifier, actual.CodeVerifier, "code verifiers are not equal")
+	require.Equal(t, request.Nonce, actual.Nonce, "nonces are not equal")
+
+	_, err = oidcRequestsStorage.Take(context.Background(), request.State, time.Now())
+	require.ErrorIs(t, err, service.ErrTakeOIDCAuthRequestNotFound, "request is taken twice")
+
+	expired := createOIDCAuthRequest(t, time.Now().Add(time.Minute))
+	_, err = oidcRequestsStorage.Take(context.Background(), expired.State, time.Now().Add(time.Hour))
+	require.ErrorIs(t, err, service.ErrTakeOIDCAuthRequestNotFound, "expired request is taken")
+}
+
+func TestOIDCAuthRequestStorage_Create(t *testing.T) {
+	expired := createOIDCAuthRequest(t, time.Now().Add(-time.Minute))
+	createOIDCAuthRequest(t, time.Now().Add(time.Minute))
+
+	var count int64
+	err := db.Model(&entity.OIDCAuthRequest{}).Where("state = ?", expired.State).Count(&count).Error
+	require.NoError(t, err, "failed to count OIDC auth requests")
+	require.Zero(t, count, "expired request isn't deleted")
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"darkness8129/news-api/app/entity"
 	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/errs"
 	"darkness8129/news-api/packages/logging"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var _ service.PostStorage = (*postStorage)(nil)
@@ -25,21 +29,92 @@ func NewPostStorage(db *gorm.DB, logger logging.Logger) *postStorage {
 func (s *postStorage) Create(ctx context.Context, post *entity.Post) (*entity.Post, error) {
 	logger := s.logger.Named("Create")
 
-	err := s.db.Create(post).Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(post).Error
+		if err != nil {
+			return fmt.Errorf("failed to create post: %w", err)
+		}
+		if post.Slug == "" {
+			return nil
+		}
+
+		return reservePostSlug(tx, post.ID, post.Slug)
+	})
 	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
 		logger.Error("failed to create post", "err", err)
 		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
 
+	// bylines are created with author IDs only, so their authors are loaded afterwards
+	if len(post.Authors) > 0 {
+		err = s.loadAssociations([]*entity.Post{post})
+		if err != nil {
+			logger.Error("failed to load post associations", "err", err)
+			return nil, fmt.Errorf("failed to load post associations: %w", err)
+		}
+	}
+
 	logger.Info("successfully created post", "post", post)
 	return post, nil
 }
 
-func (s *postStorage) List(ctx context.Context) ([]entity.Post, error) {
+func (s *postStorage) List(ctx context.Context, filter entity.PostsFilter) ([]entity.Post, error) {
 	logger := s.logger.Named("List")
 
+	query := s.db
+	if filter.OnlyDeleted {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at <= ?", *filter.CreatedTo)
+	}
+	if filter.UpdatedSince != nil {
+		query = query.Where("updated_at >= ?", *filter.UpdatedSince)
+	}
+	if filter.TitleContains != "" {
+		query = query.Where("title ILIKE ?", "%"+escapeLike(filter.TitleContains)+"%")
+	}
+	if len(filter.Tags) > 0 {
+		query = query.Where("id IN (?)", postIDsWithTags(s.db, filter.Tags, filter.TagsMatchAll))
+	}
+	if filter.CategoryID != "" {
+		query = query.Where("category_id IN ("+categorySubtreeQuery+")", filter.CategoryID)
+	}
+	if filter.AuthorID != "" {
+		query = query.Where("id IN (SELECT post_id FROM post_authors WHERE author_id = ?)", filter.AuthorID)
+	}
+
+	column, direction := postsSortColumn(filter.Sort)
+	query = query.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction))
+	if filter.After != nil {
+		// row comparison keeps keyset pagination stable for posts with equal sort values
+		operator := "<"
+		if direction == "ASC" {
+			operator = ">"
+		}
+
+		query = query.Where(
+			fmt.Sprintf("(%s, id) %s (?, ?)", column, operator),
+			postsCursorValue(filter.Sort, filter.After), filter.After.ID,
+		)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
 	var posts []entity.Post
-	err := s.db.Find(&posts).Error
+	err := preloadPostAssociations(query).Find(&posts).Error
 	if err != nil {
 		logger.Error("failed to list posts", "err", err)
 		return nil, fmt.Errorf("failed to list posts: %w", err)
@@ -49,11 +124,56 @@ func (s *postStorage) List(ctx context.Context) ([]entity.Post, error) {
 	return posts, nil
 }
 
+func (s *postStorage) Search(ctx context.Context, filter entity.PostsSearchFilter) ([]entity.PostSearchResult, error) {
+	logger := s.logger.Named("Search")
+
+	// search_vector is built with the same text search configuration, so the GIN index is used
+	query := s.db.Raw(`
+		SELECT
+			posts.*,
+			ts_rank(posts.search_vector, q.query) AS rank,
+			ts_headline('english', posts.title, q.query, @headlineOptions) AS title_highlight,
+			ts_headline('english', posts.content, q.query, @headlineOptions) AS content_highlight
+		FROM posts, websearch_to_tsquery('english', @query) AS q(query)
+		WHERE posts.search_vector @@ q.query AND posts.deleted_at IS NULL
+			AND (@status = '' OR posts.status = @status)
+		ORDER BY rank DESC, posts.id
+		LIMIT @limit OFFSET @offset`,
+		map[string]interface{}{
+			"status":          filter.Status,
+			"query":           filter.Query,
+			"headlineOptions": "StartSel=<mark>, StopSel=</mark>, MaxFragments=2",
+			"limit":           filter.Limit,
+			"offset":          filter.Offset,
+		},
+	)
+
+	var results []entity.PostSearchResult
+	err := query.Scan(&results).Error
+	if err != nil {
+		logger.Error("failed to search posts", "err", err)
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	posts := make([]*entity.Post, 0, len(results))
+	for i := range results {
+		posts = append(posts, &results[i].Post)
+	}
+	err = s.loadAssociations(posts)
+	if err != nil {
+		logger.Error("failed to load post associations", "err", err)
+		return nil, fmt.Errorf("failed to load post associations: %w", err)
+	}
+
+	logger.Info("successfully searched posts", "results", results)
+	return results, nil
+}
+
 func (s *postStorage) Get(ctx context.Context, id string) (*entity.Post, error) {
 	logger := s.logger.Named("Get")
 
 	var post entity.Post
-	err := s.db.
+	err := preloadPostAssociations(s.db).
 		Where(entity.Post{ID: id}).
 		First(&post).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -69,17 +189,309 @@ func (s *postStorage) Get(ctx context.Context, id string) (*entity.Post, error)
 	return &post, nil
 }
 
-func (s *postStorage) Update(ctx context.Context, id string, post *entity.Post) (*entity.Post, error) {
+func (s *postStorage) GetBySlug(ctx context.Context, slug string) (*entity.Post, error) {
+	logger := s.logger.Named("GetBySlug")
+
+	var post entity.Post
+	err := preloadPostAssociations(s.db).
+		Where("id = (SELECT post_id FROM post_slugs WHERE slug = ?)", slug).
+		First(&post).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("post not found", "slug", slug)
+		return nil, service.ErrGetPostNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get post by slug", "err", err)
+		return nil, fmt.Errorf("failed to get post by slug: %w", err)
+	}
+
+	logger.Info("successfully got post by slug", "post", post)
+	return &post, nil
+}
+
+func (s *postStorage) Update(ctx context.Context, id string, update entity.PostUpdate) (*entity.Post, error) {
 	logger := s.logger.Named("Update")
 
-	err := s.db.
-		Where(entity.Post{ID: id}).
-		Updates(post).Error
+	var updatedPost entity.Post
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// the lock serializes concurrent updates, so revision numbers don't collide
+		var currentPost entity.Post
+		err := preloadPostAssociations(tx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(entity.Post{ID: id}).
+			First(&currentPost).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return service.ErrGetPostNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+
+		if update.Version != 0 && update.Version != currentPost.Version {
+			return service.ErrPostVersionMismatch
+		}
+
+		// a map is used, so empty values are written too instead of being skipped
+		columns := postUpdateColumns(update)
+		// the slug follows the title, so it isn't changed if the title is the same
+		if update.Slug != nil && update.Title != nil && *update.Title != currentPost.Title {
+			err = reservePostSlug(tx, id, *update.Slug)
+			if err != nil {
+				return err
+			}
+
+			columns["slug"] = *update.Slug
+		}
+		if len(columns) == 0 {
+			updatedPost = currentPost
+			return nil
+		}
+
+		result := tx.
+			Model(&entity.Post{}).
+			Where(entity.Post{ID: id}).
+			Updates(columns)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update post: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return service.ErrGetPostNotFound
+		}
+
+		if update.Tags != nil {
+			err = replacePostTags(tx, &currentPost, *update.Tags)
+			if err != nil {
+				return err
+			}
+		}
+		if update.Authors != nil {
+			err = replacePostAuthors(tx, id, *update.Authors)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = preloadPostAssociations(tx).
+			Where(entity.Post{ID: id}).
+			First(&updatedPost).Error
+		if err != nil {
+			return fmt.Errorf("failed to get updated post: %w", err)
+		}
+
+		if updatedPost.Title == currentPost.Title && updatedPost.Content == currentPost.Content {
+			return nil
+		}
+
+		return s.createRevision(tx, &currentPost, &updatedPost)
+	})
 	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return nil, err
+		}
+
 		logger.Error("failed to update post", "err", err)
 		return nil, fmt.Errorf("failed to update post: %w", err)
 	}
-	logger.Debug("updated post")
+
+	logger.Info("successfully updated post", "updatedPost", updatedPost)
+	return &updatedPost, nil
+}
+
+func postUpdateColumns(update entity.PostUpdate) map[string]interface{} {
+	columns := make(map[string]interface{})
+	if update.Title != nil {
+		columns["title"] = *update.Title
+	}
+	if update.Content != nil {
+		columns["content"] = *update.Content
+	}
+	if update.PublishAt != nil {
+		columns["publish_at"] = *update.PublishAt
+	} else if update.ClearPublishAt {
+		columns["publish_at"] = nil
+	}
+	if update.CategoryID != nil {
+		columns["category_id"] = *update.CategoryID
+	} else if update.ClearCategory {
+		columns["category_id"] = nil
+	}
+
+	// tags and bylines are stored in join tables, but they are a part of the post version too
+	if len(columns) > 0 || update.Tags != nil || update.Authors != nil {
+		columns["version"] = gorm.Expr("version + 1")
+	}
+
+	return columns
+}
+
+// replacePostTags links the post only with the tags, the tags must exist
+func replacePostTags(tx *gorm.DB, post *entity.Post, tags []entity.Tag) error {
+	association := tx.Model(post).Association("Tags")
+
+	var err error
+	if len(tags) == 0 {
+		err = association.Clear()
+	} else {
+		err = association.Replace(tags)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to replace post tags: %w", err)
+	}
+
+	return nil
+}
+
+// reservePostSlug reserves the slug for the post, a slug which the post had before is reused.
+// ErrPostSlugExists is returned if the slug belongs to another post.
+func reservePostSlug(tx *gorm.DB, postID, slug string) error {
+	// conflicts are skipped instead of failing, so an outer transaction isn't aborted
+	result := tx.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.PostSlug{Slug: slug, PostID: postID})
+	if result.Error != nil {
+		return fmt.Errorf("failed to create post slug: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var postSlug entity.PostSlug
+	err := tx.
+		Where(entity.PostSlug{Slug: slug}).
+		First(&postSlug).Error
+	if err != nil {
+		return fmt.Errorf("failed to get post slug: %w", err)
+	}
+	if postSlug.PostID != postID {
+		return service.ErrPostSlugExists
+	}
+
+	return nil
+}
+
+// replacePostAuthors deletes all bylines of the post and writes the new ones, the authors must exist
+func replacePostAuthors(tx *gorm.DB, postID string, authors []entity.PostAuthor) error {
+	err := tx.
+		Where("post_id = ?", postID).
+		Delete(&entity.PostAuthor{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete post authors: %w", err)
+	}
+	if len(authors) == 0 {
+		return nil
+	}
+
+	bylines := make([]entity.PostAuthor, 0, len(authors))
+	for _, author := range authors {
+		bylines = append(bylines, entity.PostAuthor{PostID: postID, AuthorID: author.AuthorID, Position: author.Position})
+	}
+
+	err = tx.Create(&bylines).Error
+	if err != nil {
+		return fmt.Errorf("failed to create post authors: %w", err)
+	}
+
+	return nil
+}
+
+// createRevision writes a revision of the updated post. For posts edited for the first time
+// the content before the update is written first, so the history always starts from the original.
+func (s *postStorage) createRevision(tx *gorm.DB, currentPost, updatedPost *entity.Post) error {
+	var lastNumber int
+	err := tx.
+		Model(&entity.PostRevision{}).
+		Where("post_id = ?", currentPost.ID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&lastNumber).Error
+	if err != nil {
+		return fmt.Errorf("failed to get last revision number: %w", err)
+	}
+
+	var revisions []entity.PostRevision
+	if lastNumber == 0 {
+		lastNumber++
+		revisions = append(revisions, entity.PostRevision{
+			PostID:  currentPost.ID,
+			Number:  lastNumber,
+			Title:   currentPost.Title,
+			Content: currentPost.Content,
+		})
+	}
+	revisions = append(revisions, entity.PostRevision{
+		PostID:  updatedPost.ID,
+		Number:  lastNumber + 1,
+		Title:   updatedPost.Title,
+		Content: updatedPost.Content,
+	})
+
+	err = tx.Create(&revisions).Error
+	if err != nil {
+		return fmt.Errorf("failed to create revisions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postStorage) Delete(ctx context.Context, id string, version int) error {
+	logger := s.logger.Named("Delete")
+
+	err := s.delete(id, version, false)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to delete post", "err", err)
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+
+	logger.Info("successfully deleted post", "id", id)
+	return nil
+}
+
+func (s *postStorage) UpdateStatus(
+	ctx context.Context, id string, from, to entity.PostStatus, publishedAt *time.Time, version int,
+) (*entity.Post, error) {
+	logger := s.logger.Named("UpdateStatus")
+
+	// the from condition makes the transition atomic for concurrent requests
+	query := s.db.
+		Model(&entity.Post{}).
+		Where("id = ? AND status = ?", id, from)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.
+		Updates(map[string]interface{}{
+			"status":       to,
+			"published_at": publishedAt,
+			"publish_at":   nil,
+			"version":      gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		logger.Error("failed to update post status", "err", result.Error)
+		return nil, fmt.Errorf("failed to update post status: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		post, err := s.Get(ctx, id)
+		if err != nil {
+			logger.Info("failed to get post", "err", err)
+			return nil, err
+		}
+
+		if version != 0 && post.Version != version {
+			logger.Info("post version has been changed", "id", id, "version", version)
+			return nil, service.ErrPostVersionMismatch
+		}
+
+		logger.Info("post status has been changed", "id", id, "from", from)
+		return nil, service.ErrUpdatePostStatusConflict
+	}
+	logger.Debug("updated post status")
 
 	updatedPost, err := s.Get(ctx, id)
 	if err != nil {
@@ -87,20 +499,400 @@ func (s *postStorage) Update(ctx context.Context, id string, post *entity.Post)
 		return nil, fmt.Errorf("failed to get updated post: %w", err)
 	}
 
-	logger.Info("successfully updated post", "updatedPost", updatedPost)
+	logger.Info("successfully updated post status", "updatedPost", updatedPost)
 	return updatedPost, nil
 }
 
-func (s *postStorage) Delete(ctx context.Context, id string) error {
-	logger := s.logger.Named("Delete")
+func (s *postStorage) PublishDue(ctx context.Context, now time.Time, limit int) ([]entity.Post, error) {
+	logger := s.logger.Named("PublishDue")
+
+	// SKIP LOCKED lets concurrent callers take different posts instead of waiting,
+	// and the status condition is rechecked for rows committed by another caller
+	query := s.db.Raw(`
+		UPDATE posts
+		SET status = @published, published_at = @now, publish_at = NULL, updated_at = @now, version = version + 1
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE status = @draft AND publish_at <= @now AND deleted_at IS NULL
+			ORDER BY publish_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		map[string]interface{}{
+			"published": entity.PostStatusPublished,
+			"draft":     entity.PostStatusDraft,
+			"now":       now,
+			"limit":     limit,
+		},
+	)
+
+	var posts []entity.Post
+	err := query.Scan(&posts).Error
+	if err != nil {
+		logger.Error("failed to publish due posts", "err", err)
+		return nil, fmt.Errorf("failed to publish due posts: %w", err)
+	}
+
+	duePosts := make([]*entity.Post, 0, len(posts))
+	for i := range posts {
+		duePosts = append(duePosts, &posts[i])
+	}
+	err = s.loadAssociations(duePosts)
+	if err != nil {
+		logger.Error("failed to load post associations", "err", err)
+		return nil, fmt.Errorf("failed to load post associations: %w", err)
+	}
+
+	logger.Info("successfully published due posts", "posts", posts)
+	return posts, nil
+}
+
+func (s *postStorage) ListRevisions(ctx context.Context, postID string) ([]entity.PostRevision, error) {
+	logger := s.logger.Named("ListRevisions")
 
+	var revisions []entity.PostRevision
 	err := s.db.
-		Delete(&entity.Post{ID: id}).Error
+		Where(entity.PostRevision{PostID: postID}).
+		Order("number").
+		Find(&revisions).Error
 	if err != nil {
-		logger.Error("failed to delete post", "err", err)
-		return fmt.Errorf("failed to delete post: %w", err)
+		logger.Error("failed to list post revisions", "err", err)
+		return nil, fmt.Errorf("failed to list post revisions: %w", err)
 	}
 
-	logger.Info("successfully deleted post", "id", id)
+	logger.Info("successfully listed post revisions", "revisions", revisions)
+	return revisions, nil
+}
+
+func (s *postStorage) GetRevision(ctx context.Context, postID string, number int) (*entity.PostRevision, error) {
+	logger := s.logger.Named("GetRevision")
+
+	var revision entity.PostRevision
+	err := s.db.
+		Where(entity.PostRevision{PostID: postID, Number: number}).
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("post revision not found", "postID", postID, "number", number)
+		return nil, service.ErrGetPostRevisionNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get post revision", "err", err)
+		return nil, fmt.Errorf("failed to get post revision: %w", err)
+	}
+
+	logger.Info("successfully got post revision", "revision", revision)
+	return &revision, nil
+}
+
+func (s *postStorage) Restore(ctx context.Context, id string, version int) (*entity.Post, error) {
+	logger := s.logger.Named("Restore")
+
+	deletedPost := func() *gorm.DB {
+		return s.db.
+			Unscoped().
+			Model(&entity.Post{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id)
+	}
+
+	query := deletedPost()
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Update("deleted_at", nil)
+	if result.Error != nil {
+		logger.Error("failed to restore post", "err", result.Error)
+		return nil, fmt.Errorf("failed to restore post: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		// nothing is restored, so the deleted post doesn't exist or has another version
+		var count int64
+		err := deletedPost().Count(&count).Error
+		if err != nil {
+			logger.Error("failed to count deleted posts", "err", err)
+			return nil, fmt.Errorf("failed to count deleted posts: %w", err)
+		}
+		if count > 0 {
+			logger.Info("deleted post version has been changed", "id", id, "version", version)
+			return nil, service.ErrPostVersionMismatch
+		}
+
+		logger.Info("deleted post not found", "id", id)
+		return nil, service.ErrGetDeletedPostNotFound
+	}
+	logger.Debug("restored post")
+
+	restoredPost, err := s.Get(ctx, id)
+	if err != nil {
+		logger.Error("failed to get restored post", "err", err)
+		return nil, fmt.Errorf("failed to get restored post: %w", err)
+	}
+
+	logger.Info("successfully restored post", "restoredPost", restoredPost)
+	return restoredPost, nil
+}
+
+func (s *postStorage) Purge(ctx context.Context, id string, version int) error {
+	logger := s.logger.Named("Purge")
+
+	// revisions are deleted by the foreign key cascade
+	err := s.delete(id, version, true)
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to purge post", "err", err)
+		return fmt.Errorf("failed to purge post: %w", err)
+	}
+
+	logger.Info("successfully purged post", "id", id)
 	return nil
 }
+
+// delete deletes the post softly or permanently, the version is checked if it's passed
+func (s *postStorage) delete(id string, version int, permanently bool) error {
+	scope := func() *gorm.DB {
+		if permanently {
+			return s.db.Unscoped()
+		}
+		return s.db
+	}
+
+	query := scope().Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&entity.Post{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// nothing is deleted, so the post doesn't exist or has another version
+	var count int64
+	err := scope().
+		Model(&entity.Post{}).
+		Where("id = ?", id).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to count posts: %w", err)
+	}
+	if count == 0 {
+		return service.ErrGetPostNotFound
+	}
+
+	return service.ErrPostVersionMismatch
+}
+
+func (s *postStorage) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	logger := s.logger.Named("PurgeDeletedBefore")
+
+	result := s.db.
+		Unscoped().
+		Where("deleted_at < ?", before).
+		Delete(&entity.Post{})
+	if result.Error != nil {
+		logger.Error("failed to purge deleted posts", "err", result.Error)
+		return 0, fmt.Errorf("failed to purge deleted posts: %w", result.Error)
+	}
+
+	logger.Info("successfully purged deleted posts", "purged", result.RowsAffected)
+	return result.RowsAffected, nil
+}
+
+func (s *postStorage) SetCommentsPremoderation(ctx context.Context, id string, premoderation *bool) error {
+	logger := s.logger.Named("SetCommentsPremoderation")
+
+	result := s.db.
+		Model(&entity.Post{}).
+		Where("id = ?", id).
+		UpdateColumn("comments_premoderation", premoderation)
+	if result.Error != nil {
+		logger.Error("failed to set comments premoderation", "err", result.Error)
+		return fmt.Errorf("failed to set comments premoderation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("post not found", "id", id)
+		return service.ErrGetPostNotFound
+	}
+
+	logger.Info("successfully set comments premoderation", "premoderation", premoderation)
+	return nil
+}
+
+func (s *postStorage) ListWithoutSlug(ctx context.Context, limit int) ([]entity.Post, error) {
+	logger := s.logger.Named("ListWithoutSlug")
+
+	var posts []entity.Post
+	err := s.db.
+		Unscoped().
+		Where("slug = ''").
+		Order("created_at").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		logger.Error("failed to list posts without slug", "err", err)
+		return nil, fmt.Errorf("failed to list posts without slug: %w", err)
+	}
+
+	logger.Info("successfully listed posts without slug", "count", len(posts))
+	return posts, nil
+}
+
+func (s *postStorage) SetSlug(ctx context.Context, id, slug string) error {
+	logger := s.logger.Named("SetSlug")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := reservePostSlug(tx, id, slug)
+		if err != nil {
+			return err
+		}
+
+		result := tx.
+			Unscoped().
+			Model(&entity.Post{}).
+			Where("id = ?", id).
+			UpdateColumn("slug", slug)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update slug: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return service.ErrGetPostNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errs.IsCustom(err) {
+			logger.Info(err.Error())
+			return err
+		}
+
+		logger.Error("failed to set slug", "err", err)
+		return fmt.Errorf("failed to set slug: %w", err)
+	}
+
+	logger.Info("successfully set slug", "id", id, "slug", slug)
+	return nil
+}
+
+// preloadPostAssociations makes the query load tags of the posts ordered by name and bylines
+// ordered by position with their authors, it takes the same number of queries for any number of posts
+func preloadPostAssociations(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tags.name")
+		}).
+		Preload("Authors", func(db *gorm.DB) *gorm.DB {
+			return db.Order("post_authors.position")
+		}).
+		Preload("Authors.Author")
+}
+
+// loadAssociations fills tags and bylines of the posts selected by raw queries, which can't preload them
+func (s *postStorage) loadAssociations(posts []*entity.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postsByID := make(map[string]*entity.Post, len(posts))
+	ids := make([]string, 0, len(posts))
+	for _, post := range posts {
+		post.Tags = []entity.Tag{}
+		post.Authors = []entity.PostAuthor{}
+		postsByID[post.ID] = post
+		ids = append(ids, post.ID)
+	}
+
+	var postAuthors []entity.PostAuthor
+	err := s.db.
+		Preload("Author").
+		Where("post_id IN ?", ids).
+		Order("position").
+		Find(&postAuthors).Error
+	if err != nil {
+		return err
+	}
+
+	for _, postAuthor := range postAuthors {
+		post := postsByID[postAuthor.PostID]
+		post.Authors = append(post.Authors, postAuthor)
+	}
+
+	var postTags []struct {
+		PostID string
+		entity.Tag
+	}
+	err = s.db.Raw(`
+		SELECT post_tags.post_id, tags.*
+		FROM tags
+		JOIN post_tags ON post_tags.tag_id = tags.id
+		WHERE post_tags.post_id IN ?
+		ORDER BY tags.name`,
+		ids,
+	).Scan(&postTags).Error
+	if err != nil {
+		return err
+	}
+
+	for _, postTag := range postTags {
+		post := postsByID[postTag.PostID]
+		post.Tags = append(post.Tags, postTag.Tag)
+	}
+
+	return nil
+}
+
+// postIDsWithTags is a subquery selecting IDs of posts with any of the tags, or with all of them if matchAll is set
+func postIDsWithTags(db *gorm.DB, tags []string, matchAll bool) *gorm.DB {
+	query := db.
+		Table("post_tags").
+		Select("post_tags.post_id").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("tags.name IN ?", tags).
+		Group("post_tags.post_id")
+	if matchAll {
+		query = query.Having("COUNT(*) = ?", len(tags))
+	}
+
+	return query
+}
+
+// postsSortColumn returns a column and a direction for the sort, the newest posts go first by default
+func postsSortColumn(sort entity.PostsSort) (string, string) {
+	switch sort {
+	case entity.PostsSortCreatedAt:
+		return "created_at", "ASC"
+	case entity.PostsSortUpdatedAt:
+		return "updated_at", "ASC"
+	case entity.PostsSortTitle:
+		return "title", "ASC"
+	default:
+		return "created_at", "DESC"
+	}
+}
+
+func postsCursorValue(sort entity.PostsSort, cursor *entity.PostsCursor) interface{} {
+	switch sort {
+	case entity.PostsSortUpdatedAt:
+		return cursor.UpdatedAt
+	case entity.PostsSortTitle:
+		return cursor.Title
+	default:
+		return cursor.CreatedAt
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes LIKE wildcards, so user input is matched literally
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -9,16 +9,29 @@ import (
 	"darkness8129/news-api/packages/logging"
 	"log"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
 var (
-	db      *gorm.DB
-	storage service.PostStorage
+	db                   *gorm.DB
+	storage              service.PostStorage
+	idempotencyStorage   service.IdempotencyKeyStorage
+	tagsStorage          service.TagStorage
+	categoriesStorage    service.CategoryStorage
+	authorsStorage       service.AuthorStorage
+	commentsStorage      service.CommentStorage
+	usersStorage         service.UserStorage
+	refreshTokensStorage service.RefreshTokenStorage
+	apiKeysStorage       service.APIKeyStorage
+	oidcRequestsStorage  service.OIDCAuthRequestStorage
+	storages             service.Storages
 )
 
 func init() {
@@ -50,12 +63,22 @@ func init() {
 		logger.Fatal("failed type assertion for db")
 	}
 
-	err = DB.AutoMigrate(&entity.Post{})
+	err = Migrate(DB)
 	if err != nil {
-		logger.Fatal("automigration failed", "err", err)
+		logger.Fatal("migration failed", "err", err)
 	}
 
 	storage = NewPostStorage(DB, logger)
+	idempotencyStorage = NewIdempotencyKeyStorage(DB, logger)
+	tagsStorage = NewTagStorage(DB, logger)
+	categoriesStorage = NewCategoryStorage(DB, logger)
+	authorsStorage = NewAuthorStorage(DB, logger)
+	commentsStorage = NewCommentStorage(DB, logger)
+	usersStorage = NewUserStorage(DB, logger)
+	refreshTokensStorage = NewRefreshTokenStorage(DB, logger)
+	apiKeysStorage = NewAPIKeyStorage(DB, logger)
+	oidcRequestsStorage = NewOIDCAuthRequestStorage(DB, logger)
+	storages = NewStorages(DB, logger)
 	db = DB
 }
 
@@ -133,11 +156,17 @@ func TestPostStorage_Create(t *testing.T) {
 }
 
 func TestPostStorage_List(t *testing.T) {
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+
 	testCases := []struct {
-		name          string
-		postsToCreate []entity.Post
-		expectedLen   int
-		expectErr     bool
+		name           string
+		postsToCreate  []entity.Post
+		inputFilter    entity.PostsFilter
+		useLastCursor  bool
+		expectedLen    int
+		expectedTitles []string
+		expectErr      bool
 	}{
 		{
 			name:          "List 0 posts",
@@ -158,6 +187,164 @@ func TestPostStorage_List(t *testing.T) {
 			},
 			expectedLen: 2,
 		},
+		{
+			name: "List with limit",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "title",
+					Content: "content",
+				},
+				{
+					Title:   "title",
+					Content: "content",
+				},
+				{
+					Title:   "title",
+					Content: "content",
+				},
+			},
+			inputFilter: entity.PostsFilter{Limit: 2},
+			expectedLen: 2,
+		},
+		{
+			name: "List after cursor",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "title",
+					Content: "content",
+				},
+				{
+					Title:   "title",
+					Content: "content",
+				},
+				{
+					Title:   "title",
+					Content: "content",
+				},
+			},
+			inputFilter:   entity.PostsFilter{Limit: 2},
+			useLastCursor: true,
+			expectedLen:   1,
+		},
+		{
+			name: "List only published",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "title",
+					Content: "content",
+					Status:  entity.PostStatusPublished,
+				},
+				{
+					Title:   "title",
+					Content: "content",
+					Status:  entity.PostStatusDraft,
+				},
+				{
+					Title:   "title",
+					Content: "content",
+					Status:  entity.PostStatusArchived,
+				},
+			},
+			inputFilter: entity.PostsFilter{Status: entity.PostStatusPublished},
+			expectedLen: 1,
+		},
+		{
+			name: "List with title filter",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "Breaking news",
+					Content: "content",
+				},
+				{
+					Title:   "weather",
+					Content: "content",
+				},
+				{
+					Title:   "breaking_point",
+					Content: "content",
+				},
+			},
+			inputFilter: entity.PostsFilter{TitleContains: "BREAKING"},
+			expectedLen: 2,
+		},
+		{
+			name: "List with title filter containing wildcard",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "breaking news",
+					Content: "content",
+				},
+				{
+					Title:   "breaking_point",
+					Content: "content",
+				},
+			},
+			inputFilter: entity.PostsFilter{TitleContains: "_"},
+			expectedLen: 1,
+		},
+		{
+			name: "List with created range in the future",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "title",
+					Content: "content",
+				},
+			},
+			inputFilter: entity.PostsFilter{CreatedFrom: &tomorrow},
+			expectedLen: 0,
+		},
+		{
+			name: "List with created and updated range",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "title",
+					Content: "content",
+				},
+			},
+			inputFilter: entity.PostsFilter{CreatedFrom: &yesterday, CreatedTo: &tomorrow, UpdatedSince: &yesterday},
+			expectedLen: 1,
+		},
+		{
+			name: "List sorted by title",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "b",
+					Content: "content",
+				},
+				{
+					Title:   "a",
+					Content: "content",
+				},
+				{
+					Title:   "c",
+					Content: "content",
+				},
+			},
+			inputFilter:    entity.PostsFilter{Sort: entity.PostsSortTitle},
+			expectedLen:    3,
+			expectedTitles: []string{"a", "b", "c"},
+		},
+		{
+			name: "List sorted by title after cursor",
+			postsToCreate: []entity.Post{
+				{
+					Title:   "b",
+					Content: "content",
+				},
+				{
+					Title:   "a",
+					Content: "content",
+				},
+				{
+					Title:   "c",
+					Content: "content",
+				},
+			},
+			inputFilter:    entity.PostsFilter{Sort: entity.PostsSortTitle, Limit: 2},
+			useLastCursor:  true,
+			expectedLen:    1,
+			expectedTitles: []string{"c"},
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -172,10 +359,39 @@ func TestPostStorage_List(t *testing.T) {
 				require.NoError(t, err, "failed to create post")
 			}
 
-			actual, err := storage.List(context.Background())
+			filter := tc.inputFilter
+			if tc.useLastCursor {
+				firstPage, err := storage.List(context.Background(), filter)
+				require.NoError(t, err, "failed to list first page")
+
+				last := firstPage[len(firstPage)-1]
+				filter.After = &entity.PostsCursor{
+					Sort:      filter.Sort,
+					CreatedAt: last.CreatedAt,
+					UpdatedAt: last.UpdatedAt,
+					Title:     last.Title,
+					ID:        last.ID,
+				}
+			}
+
+			actual, err := storage.List(context.Background(), filter)
 			if !tc.expectErr {
 				require.NoError(t, err, "failed to list posts")
 				require.Equal(t, tc.expectedLen, len(actual), "len is not equal")
+
+				if tc.expectedTitles != nil {
+					var titles []string
+					for _, p := range actual {
+						titles = append(titles, p.Title)
+					}
+					require.Equal(t, tc.expectedTitles, titles, "titles are not equal")
+				}
+
+				if filter.Sort == "" {
+					for i := 1; i < len(actual); i++ {
+						require.False(t, actual[i].CreatedAt.After(actual[i-1].CreatedAt), "posts are not ordered from the newest")
+					}
+				}
 			} else {
 				require.Error(t, err, "no error")
 				require.Empty(t, actual, "slice is not empty")
@@ -184,6 +400,85 @@ func TestPostStorage_List(t *testing.T) {
 	}
 }
 
+func TestPostStorage_Search(t *testing.T) {
+	postsToCreate := []entity.Post{
+		{
+			Title:   "Elections in Europe",
+			Content: "Results of the parliament elections",
+		},
+		{
+			Title:   "Weather",
+			Content: "Heavy rain is expected after the elections",
+		},
+		{
+			Title:   "Football",
+			Content: "The final match was played yesterday",
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		inputFilter     entity.PostsSearchFilter
+		expectedTitles  []string
+		expectHighlight bool
+	}{
+		{
+			name:            "Search ranks title matches higher",
+			inputFilter:     entity.PostsSearchFilter{Query: "election", Limit: 10},
+			expectedTitles:  []string{"Elections in Europe", "Weather"},
+			expectHighlight: true,
+		},
+		{
+			name:           "Search with phrase",
+			inputFilter:    entity.PostsSearchFilter{Query: `"final match"`, Limit: 10},
+			expectedTitles: []string{"Football"},
+		},
+		{
+			name:           "Search with exclusion",
+			inputFilter:    entity.PostsSearchFilter{Query: "elections -rain", Limit: 10},
+			expectedTitles: []string{"Elections in Europe"},
+		},
+		{
+			name:           "Search with limit and offset",
+			inputFilter:    entity.PostsSearchFilter{Query: "elections", Limit: 1, Offset: 1},
+			expectedTitles: []string{"Weather"},
+		},
+		{
+			name:           "Search without matches",
+			inputFilter:    entity.PostsSearchFilter{Query: "basketball", Limit: 10},
+			expectedTitles: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := db.Exec("DELETE FROM posts;").Error
+				require.NoError(t, err, "failed to clear posts table")
+			})
+
+			for _, p := range postsToCreate {
+				_, err := storage.Create(context.Background(), &p)
+				require.NoError(t, err, "failed to create post")
+			}
+
+			actual, err := storage.Search(context.Background(), tc.inputFilter)
+			require.NoError(t, err, "failed to search posts")
+
+			var titles []string
+			for _, r := range actual {
+				titles = append(titles, r.Title)
+				require.NotZero(t, r.Rank, "rank is zero")
+			}
+			require.Equal(t, tc.expectedTitles, titles, "titles are not equal")
+
+			if tc.expectHighlight {
+				require.Contains(t, actual[0].TitleHighlight, "<mark>Elections</mark>", "title is not highlighted")
+			}
+		})
+	}
+}
+
 func TestPostStorage_Get(t *testing.T) {
 	postID := uuid.NewString()
 	post := &entity.Post{
@@ -250,65 +545,152 @@ func TestPostStorage_Update(t *testing.T) {
 		Title:   "title",
 		Content: "content",
 	}
+	titleUpdated := "title updated"
+	contentUpdated := "content updated"
+	emptyContent := ""
+	tomorrow := time.Now().Add(24 * time.Hour).Truncate(time.Microsecond)
 
 	testCases := []struct {
 		name         string
 		postToCreate *entity.Post
 		inputID      string
-		inputPost    *entity.Post
+		inputUpdate  entity.PostUpdate
 		expected     *entity.Post
+		expectedErr  error
 		expectErr    bool
 	}{
 		{
 			name:         "Update",
 			postToCreate: post,
-			inputPost: &entity.Post{
-				Title:   "title updated",
-				Content: "content updated",
+			inputUpdate: entity.PostUpdate{
+				Title:   &titleUpdated,
+				Content: &contentUpdated,
 			},
 			inputID: postID,
 			expected: &entity.Post{
 				ID:      postID,
 				Title:   "title updated",
 				Content: "content updated",
+				Version: 2,
 			},
 		},
 		{
 			name:         "Update only title",
 			postToCreate: post,
-			inputPost: &entity.Post{
-				Title: "title updated",
+			inputUpdate: entity.PostUpdate{
+				Title: &titleUpdated,
 			},
 			inputID: postID,
 			expected: &entity.Post{
 				ID:      postID,
 				Title:   "title updated",
 				Content: "content",
+				Version: 2,
+			},
+		},
+		{
+			name:         "Update clearing content",
+			postToCreate: post,
+			inputUpdate: entity.PostUpdate{
+				Content: &emptyContent,
+			},
+			inputID: postID,
+			expected: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "",
+				Version: 2,
+			},
+		},
+		{
+			name:         "Update scheduling publishing",
+			postToCreate: post,
+			inputUpdate: entity.PostUpdate{
+				PublishAt: &tomorrow,
+			},
+			inputID: postID,
+			expected: &entity.Post{
+				ID:        postID,
+				Title:     "title",
+				Content:   "content",
+				PublishAt: &tomorrow,
+				Version:   2,
+			},
+		},
+		{
+			name: "Update cancelling scheduled publishing",
+			postToCreate: &entity.Post{
+				ID:        postID,
+				Title:     "title",
+				Content:   "content",
+				PublishAt: &tomorrow,
+			},
+			inputUpdate: entity.PostUpdate{
+				ClearPublishAt: true,
+			},
+			inputID: postID,
+			expected: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+				Version: 2,
 			},
 		},
 		{
 			name:         "Update without any changes",
 			postToCreate: post,
-			inputPost:    &entity.Post{},
+			inputUpdate:  entity.PostUpdate{},
 			inputID:      postID,
-			expected:     post,
+			expected: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+				Version: 1,
+			},
 		},
 		{
-			name:         "Update with wrong ID",
+			name:         "Update with version",
 			postToCreate: post,
-			inputPost: &entity.Post{
+			inputUpdate: entity.PostUpdate{
+				Title:   &titleUpdated,
+				Version: 1,
+			},
+			inputID: postID,
+			expected: &entity.Post{
+				ID:      postID,
 				Title:   "title updated",
-				Content: "content updated",
+				Content: "content",
+				Version: 2,
 			},
-			inputID:   uuid.NewString(),
-			expectErr: true,
+		},
+		{
+			name:         "Update with version mismatch",
+			postToCreate: post,
+			inputUpdate: entity.PostUpdate{
+				Title:   &titleUpdated,
+				Version: 2,
+			},
+			inputID:     postID,
+			expectedErr: service.ErrPostVersionMismatch,
+			expectErr:   true,
+		},
+		{
+			name:         "Update with wrong ID",
+			postToCreate: post,
+			inputUpdate: entity.PostUpdate{
+				Title:   &titleUpdated,
+				Content: &contentUpdated,
+			},
+			inputID:     uuid.NewString(),
+			expectedErr: service.ErrGetPostNotFound,
+			expectErr:   true,
 		},
 		{
 			name:         "Update with invalid ID",
 			postToCreate: post,
-			inputPost: &entity.Post{
-				Title:   "title updated",
-				Content: "content updated",
+			inputUpdate: entity.PostUpdate{
+				Title:   &titleUpdated,
+				Content: &contentUpdated,
 			},
 			inputID:   "invalid",
 			expectErr: true,
@@ -325,15 +707,20 @@ func TestPostStorage_Update(t *testing.T) {
 			_, err := storage.Create(context.Background(), tc.postToCreate)
 			require.NoError(t, err, "failed to create post")
 
-			actual, err := storage.Update(context.Background(), tc.inputID, tc.inputPost)
+			actual, err := storage.Update(context.Background(), tc.inputID, tc.inputUpdate)
 			if !tc.expectErr {
 				require.NoError(t, err, "failed to update post")
 				require.Equal(t, tc.expected.ID, actual.ID, "IDs are not equal")
 				require.Equal(t, tc.expected.Title, actual.Title, "titles are not equal")
 				require.Equal(t, tc.expected.Content, actual.Content, "content is not equal")
+				require.Equal(t, tc.expected.PublishAt == nil, actual.PublishAt == nil, "publishAt presence is not equal")
+				require.Equal(t, tc.expected.Version, actual.Version, "versions are not equal")
 			} else {
 				require.Error(t, err, "no error")
 				require.Nil(t, actual, "post is not nil")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				}
 			}
 		})
 	}
@@ -351,6 +738,8 @@ func TestPostStorage_Delete(t *testing.T) {
 		name         string
 		postToCreate *entity.Post
 		inputID      string
+		inputVersion int
+		expectedErr  error
 		expectErr    bool
 	}{
 		{
@@ -359,9 +748,25 @@ func TestPostStorage_Delete(t *testing.T) {
 			inputID:      postID,
 		},
 		{
-			name:         "Delete with wrong ID",
+			name:         "Delete with version",
+			postToCreate: post,
+			inputID:      postID,
+			inputVersion: 1,
+		},
+		{
+			name:         "Delete with version mismatch",
 			postToCreate: post,
 			inputID:      postID,
+			inputVersion: 2,
+			expectedErr:  service.ErrPostVersionMismatch,
+			expectErr:    true,
+		},
+		{
+			name:         "Delete with wrong ID",
+			postToCreate: post,
+			inputID:      uuid.NewString(),
+			expectedErr:  service.ErrGetPostNotFound,
+			expectErr:    true,
 		},
 		{
 			name:         "Delete with invalid ID",
@@ -381,7 +786,7 @@ func TestPostStorage_Delete(t *testing.T) {
 			_, err := storage.Create(context.Background(), tc.postToCreate)
 			require.NoError(t, err, "failed to create post")
 
-			err = storage.Delete(context.Background(), tc.inputID)
+			err = storage.Delete(context.Background(), tc.inputID, tc.inputVersion)
 			if !tc.expectErr {
 				require.NoError(t, err, "failed to delete post")
 
@@ -389,12 +794,705 @@ func TestPostStorage_Delete(t *testing.T) {
 				require.Error(t, err, "got post")
 			} else {
 				require.Error(t, err, "no error")
-
-				if uuid.Validate(tc.inputID) == nil {
-					_, err := storage.Get(context.Background(), tc.inputID)
-					require.NoError(t, err, "failed to get post")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
 				}
+
+				_, err := storage.Get(context.Background(), tc.postToCreate.ID)
+				require.NoError(t, err, "failed to get post")
 			}
 		})
 	}
 }
+
+func TestPostStorage_UpdateStatus(t *testing.T) {
+	postID := uuid.NewString()
+	publishedAt := time.Now()
+
+	testCases := []struct {
+		name         string
+		postToCreate *entity.Post
+		inputID      string
+		inputFrom    entity.PostStatus
+		inputTo      entity.PostStatus
+		publishedAt  *time.Time
+		inputVersion int
+		expectedErr  error
+		expectErr    bool
+	}{
+		{
+			name: "UpdateStatus",
+			postToCreate: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+				Status:  entity.PostStatusDraft,
+			},
+			inputID:     postID,
+			inputFrom:   entity.PostStatusDraft,
+			inputTo:     entity.PostStatusPublished,
+			publishedAt: &publishedAt,
+		},
+		{
+			name: "UpdateStatus clearing publishedAt",
+			postToCreate: &entity.Post{
+				ID:          postID,
+				Title:       "title",
+				Content:     "content",
+				Status:      entity.PostStatusPublished,
+				PublishedAt: &publishedAt,
+			},
+			inputID:   postID,
+			inputFrom: entity.PostStatusPublished,
+			inputTo:   entity.PostStatusDraft,
+		},
+		{
+			name: "UpdateStatus with changed status",
+			postToCreate: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+				Status:  entity.PostStatusArchived,
+			},
+			inputID:     postID,
+			inputFrom:   entity.PostStatusDraft,
+			inputTo:     entity.PostStatusPublished,
+			publishedAt: &publishedAt,
+			expectedErr: service.ErrUpdatePostStatusConflict,
+			expectErr:   true,
+		},
+		{
+			name: "UpdateStatus with version",
+			postToCreate: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+				Status:  entity.PostStatusDraft,
+			},
+			inputID:      postID,
+			inputFrom:    entity.PostStatusDraft,
+			inputTo:      entity.PostStatusPublished,
+			publishedAt:  &publishedAt,
+			inputVersion: 1,
+		},
+		{
+			name: "UpdateStatus with version mismatch",
+			postToCreate: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+				Status:  entity.PostStatusDraft,
+			},
+			inputID:      postID,
+			inputFrom:    entity.PostStatusDraft,
+			inputTo:      entity.PostStatusPublished,
+			publishedAt:  &publishedAt,
+			inputVersion: 2,
+			expectedErr:  service.ErrPostVersionMismatch,
+			expectErr:    true,
+		},
+		{
+			name: "UpdateStatus with wrong ID",
+			postToCreate: &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+				Status:  entity.PostStatusDraft,
+			},
+			inputID:     uuid.NewString(),
+			inputFrom:   entity.PostStatusDraft,
+			inputTo:     entity.PostStatusPublished,
+			publishedAt: &publishedAt,
+			expectedErr: service.ErrGetPostNotFound,
+			expectErr:   true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := db.Exec("DELETE FROM posts;").Error
+				require.NoError(t, err, "failed to clear posts table")
+			})
+
+			_, err := storage.Create(context.Background(), tc.postToCreate)
+			require.NoError(t, err, "failed to create post")
+
+			actual, err := storage.UpdateStatus(context.Background(), tc.inputID, tc.inputFrom, tc.inputTo, tc.publishedAt, tc.inputVersion)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to update post status")
+				require.Equal(t, tc.inputTo, actual.Status, "statuses are not equal")
+				require.Equal(t, tc.publishedAt == nil, actual.PublishedAt == nil, "publishedAt presence is not equal")
+				require.Equal(t, 2, actual.Version, "version is not incremented")
+			} else {
+				require.Error(t, err, "no error")
+				require.Nil(t, actual, "post is not nil")
+				require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+			}
+		})
+	}
+}
+
+func TestPostStorage_PublishDue(t *testing.T) {
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		postsToCreate []entity.Post
+		inputLimit    int
+		callers       int
+		expectedLen   int
+	}{
+		{
+			name: "PublishDue",
+			postsToCreate: []entity.Post{
+				{
+					Title:     "due",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &yesterday,
+				},
+				{
+					Title:     "not due",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &tomorrow,
+				},
+				{
+					Title:   "not scheduled",
+					Content: "content",
+					Status:  entity.PostStatusDraft,
+				},
+				{
+					Title:     "archived",
+					Content:   "content",
+					Status:    entity.PostStatusArchived,
+					PublishAt: &yesterday,
+				},
+			},
+			inputLimit:  10,
+			callers:     1,
+			expectedLen: 1,
+		},
+		{
+			name: "PublishDue with limit",
+			postsToCreate: []entity.Post{
+				{
+					Title:     "due",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &yesterday,
+				},
+				{
+					Title:     "due",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &yesterday,
+				},
+			},
+			inputLimit:  1,
+			callers:     1,
+			expectedLen: 1,
+		},
+		{
+			name: "PublishDue with concurrent callers",
+			postsToCreate: []entity.Post{
+				{
+					Title:     "due",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &yesterday,
+				},
+				{
+					Title:     "due",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &yesterday,
+				},
+				{
+					Title:     "due",
+					Content:   "content",
+					Status:    entity.PostStatusDraft,
+					PublishAt: &yesterday,
+				},
+			},
+			inputLimit:  10,
+			callers:     5,
+			expectedLen: 3,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := db.Exec("DELETE FROM posts;").Error
+				require.NoError(t, err, "failed to clear posts table")
+			})
+
+			for _, p := range tc.postsToCreate {
+				_, err := storage.Create(context.Background(), &p)
+				require.NoError(t, err, "failed to create post")
+			}
+
+			var (
+				wg        sync.WaitGroup
+				mu        sync.Mutex
+				published []entity.Post
+			)
+			for i := 0; i < tc.callers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					posts, err := storage.PublishDue(context.Background(), time.Now(), tc.inputLimit)
+					assert.NoError(t, err, "failed to publish due posts")
+
+					mu.Lock()
+					published = append(published, posts...)
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+
+			require.Equal(t, tc.expectedLen, len(published), "len is not equal")
+
+			ids := make(map[string]struct{})
+			for _, p := range published {
+				require.Equal(t, entity.PostStatusPublished, p.Status, "post is not published")
+				require.NotNil(t, p.PublishedAt, "publishedAt is empty")
+				require.Nil(t, p.PublishAt, "publishAt is not cleared")
+
+				_, ok := ids[p.ID]
+				require.False(t, ok, "post is published twice")
+				ids[p.ID] = struct{}{}
+			}
+		})
+	}
+}
+
+func TestPostStorage_Revisions(t *testing.T) {
+	postID := uuid.NewString()
+	title := "title"
+	titleUpdated := "title updated"
+	contentUpdated := "content updated"
+
+	testCases := []struct {
+		name              string
+		updates           []entity.PostUpdate
+		expectedRevisions []entity.PostRevision
+	}{
+		{
+			name:              "Revisions without updates",
+			updates:           nil,
+			expectedRevisions: nil,
+		},
+		{
+			name: "Revisions after first update",
+			updates: []entity.PostUpdate{
+				{Title: &titleUpdated},
+			},
+			expectedRevisions: []entity.PostRevision{
+				{Number: 1, Title: "title", Content: "content"},
+				{Number: 2, Title: "title updated", Content: "content"},
+			},
+		},
+		{
+			name: "Revisions after several updates",
+			updates: []entity.PostUpdate{
+				{Title: &titleUpdated},
+				{Content: &contentUpdated},
+			},
+			expectedRevisions: []entity.PostRevision{
+				{Number: 1, Title: "title", Content: "content"},
+				{Number: 2, Title: "title updated", Content: "content"},
+				{Number: 3, Title: "title updated", Content: "content updated"},
+			},
+		},
+		{
+			name: "Revisions after update without content changes",
+			updates: []entity.PostUpdate{
+				{Title: &title},
+			},
+			expectedRevisions: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := db.Exec("DELETE FROM posts;").Error
+				require.NoError(t, err, "failed to clear posts table")
+			})
+
+			_, err := storage.Create(context.Background(), &entity.Post{
+				ID:      postID,
+				Title:   "title",
+				Content: "content",
+			})
+			require.NoError(t, err, "failed to create post")
+
+			for _, p := range tc.updates {
+				_, err := storage.Update(context.Background(), postID, p)
+				require.NoError(t, err, "failed to update post")
+			}
+
+			actual, err := storage.ListRevisions(context.Background(), postID)
+			require.NoError(t, err, "failed to list post revisions")
+			require.Equal(t, len(tc.expectedRevisions), len(actual), "len is not equal")
+
+			for i, expected := range tc.expectedRevisions {
+				require.Equal(t, expected.Number, actual[i].Number, "numbers are not equal")
+				require.Equal(t, expected.Title, actual[i].Title, "titles are not equal")
+				require.Equal(t, expected.Content, actual[i].Content, "content is not equal")
+
+				revision, err := storage.GetRevision(context.Background(), postID, expected.Number)
+				require.NoError(t, err, "failed to get post revision")
+				require.Equal(t, actual[i].ID, revision.ID, "IDs are not equal")
+			}
+
+			_, err = storage.GetRevision(context.Background(), postID, len(tc.expectedRevisions)+1)
+			require.ErrorIs(t, err, service.ErrGetPostRevisionNotFound, "unexpected error")
+		})
+	}
+}
+
+func TestPostStorage_Trash(t *testing.T) {
+	postID := uuid.NewString()
+	deletedPostID := uuid.NewString()
+
+	testCases := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "List only deleted posts",
+			test: func(t *testing.T) {
+				actual, err := storage.List(context.Background(), entity.PostsFilter{OnlyDeleted: true})
+				require.NoError(t, err, "failed to list deleted posts")
+				require.Len(t, actual, 1, "len is not equal")
+				require.Equal(t, deletedPostID, actual[0].ID, "IDs are not equal")
+				require.True(t, actual[0].DeletedAt.Valid, "post is not deleted")
+			},
+		},
+		{
+			name: "List without deleted posts",
+			test: func(t *testing.T) {
+				actual, err := storage.List(context.Background(), entity.PostsFilter{})
+				require.NoError(t, err, "failed to list posts")
+				require.Len(t, actual, 1, "len is not equal")
+				require.Equal(t, postID, actual[0].ID, "IDs are not equal")
+			},
+		},
+		{
+			name: "Restore",
+			test: func(t *testing.T) {
+				actual, err := storage.Restore(context.Background(), deletedPostID, 0)
+				require.NoError(t, err, "failed to restore post")
+				require.Equal(t, deletedPostID, actual.ID, "IDs are not equal")
+				require.False(t, actual.DeletedAt.Valid, "post is still deleted")
+			},
+		},
+		{
+			name: "Restore with version",
+			test: func(t *testing.T) {
+				actual, err := storage.Restore(context.Background(), deletedPostID, 1)
+				require.NoError(t, err, "failed to restore post")
+				require.Equal(t, 1, actual.Version, "versions are not equal")
+			},
+		},
+		{
+			name: "Restore with version mismatch",
+			test: func(t *testing.T) {
+				actual, err := storage.Restore(context.Background(), deletedPostID, 2)
+				require.ErrorIs(t, err, service.ErrPostVersionMismatch, "unexpected error")
+				require.Nil(t, actual, "post is not nil")
+			},
+		},
+		{
+			name: "Restore not deleted post",
+			test: func(t *testing.T) {
+				actual, err := storage.Restore(context.Background(), postID, 0)
+				require.ErrorIs(t, err, service.ErrGetDeletedPostNotFound, "unexpected error")
+				require.Nil(t, actual, "post is not nil")
+			},
+		},
+		{
+			name: "Purge deleted post",
+			test: func(t *testing.T) {
+				err := storage.Purge(context.Background(), deletedPostID, 0)
+				require.NoError(t, err, "failed to purge post")
+
+				_, err = storage.Restore(context.Background(), deletedPostID, 0)
+				require.ErrorIs(t, err, service.ErrGetDeletedPostNotFound, "post was not purged")
+			},
+		},
+		{
+			name: "Purge not deleted post",
+			test: func(t *testing.T) {
+				err := storage.Purge(context.Background(), postID, 0)
+				require.NoError(t, err, "failed to purge post")
+
+				_, err = storage.Get(context.Background(), postID)
+				require.ErrorIs(t, err, service.ErrGetPostNotFound, "post was not purged")
+			},
+		},
+		{
+			name: "Purge with version mismatch",
+			test: func(t *testing.T) {
+				err := storage.Purge(context.Background(), deletedPostID, 2)
+				require.ErrorIs(t, err, service.ErrPostVersionMismatch, "unexpected error")
+			},
+		},
+		{
+			name: "Purge with wrong ID",
+			test: func(t *testing.T) {
+				err := storage.Purge(context.Background(), uuid.NewString(), 0)
+				require.ErrorIs(t, err, service.ErrGetPostNotFound, "unexpected error")
+			},
+		},
+		{
+			name: "PurgeDeletedBefore",
+			test: func(t *testing.T) {
+				purged, err := storage.PurgeDeletedBefore(context.Background(), time.Now().Add(-time.Hour))
+				require.NoError(t, err, "failed to purge deleted posts")
+				require.Equal(t, int64(0), purged, "purged counts are not equal")
+
+				purged, err = storage.PurgeDeletedBefore(context.Background(), time.Now().Add(time.Hour))
+				require.NoError(t, err, "failed to purge deleted posts")
+				require.Equal(t, int64(1), purged, "purged counts are not equal")
+
+				_, err = storage.Get(context.Background(), postID)
+				require.NoError(t, err, "not deleted post was purged")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := db.Exec("DELETE FROM posts;").Error
+				require.NoError(t, err, "failed to clear posts table")
+			})
+
+			for _, id := range []string{postID, deletedPostID} {
+				_, err := storage.Create(context.Background(), &entity.Post{
+					ID:      id,
+					Title:   "title",
+					Content: "content",
+				})
+				require.NoError(t, err, "failed to create post")
+			}
+
+			err := storage.Delete(context.Background(), deletedPostID, 0)
+			require.NoError(t, err, "failed to delete post")
+
+			tc.test(t)
+		})
+	}
+}
+
+func TestPostStorage_Tags(t *testing.T) {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM posts;").Error
+		require.NoError(t, err, "failed to clear posts table")
+		err = db.Exec("DELETE FROM tags;").Error
+		require.NoError(t, err, "failed to clear tags table")
+	})
+
+	tags, err := tagsStorage.Ensure(context.Background(), []string{"go", "news"})
+	require.NoError(t, err, "failed to create tags")
+
+	bothTags, err := storage.Create(context.Background(), &entity.Post{Title: "both", Content: "content", Tags: tags})
+	require.NoError(t, err, "failed to create post")
+	oneTag, err := storage.Create(context.Background(), &entity.Post{Title: "one", Content: "content", Tags: tags[:1]})
+	require.NoError(t, err, "failed to create post")
+	_, err = storage.Create(context.Background(), &entity.Post{Title: "none", Content: "content"})
+	require.NoError(t, err, "failed to create post")
+
+	got, err := storage.Get(context.Background(), bothTags.ID)
+	require.NoError(t, err, "failed to get post")
+	require.Equal(t, []string{"go", "news"}, []string{got.Tags[0].Name, got.Tags[1].Name}, "tags are not equal")
+
+	anyPosts, err := storage.List(context.Background(), entity.PostsFilter{Tags: []string{"go", "news"}, Sort: entity.PostsSortTitle})
+	require.NoError(t, err, "failed to list posts")
+	require.Len(t, anyPosts, 2, "posts with any tag are not listed")
+
+	allPosts, err := storage.List(context.Background(), entity.PostsFilter{Tags: []string{"go", "news"}, TagsMatchAll: true})
+	require.NoError(t, err, "failed to list posts")
+	require.Len(t, allPosts, 1, "posts with all tags are not listed")
+	require.Equal(t, bothTags.ID, allPosts[0].ID, "IDs are not equal")
+	require.Len(t, allPosts[0].Tags, 2, "tags are not preloaded")
+
+	// replacing tags changes the version, but doesn't write a revision
+	newTags := tags[1:]
+	updated, err := storage.Update(context.Background(), oneTag.ID, entity.PostUpdate{Tags: &newTags})
+	require.NoError(t, err, "failed to update post")
+	require.Equal(t, oneTag.Version+1, updated.Version, "version is not incremented")
+	require.Len(t, updated.Tags, 1, "len is not equal")
+	require.Equal(t, "news", updated.Tags[0].Name, "tags are not replaced")
+
+	revisions, err := storage.ListRevisions(context.Background(), oneTag.ID)
+	require.NoError(t, err, "failed to list revisions")
+	require.Empty(t, revisions, "revision is written")
+
+	noTags := []entity.Tag{}
+	updated, err = storage.Update(context.Background(), oneTag.ID, entity.PostUpdate{Tags: &noTags})
+	require.NoError(t, err, "failed to update post")
+	require.Empty(t, updated.Tags, "tags are not removed")
+}
+
+func TestPostStorage_Authors(t *testing.T) {
+	authors := createAuthors(t, "jane", "john")
+
+	byline := func(authors ...*entity.Author) []entity.PostAuthor {
+		postAuthors := make([]entity.PostAuthor, 0, len(authors))
+		for i, author := range authors {
+			postAuthors = append(postAuthors, entity.PostAuthor{AuthorID: author.ID, Position: i})
+		}
+
+		return postAuthors
+	}
+
+	both, err := storage.Create(context.Background(), &entity.Post{Title: "both", Content: "content", Authors: byline(authors[1], authors[0])})
+	require.NoError(t, err, "failed to create post")
+	require.Equal(t, "john", both.Authors[0].Author.Name, "authors are not loaded")
+	one, err := storage.Create(context.Background(), &entity.Post{Title: "one", Content: "content", Authors: byline(authors[0])})
+	require.NoError(t, err, "failed to create post")
+
+	got, err := storage.Get(context.Background(), both.ID)
+	require.NoError(t, err, "failed to get post")
+	require.Equal(t, []string{"john", "jane"}, []string{got.Authors[0].Author.Name, got.Authors[1].Author.Name}, "bylines are not ordered")
+
+	posts, err := storage.List(context.Background(), entity.PostsFilter{AuthorID: authors[1].ID})
+	require.NoError(t, err, "failed to list posts")
+	require.Len(t, posts, 1, "len is not equal")
+	require.Equal(t, both.ID, posts[0].ID, "IDs are not equal")
+	require.Len(t, posts[0].Authors, 2, "bylines are not preloaded")
+
+	// replacing bylines changes the version
+	newAuthors := byline(authors[1], authors[0])
+	updated, err := storage.Update(context.Background(), one.ID, entity.PostUpdate{Authors: &newAuthors})
+	require.NoError(t, err, "failed to update post")
+	require.Equal(t, one.Version+1, updated.Version, "version is not incremented")
+	require.Equal(t, []string{"john", "jane"}, []string{updated.Authors[0].Author.Name, updated.Authors[1].Author.Name}, "bylines are not replaced")
+
+	noAuthors := []entity.PostAuthor{}
+	updated, err = storage.Update(context.Background(), one.ID, entity.PostUpdate{Authors: &noAuthors})
+	require.NoError(t, err, "failed to update post")
+	require.Empty(t, updated.Authors, "bylines are not removed")
+}
+
+func TestPostStorage_Slugs(t *testing.T) {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM posts;").Error
+		require.NoError(t, err, "failed to clear posts table")
+	})
+
+	post, err := storage.Create(context.Background(), &entity.Post{Title: "first title", Content: "content", Slug: "first-title"})
+	require.NoError(t, err, "failed to create post")
+
+	_, err = storage.Create(context.Background(), &entity.Post{Title: "first title", Content: "content", Slug: "first-title"})
+	require.ErrorIs(t, err, service.ErrPostSlugExists, "unexpected error")
+
+	// the slug isn't changed if the title is the same
+	slug := "ignored"
+	updated, err := storage.Update(context.Background(), post.ID, entity.PostUpdate{Title: &post.Title, Slug: &slug})
+	require.NoError(t, err, "failed to update post")
+	require.Equal(t, "first-title", updated.Slug, "slug is changed")
+
+	title := "second title"
+	slug = "second-title"
+	updated, err = storage.Update(context.Background(), post.ID, entity.PostUpdate{Title: &title, Slug: &slug})
+	require.NoError(t, err, "failed to update post")
+	require.Equal(t, "second-title", updated.Slug, "slug is not changed")
+
+	// previous slugs still lead to the post
+	got, err := storage.GetBySlug(context.Background(), "first-title")
+	require.NoError(t, err, "failed to get post by slug")
+	require.Equal(t, post.ID, got.ID, "IDs are not equal")
+	require.Equal(t, "second-title", got.Slug, "slug is not current")
+
+	// a previous slug of the post can be taken back
+	slug = "first-title"
+	updated, err = storage.Update(context.Background(), post.ID, entity.PostUpdate{Title: &post.Title, Slug: &slug})
+	require.NoError(t, err, "failed to update post")
+	require.Equal(t, "first-title", updated.Slug, "slug is not changed")
+
+	other, err := storage.Create(context.Background(), &entity.Post{Title: "other", Content: "content", Slug: "other"})
+	require.NoError(t, err, "failed to create post")
+	slug = "second-title"
+	_, err = storage.Update(context.Background(), other.ID, entity.PostUpdate{Title: &title, Slug: &slug})
+	require.ErrorIs(t, err, service.ErrPostSlugExists, "unexpected error")
+
+	_, err = storage.GetBySlug(context.Background(), "unknown")
+	require.ErrorIs(t, err, service.ErrGetPostNotFound, "unexpected error")
+}
+
+func TestPostStorage_SetSlug(t *testing.T) {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM posts;").Error
+		require.NoError(t, err, "failed to clear posts table")
+	})
+
+	// posts created before slugs have empty ones
+	legacy, err := storage.Create(context.Background(), &entity.Post{Title: "legacy", Content: "content"})
+	require.NoError(t, err, "failed to create post")
+	trashed, err := storage.Create(context.Background(), &entity.Post{Title: "trashed", Content: "content"})
+	require.NoError(t, err, "failed to create post")
+	err = storage.Delete(context.Background(), trashed.ID, 0)
+	require.NoError(t, err, "failed to delete post")
+	_, err = storage.Create(context.Background(), &entity.Post{Title: "taken", Content: "content", Slug: "taken"})
+	require.NoError(t, err, "failed to create post")
+
+	posts, err := storage.ListWithoutSlug(context.Background(), 10)
+	require.NoError(t, err, "failed to list posts without slug")
+	require.Equal(t, []string{legacy.ID, trashed.ID}, []string{posts[0].ID, posts[1].ID}, "unexpected posts")
+
+	err = storage.SetSlug(context.Background(), legacy.ID, "taken")
+	require.ErrorIs(t, err, service.ErrPostSlugExists, "unexpected error")
+
+	err = storage.SetSlug(context.Background(), legacy.ID, "legacy")
+	require.NoError(t, err, "failed to set slug")
+	err = storage.SetSlug(context.Background(), trashed.ID, "trashed")
+	require.NoError(t, err, "failed to set slug of trashed post")
+
+	actual, err := storage.GetBySlug(context.Background(), "legacy")
+	require.NoError(t, err, "failed to get post by slug")
+	require.Equal(t, legacy.ID, actual.ID, "IDs are not equal")
+	require.Equal(t, "legacy", actual.Slug, "slugs are not equal")
+	require.Equal(t, legacy.Version, actual.Version, "version is changed")
+
+	posts, err = storage.ListWithoutSlug(context.Background(), 10)
+	require.NoError(t, err, "failed to list posts without slug")
+	require.Empty(t, posts, "posts without slug are left")
+}
+
+func TestPostStorage_SetCommentsPremoderation(t *testing.T) {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM posts;").Error
+		require.NoError(t, err, "failed to clear posts table")
+	})
+
+	post, err := storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", Slug: "title"})
+	require.NoError(t, err, "failed to create post")
+
+	premoderation := true
+	err = storage.SetCommentsPremoderation(context.Background(), post.ID, &premoderation)
+	require.NoError(t, err, "failed to set comments premoderation")
+
+	actual, err := storage.Get(context.Background(), post.ID)
+	require.NoError(t, err, "failed to get post")
+	require.Equal(t, &premoderation, actual.CommentsPremoderation, "policies are not equal")
+	require.Equal(t, post.Version, actual.Version, "version is changed")
+
+	err = storage.SetCommentsPremoderation(context.Background(), post.ID, nil)
+	require.NoError(t, err, "failed to reset comments premoderation")
+
+	actual, err = storage.Get(context.Background(), post.ID)
+	require.NoError(t, err, "failed to get post")
+	require.Nil(t, actual.CommentsPremoderation, "policy is not reset")
+
+	err = storage.SetCommentsPremoderation(context.Background(), "00000000-0000-0000-0000-000000000000", &premoderation)
+	require.ErrorIs(t, err, service.ErrGetPostNotFound, "unexpected error")
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.RefreshTokenStorage = (*refreshTokenStorage)(nil)
+
+type refreshTokenStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewRefreshTokenStorage(db *gorm.DB, logger logging.Logger) *refreshTokenStorage {
+	return &refreshTokenStorage{db, logger.Named("refreshTokenStorage")}
+}
+
+func (s *refreshTokenStorage) Create(ctx context.Context, token *entity.RefreshToken) (*entity.RefreshToken, error) {
+	logger := s.logger.Named("Create")
+
+	err := s.db.Create(token).Error
+	if err != nil {
+		logger.Error("failed to create refresh token", "err", err)
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	logger.Info("successfully created refresh token", "tokenID", token.ID)
+	return token, nil
+}
+
+func (s *refreshTokenStorage) Use(ctx context.Context, id string, usedAt time.Time) (*entity.RefreshToken, error) {
+	logger := s.logger.Named("Use")
+
+	// the condition makes concurrent refreshes with the same token succeed only once
+	var token entity.RefreshToken
+	result := s.db.
+		Model(&token).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		logger.Error("failed to use refresh token", "err", result.Error)
+		return nil, fmt.Errorf("failed to use refresh token: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("successfully used refresh token", "tokenID", id)
+		return &token, nil
+	}
+
+	err := s.db.
+		Where(entity.RefreshToken{ID: id}).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("refresh token not found", "id", id)
+		return nil, service.ErrUseRefreshTokenNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get refresh token", "err", err)
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	logger.Info("refresh token is already used", "id", id)
+	return nil, service.ErrUseRefreshTokenUsed
+}
+
+func (s *refreshTokenStorage) RevokeFamily(ctx context.Context, id string, revokedAt time.Time) error {
+	logger := s.logger.Named("RevokeFamily")
+
+	result := s.db.
+		Model(&entity.RefreshToken{}).
+		Where("family_id = (SELECT family_id FROM refresh_tokens WHERE id = ?) AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		logger.Error("failed to revoke refresh token family", "err", result.Error)
+		return fmt.Errorf("failed to revoke refresh token family: %w", result.Error)
+	}
+
+	logger.Info("successfully revoked refresh token family", "revoked", result.RowsAffected)
+	return nil
+}
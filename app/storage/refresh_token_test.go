@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRefreshToken(t *testing.T, userID, familyID string) *entity.RefreshToken {
+	token, err := refreshTokensStorage.Create(context.Background(), &entity.RefreshToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err, "failed to create refresh token")
+
+	return token
+}
+
+func TestRefreshTokenStorage_Use(t *testing.T) {
+	user := createUser(t, "jane@example.com")
+	token := createRefreshToken(t, user.ID, uuid.NewString())
+
+	used, err := refreshTokensStorage.Use(context.Background(), token.ID, time.Now())
+	require.NoError(t, err, "failed to use refresh token")
+	require.Equal(t, token.FamilyID, used.FamilyID, "families are not equal")
+	require.NotNil(t, used.UsedAt, "use time is not set")
+
+	_, err = refreshTokensStorage.Use(context.Background(), token.ID, time.Now())
+	require.ErrorIs(t, err, service.ErrUseRefreshTokenUsed, "token is used twice")
+
+	_, err = refreshTokensStorage.Use(context.Background(), uuid.NewString(), time.Now())
+	require.ErrorIs(t, err, service.ErrUseRefreshTokenNotFound, "unexpected error")
+}
+
+func TestRefreshTokenStorage_RevokeFamily(t *testing.T) {
+	user := createUser(t, "jane@example.com")
+	familyID := uuid.NewString()
+	first := createRefreshToken(t, user.ID, familyID)
+	second := createRefreshToken(t, user.ID, familyID)
+	other := createRefreshToken(t, user.ID, uuid.NewString())
+
+	_, err := refreshTokensStorage.Use(context.Background(), first.ID, time.Now())
+	require.NoError(t, err, "failed to use refresh token")
+
+	// the reused first token revokes the second one issued for it
+	err = refreshTokensStorage.RevokeFamily(context.Background(), first.ID, time.Now())
+	require.NoError(t, err, "failed to revoke refresh token family")
+
+	_, err = refreshTokensStorage.Use(context.Background(), second.ID, time.Now())
+	require.ErrorIs(t, err, service.ErrUseRefreshTokenUsed, "revoked token is used")
+
+	_, err = refreshTokensStorage.Use(context.Background(), other.ID, time.Now())
+	require.NoError(t, err, "token of another family is revoked")
+}
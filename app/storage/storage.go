@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+
+	"gorm.io/gorm"
+)
+
+// NewStorages creates all storages on the db, storages passed by their Transaction
+// are created the same way on the transaction
+func NewStorages(db *gorm.DB, logger logging.Logger) service.Storages {
+	return service.Storages{
+		Post:            NewPostStorage(db, logger),
+		IdempotencyKey:  NewIdempotencyKeyStorage(db, logger),
+		Tag:             NewTagStorage(db, logger),
+		Category:        NewCategoryStorage(db, logger),
+		Author:          NewAuthorStorage(db, logger),
+		Comment:         NewCommentStorage(db, logger),
+		User:            NewUserStorage(db, logger),
+		RefreshToken:    NewRefreshTokenStorage(db, logger),
+		APIKey:          NewAPIKeyStorage(db, logger),
+		OIDCAuthRequest: NewOIDCAuthRequestStorage(db, logger),
+		// other storages should be here
+
+		Transaction: func(ctx context.Context, fn func(storages service.Storages) error) error {
+			return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				return fn(NewStorages(tx, logger))
+			})
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorages_Transaction(t *testing.T) {
+	testCases := []struct {
+		name         string
+		fnErr        error
+		expectedPost bool
+	}{
+		{
+			name:         "Transaction is committed",
+			expectedPost: true,
+		},
+		{
+			name:  "Transaction is rolled back",
+			fnErr: errors.New("error!"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := db.Exec("DELETE FROM posts;").Error
+				require.NoError(t, err, "failed to clear posts table")
+			})
+
+			var createdPost *entity.Post
+			err := storages.Transaction(context.Background(), func(txStorages service.Storages) error {
+				post, err := txStorages.Post.Create(context.Background(), &entity.Post{Title: "title", Content: "content"})
+				require.NoError(t, err, "failed to create post")
+				createdPost = post
+
+				// the deleted post is changed in the same transaction, so it's seen there
+				err = txStorages.Post.Delete(context.Background(), post.ID, 0)
+				require.NoError(t, err, "failed to delete post")
+
+				return tc.fnErr
+			})
+			require.ErrorIs(t, err, tc.fnErr, "unexpected error")
+
+			_, err = storage.Restore(context.Background(), createdPost.ID, 0)
+			if tc.expectedPost {
+				require.NoError(t, err, "committed post is not found")
+			} else {
+				require.ErrorIs(t, err, service.ErrGetDeletedPostNotFound, "rolled back post is found")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.TagStorage = (*tagStorage)(nil)
+
+type tagStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewTagStorage(db *gorm.DB, logger logging.Logger) *tagStorage {
+	return &tagStorage{db, logger.Named("tagStorage")}
+}
+
+func (s *tagStorage) Ensure(ctx context.Context, names []string) ([]entity.Tag, error) {
+	logger := s.logger.Named("Ensure")
+
+	newTags := make([]entity.Tag, 0, len(names))
+	for _, name := range names {
+		newTags = append(newTags, entity.Tag{Name: name})
+	}
+
+	// tags created concurrently by other requests are skipped and selected below
+	err := s.db.
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&newTags).Error
+	if err != nil {
+		logger.Error("failed to create tags", "err", err)
+		return nil, fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	var tags []entity.Tag
+	err = s.db.
+		Where("name IN ?", names).
+		Order("name").
+		Find(&tags).Error
+	if err != nil {
+		logger.Error("failed to get tags", "err", err)
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	logger.Info("successfully ensured tags", "tags", tags)
+	return tags, nil
+}
+
+func (s *tagStorage) List(ctx context.Context) ([]entity.TagUsage, error) {
+	logger := s.logger.Named("List")
+
+	// the list is public, so drafts and archived posts aren't counted
+	var tags []entity.TagUsage
+	err := s.db.Raw(`
+		SELECT tags.*, COUNT(posts.id) AS posts_count
+		FROM tags
+		LEFT JOIN post_tags ON post_tags.tag_id = tags.id
+		LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.status = ? AND posts.deleted_at IS NULL
+		GROUP BY tags.id
+		ORDER BY posts_count DESC, tags.name`,
+		entity.PostStatusPublished,
+	).Scan(&tags).Error
+	if err != nil {
+		logger.Error("failed to list tags", "err", err)
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	logger.Info("successfully listed tags", "tags", tags)
+	return tags, nil
+}
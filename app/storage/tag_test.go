@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagStorage_Ensure(t *testing.T) {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM tags;").Error
+		require.NoError(t, err, "failed to clear tags table")
+	})
+
+	created, err := tagsStorage.Ensure(context.Background(), []string{"news", "go"})
+	require.NoError(t, err, "failed to create tags")
+	require.Len(t, created, 2, "len is not equal")
+	require.Equal(t, "go", created[0].Name, "names are not equal")
+	require.Equal(t, "news", created[1].Name, "names are not equal")
+
+	// existing tags are returned as they are
+	ensured, err := tagsStorage.Ensure(context.Background(), []string{"go", "sport"})
+	require.NoError(t, err, "failed to ensure tags")
+	require.Len(t, ensured, 2, "len is not equal")
+	require.Equal(t, created[0].ID, ensured[0].ID, "existing tag is created again")
+	require.Equal(t, "sport", ensured[1].Name, "names are not equal")
+}
+
+func TestTagStorage_List(t *testing.T) {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM posts;").Error
+		require.NoError(t, err, "failed to clear posts table")
+		err = db.Exec("DELETE FROM tags;").Error
+		require.NoError(t, err, "failed to clear tags table")
+	})
+
+	tags, err := tagsStorage.Ensure(context.Background(), []string{"go", "news", "sport"})
+	require.NoError(t, err, "failed to create tags")
+
+	_, err = storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", Status: entity.PostStatusPublished, Tags: tags[:2]})
+	require.NoError(t, err, "failed to create post")
+	_, err = storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", Status: entity.PostStatusPublished, Tags: tags[:1]})
+	require.NoError(t, err, "failed to create post")
+	_, err = storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", Status: entity.PostStatusDraft, Tags: tags[1:]})
+	require.NoError(t, err, "failed to create post")
+	_, err = storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", Status: entity.PostStatusArchived, Tags: tags[1:]})
+	require.NoError(t, err, "failed to create post")
+	deletedPost, err := storage.Create(context.Background(), &entity.Post{Title: "title", Content: "content", Status: entity.PostStatusPublished, Tags: tags[1:]})
+	require.NoError(t, err, "failed to create post")
+	err = storage.Delete(context.Background(), deletedPost.ID, 0)
+	require.NoError(t, err, "failed to delete post")
+
+	actual, err := tagsStorage.List(context.Background())
+	require.NoError(t, err, "failed to list tags")
+	require.Len(t, actual, 3, "len is not equal")
+
+	expected := map[string]int64{"go": 2, "news": 1, "sport": 0}
+	for i, tag := range actual {
+		require.Equal(t, expected[tag.Name], tag.PostsCount, "posts count of %q is not equal", tag.Name)
+		if i > 0 {
+			require.LessOrEqual(t, tag.PostsCount, actual[i-1].PostsCount, "tags are not ordered by usage")
+		}
+	}
+}
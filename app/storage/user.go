@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ service.UserStorage = (*userStorage)(nil)
+
+type userStorage struct {
+	db     *gorm.DB
+	logger logging.Logger
+}
+
+func NewUserStorage(db *gorm.DB, logger logging.Logger) *userStorage {
+	return &userStorage{db, logger.Named("userStorage")}
+}
+
+func (s *userStorage) Create(ctx context.Context, user *entity.User) (*entity.User, error) {
+	logger := s.logger.Named("Create")
+
+	// the unique email index makes concurrent registrations with the same email create only one user
+	result := s.db.
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "email"}}, DoNothing: true}).
+		Create(user)
+	if result.Error != nil {
+		logger.Error("failed to create user", "err", result.Error)
+		return nil, fmt.Errorf("failed to create user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("user email already exists", "email", user.Email)
+		return nil, service.ErrCreateUserEmailExists
+	}
+
+	logger.Info("successfully created user", "userID", user.ID)
+	return user, nil
+}
+
+func (s *userStorage) Get(ctx context.Context, id string) (*entity.User, error) {
+	logger := s.logger.Named("Get")
+
+	var user entity.User
+	err := s.db.
+		Where(entity.User{ID: id}).
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("user not found", "id", id)
+		return nil, service.ErrGetUserNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get user", "err", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	logger.Info("successfully got user", "userID", user.ID)
+	return &user, nil
+}
+
+func (s *userStorage) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	logger := s.logger.Named("GetByEmail")
+
+	var user entity.User
+	err := s.db.
+		Where(entity.User{Email: email}).
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("user not found", "email", email)
+		return nil, service.ErrGetUserNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get user", "err", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	logger.Info("successfully got user", "userID", user.ID)
+	return &user, nil
+}
+
+func (s *userStorage) GetByOIDCSubject(ctx context.Context, subject string) (*entity.User, error) {
+	logger := s.logger.Named("GetByOIDCSubject")
+
+	var user entity.User
+	err := s.db.
+		Where(entity.User{OIDCSubject: &subject}).
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Info("user not found", "subject", subject)
+		return nil, service.ErrGetUserNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get user", "err", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	logger.Info("successfully got user", "userID", user.ID)
+	return &user, nil
+}
+
+func (s *userStorage) LinkOIDCSubject(ctx context.Context, id, subject string) (*entity.User, error) {
+	logger := s.logger.Named("LinkOIDCSubject")
+
+	var user entity.User
+	result := s.db.
+		Model(&user).
+		Clauses(clause.Returning{}).
+		Where(entity.User{ID: id}).
+		Update("oidc_subject", subject)
+	if result.Error != nil {
+		logger.Error("failed to link user", "err", result.Error)
+		return nil, fmt.Errorf("failed to link user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("user not found", "id", id)
+		return nil, service.ErrGetUserNotFound
+	}
+
+	logger.Info("successfully linked user", "userID", user.ID)
+	return &user, nil
+}
+
+func (s *userStorage) List(ctx context.Context, filter entity.UsersFilter) ([]entity.User, error) {
+	logger := s.logger.Named("List")
+
+	var users []entity.User
+	err := s.db.
+		Where(entity.User{Role: filter.Role}).
+		Order("email").
+		Find(&users).Error
+	if err != nil {
+		logger.Error("failed to list users", "err", err)
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	logger.Info("successfully listed users", "count", len(users))
+	return users, nil
+}
+
+func (s *userStorage) UpdateRole(ctx context.Context, id string, role entity.UserRole) (*entity.User, error) {
+	logger := s.logger.Named("UpdateRole")
+
+	var user entity.User
+	result := s.db.
+		Model(&user).
+		Clauses(clause.Returning{}).
+		Where(entity.User{ID: id}).
+		Update("role", role)
+	if result.Error != nil {
+		logger.Error("failed to update user role", "err", result.Error)
+		return nil, fmt.Errorf("failed to update user role: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Info("user not found", "id", id)
+		return nil, service.ErrGetUserNotFound
+	}
+
+	logger.Info("successfully updated user role", "userID", user.ID, "role", user.Role)
+	return &user, nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"darkness8129/news-api/app/entity"
+	"darkness8129/news-api/app/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createUser(t *testing.T, email string) *entity.User {
+	t.Cleanup(func() {
+		err := db.Exec("DELETE FROM users;").Error
+		require.NoError(t, err, "failed to clear users table")
+	})
+
+	user, err := usersStorage.Create(context.Background(), &entity.User{Email: email, Name: "Jane", PasswordHash: "hash"})
+	require.NoError(t, err, "failed to create user")
+
+	return user
+}
+
+func TestUserStorage_Create(t *testing.T) {
+	user := createUser(t, "jane@example.com")
+	require.NotEmpty(t, user.ID, "ID is empty")
+
+	_, err := usersStorage.Create(context.Background(), &entity.User{Email: "jane@example.com", Name: "Jane", PasswordHash: "hash"})
+	require.ErrorIs(t, err, service.ErrCreateUserEmailExists, "unexpected error")
+}
+
+func TestUserStorage_Get(t *testing.T) {
+	user := createUser(t, "jane@example.com")
+
+	actual, err := usersStorage.Get(context.Background(), user.ID)
+	require.NoError(t, err, "failed to get user")
+	require.Equal(t, user.Email, actual.Email, "emails are not equal")
+
+	actual, err = usersStorage.GetByEmail(context.Background(), "jane@example.com")
+	require.NoError(t, err, "failed to get user by email")
+	require.Equal(t, user.ID, actual.ID, "IDs are not equal")
+
+	_, err = usersStorage.Get(context.Background(), "00000000-0000-0000-0000-000000000000")
+	require.ErrorIs(t, err, service.ErrGetUserNotFound, "unexpected error")
+
+	_, err = usersStorage.GetByEmail(context.Background(), "john@example.com")
+	require.ErrorIs(t, err, service.ErrGetUserNotFound, "unexpected error")
+}
+
+func TestUserStorage_List(t *testing.T) {
+	john := createUser(t, "john@example.com")
+	jane := createUser(t, "jane@example.com")
+	require.Equal(t, entity.UserRoleReader, jane.Role, "users aren't readers by default")
+
+	_, err := usersStorage.UpdateRole(context.Background(), john.ID, entity.UserRoleEditor)
+	require.NoError(t, err, "failed to update user role")
+
+	actual, err := usersStorage.List(context.Background(), entity.UsersFilter{})
+	require.NoError(t, err, "failed to list users")
+	require.Len(t, actual, 2, "unexpected number of users")
+	require.Equal(t, jane.ID, actual[0].ID, "users aren't ordered by email")
+
+	actual, err = usersStorage.List(context.Background(), entity.UsersFilter{Role: entity.UserRoleEditor})
+	require.NoError(t, err, "failed to list users by role")
+	require.Len(t, actual, 1, "unexpected number of editors")
+	require.Equal(t, john.ID, actual[0].ID, "IDs are not equal")
+}
+
+func TestUserStorage_UpdateRole(t *testing.T) {
+	user := createUser(t, "jane@example.com")
+
+	actual, err := usersStorage.UpdateRole(context.Background(), user.ID, entity.UserRoleAdmin)
+	require.NoError(t, err, "failed to update user role")
+	require.Equal(t, entity.UserRoleAdmin, actual.Role, "roles are not equal")
+	require.Equal(t, user.Email, actual.Email, "updated user isn't returned")
+
+	_, err = usersStorage.UpdateRole(context.Background(), "00000000-0000-0000-0000-000000000000", entity.UserRoleAdmin)
+	require.ErrorIs(t, err, service.ErrGetUserNotFound, "unexpected error")
+}
+
+func TestUserStorage_LinkOIDCSubject(t *testing.T) {
+	user := createUser(t, "jane@example.com")
+
+	_, err := usersStorage.GetByOIDCSubject(context.Background(), "jane-subject")
+	require.ErrorIs(t, err, service.ErrGetUserNotFound, "unexpected error")
+
+	actual, err := usersStorage.LinkOIDCSubject(context.Background(), user.ID, "jane-subject")
+	require.NoError(t, err, "failed to link user")
+	require.NotNil(t, actual.OIDCSubject, "user isn't linked")
+	require.Equal(t, "jane-subject", *actual.OIDCSubject, "subjects are not equal")
+
+	actual, err = usersStorage.GetByOIDCSubject(context.Background(), "jane-subject")
+	require.NoError(t, err, "failed to get user by subject")
+	require.Equal(t, user.ID, actual.ID, "IDs are not equal")
+
+	_, err = usersStorage.LinkOIDCSubject(context.Background(), "00000000-0000-0000-0000-000000000000", "john-subject")
+	require.ErrorIs(t, err, service.ErrGetUserNotFound, "unexpected error")
+}
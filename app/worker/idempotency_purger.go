@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"time"
+)
+
+// idempotencyKeyPurger periodically deletes expired idempotency keys
+type idempotencyKeyPurger struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+type IdempotencyKeyPurgerOptions struct {
+	Services service.Services
+	Interval time.Duration
+	Logger   logging.Logger
+}
+
+func NewIdempotencyKeyPurger(opt IdempotencyKeyPurgerOptions) *periodicWorker {
+	p := &idempotencyKeyPurger{
+		services: opt.Services,
+		logger:   opt.Logger.Named("idempotencyKeyPurger"),
+	}
+
+	return newPeriodicWorker("idempotency key purger", opt.Interval, p.purge)
+}
+
+func (p *idempotencyKeyPurger) purge(ctx context.Context) {
+	logger := p.logger.Named("purge")
+
+	purged, err := p.services.Idempotency.PurgeExpired(ctx)
+	if err != nil {
+		logger.Error("failed to purge expired idempotency keys", "err", err)
+		return
+	}
+
+	if purged > 0 {
+		logger.Info("purged expired idempotency keys", "count", purged)
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"time"
+)
+
+// scheduledPublisher periodically publishes posts whose scheduled time has come.
+// Several app instances can run it at the same time, each post is published once.
+type scheduledPublisher struct {
+	services service.Services
+	logger   logging.Logger
+}
+
+type ScheduledPublisherOptions struct {
+	Services service.Services
+	Interval time.Duration
+	Logger   logging.Logger
+}
+
+func NewScheduledPublisher(opt ScheduledPublisherOptions) *periodicWorker {
+	p := &scheduledPublisher{
+		services: opt.Services,
+		logger:   opt.Logger.Named("scheduledPublisher"),
+	}
+
+	return newPeriodicWorker("scheduled publisher", opt.Interval, p.publish)
+}
+
+func (p *scheduledPublisher) publish(ctx context.Context) {
+	logger := p.logger.Named("publish")
+
+	posts, err := p.services.Post.PublishScheduled(ctx)
+	if err != nil {
+		logger.Error("failed to publish scheduled posts", "err", err)
+		return
+	}
+
+	if len(posts) > 0 {
+		logger.Info("published scheduled posts", "count", len(posts))
+	}
+}
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"darkness8129/news-api/app/service"
+	"darkness8129/news-api/packages/logging"
+	"time"
+)
+
+// trashPurger periodically deletes posts which have been in the trash longer than retention
+type trashPurger struct {
+	services  service.Services
+	retention time.Duration
+	logger    logging.Logger
+}
+
+type TrashPurgerOptions struct {
+	Services  service.Services
+	Interval  time.Duration
+	Retention time.Duration
+	Logger    logging.Logger
+}
+
+func NewTrashPurger(opt TrashPurgerOptions) *periodicWorker {
+	p := &trashPurger{
+		services:  opt.Services,
+		retention: opt.Retention,
+		logger:    opt.Logger.Named("trashPurger"),
+	}
+
+	return newPeriodicWorker("trash purger", opt.Interval, p.purge)
+}
+
+func (p *trashPurger) purge(ctx context.Context) {
+	logger := p.logger.Named("purge")
+
+	purged, err := p.services.Post.PurgeTrash(ctx, p.retention)
+	if err != nil {
+		logger.Error("failed to purge trash", "err", err)
+		return
+	}
+
+	if purged > 0 {
+		logger.Info("purged trash", "count", purged)
+	}
+}
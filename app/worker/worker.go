@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"darkness8129/news-api/app/service"
+	"fmt"
+	"time"
+)
+
+// periodicWorker runs a job in the background on every tick until it's stopped
+type periodicWorker struct {
+	name     string
+	interval time.Duration
+	job      func(ctx context.Context)
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+func newPeriodicWorker(name string, interval time.Duration, job func(ctx context.Context)) *periodicWorker {
+	return &periodicWorker{
+		name:     name,
+		interval: interval,
+		job:      job,
+		doneCh:   make(chan struct{}),
+	}
+}
+
+func (w *periodicWorker) Start() {
+	// jobs act on behalf of the app, not of a user, so they pass the access policy
+	ctx, cancel := context.WithCancel(service.SystemContext(context.Background()))
+	w.cancel = cancel
+
+	go func() {
+		defer close(w.doneCh)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.job(ctx)
+			}
+		}
+	}()
+}
+
+// Stop waits for the current run to finish, but not longer than timeout
+func (w *periodicWorker) Stop(timeout time.Duration) error {
+	w.cancel()
+
+	select {
+	case <-w.doneCh:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("%s didn't stop in %s", w.name, timeout)
+	}
+}
@@ -11,6 +11,9 @@ type (
 	Config struct {
 		HTTP
 		PostgreSQL
+		Worker
+		Comments
+		Auth
 		Test
 	}
 
@@ -19,6 +22,12 @@ type (
 		WriteTimeout    time.Duration `env:"HTTP_WRITE_TIMEOUT" env-default:"5s"`
 		ReadTimeout     time.Duration `env:"HTTP_READ_TIMEOUT" env-default:"5s"`
 		ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"3s"`
+		ProblemDetails  bool          `env:"HTTP_PROBLEM_DETAILS" env-default:"false"`
+		RequireIfMatch  bool          `env:"HTTP_REQUIRE_IF_MATCH" env-default:"false"`
+		// IdempotencyKeyTTL is how long responses to requests with Idempotency-Key header are replayed
+		IdempotencyKeyTTL time.Duration `env:"HTTP_IDEMPOTENCY_KEY_TTL" env-default:"24h"`
+		// MaxBatchSize limits the number of operations in one batch request
+		MaxBatchSize int `env:"HTTP_MAX_BATCH_SIZE" env-default:"100"`
 	}
 
 	PostgreSQL struct {
@@ -29,6 +38,39 @@ type (
 		Port     string `env:"POSTGRESQL_PORT" env-default:"5432"`
 	}
 
+	Worker struct {
+		PublishInterval    time.Duration `env:"WORKER_PUBLISH_INTERVAL" env-default:"30s"`
+		TrashPurgeInterval time.Duration `env:"WORKER_TRASH_PURGE_INTERVAL" env-default:"1h"`
+		// TrashRetentionDays is how long trashed posts can be restored, the app doesn't start with less than 1
+		TrashRetentionDays          int           `env:"WORKER_TRASH_RETENTION_DAYS" env-default:"30"`
+		IdempotencyKeyPurgeInterval time.Duration `env:"WORKER_IDEMPOTENCY_KEY_PURGE_INTERVAL" env-default:"1h"`
+	}
+
+	Comments struct {
+		// Premoderation makes new comments wait for approval unless their post has its own policy
+		Premoderation bool `env:"COMMENTS_PREMODERATION" env-default:"false"`
+	}
+
+	Auth struct {
+		// JWTSecret signs access and refresh tokens, the app doesn't start without it or with one shorter than 32 bytes
+		JWTSecret       string        `env:"AUTH_JWT_SECRET"`
+		AccessTokenTTL  time.Duration `env:"AUTH_ACCESS_TOKEN_TTL" env-default:"15m"`
+		RefreshTokenTTL time.Duration `env:"AUTH_REFRESH_TOKEN_TTL" env-default:"720h"`
+		// AdminEmails are comma-separated emails which get the admin role when they first sign in through OIDC
+		AdminEmails []string `env:"AUTH_ADMIN_EMAILS" env-separator:","`
+		// OIDCIssuer enables the sign-in through the identity provider discovered from it
+		OIDCIssuer       string `env:"AUTH_OIDC_ISSUER"`
+		OIDCClientID     string `env:"AUTH_OIDC_CLIENT_ID"`
+		OIDCClientSecret string `env:"AUTH_OIDC_CLIENT_SECRET"`
+		// OIDCRedirectURL is registered at the provider, the code sent there is passed to the callback endpoint
+		OIDCRedirectURL string   `env:"AUTH_OIDC_REDIRECT_URL"`
+		OIDCScopes      []string `env:"AUTH_OIDC_SCOPES" env-separator:"," env-default:"openid,email,profile"`
+		// OIDCRolesClaim is the claim of the ID token with groups of the user
+		OIDCRolesClaim string `env:"AUTH_OIDC_ROLES_CLAIM" env-default:"groups"`
+		// OIDCRoles are comma-separated group:role pairs, e.g. news-editors:editor,news-admins:admin
+		OIDCRoles map[string]string `env:"AUTH_OIDC_ROLES" env-separator:","`
+	}
+
 	Test struct {
 		PostgreSQLUser     string `env:"TEST_POSTGRESQL_USER" env-default:"postgres"`
 		PostgreSQLPassword string `env:"TEST_POSTGRESQL_PASSWORD" env-default:"postgres"`
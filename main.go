@@ -8,6 +8,15 @@ import (
 	"os"
 )
 
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                Access token or API key with the Bearer prefix
+//
+// @securityDefinitions.apikey APIKeyAuth
+// @in                         header
+// @name                       X-API-Key
+// @description                API key of a machine client
 func main() {
 	logger, err := logging.NewZapLogger()
 	if err != nil {
@@ -20,5 +29,11 @@ func main() {
 		logger.Fatal("failed to get config", "err", err)
 	}
 
+	// "promote-admin <email>" appoints an admin instead of starting the API
+	if len(os.Args) == 3 && os.Args[1] == "promote-admin" {
+		app.PromoteAdmin(cfg, logger, os.Args[2])
+		return
+	}
+
 	app.Start(cfg, logger)
 }
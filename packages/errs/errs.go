@@ -1,20 +1,50 @@
 package errs
 
-// Err provides a unified custom error used in the system, implements Error interface
+import "errors"
+
+// Err provides a unified custom error used in the system, implements Error interface.
+// Errors are compared by code, so an error with a cause or metadata still matches the error it was made from.
 type Err struct {
-	Message string `json:"message"`
-	Code    string `json:"code"`
+	Message  string                 `json:"message"`
+	Code     string                 `json:"code"`
+	Kind     Kind                   `json:"-"`
+	Cause    error                  `json:"-"`
+	Metadata map[string]interface{} `json:"-"`
 }
 
+// Kind provides a category of the error, which lets transport layers choose a response without knowing codes
+type Kind string
+
+const (
+	KindInvalid      Kind = "invalid"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindForbidden    Kind = "forbidden"
+	KindUnauthorized Kind = "unauthorized"
+	KindRateLimited  Kind = "rate_limited"
+	// KindUnprocessable is used for well-formed requests which can't be processed, e.g. a reused idempotency key
+	KindUnprocessable Kind = "unprocessable"
+	// KindPreconditionFailed is used when a condition of the request, e.g. an expected version, doesn't hold
+	KindPreconditionFailed Kind = "precondition_failed"
+	// KindPreconditionRequired is used when a request must be conditional, but it's not
+	KindPreconditionRequired Kind = "precondition_required"
+)
+
 type Options struct {
-	Message string
-	Code    string
+	Message  string
+	Code     string
+	Kind     Kind
+	Cause    error
+	Metadata map[string]interface{}
 }
 
 func New(opt Options) error {
 	return &Err{
-		Message: opt.Message,
-		Code:    opt.Code,
+		Message:  opt.Message,
+		Code:     opt.Code,
+		Kind:     opt.Kind,
+		Cause:    opt.Cause,
+		Metadata: opt.Metadata,
 	}
 }
 
@@ -22,16 +52,78 @@ func (e *Err) Error() string {
 	return e.Message
 }
 
-func IsCustom(e error) bool {
-	_, ok := e.(*Err)
-	return ok
+func (e *Err) Unwrap() error {
+	return e.Cause
 }
 
-func Code(err error) string {
-	v, ok := err.(*Err)
+// Is reports whether the target is a custom error with the same code, errors without code match only themselves
+func (e *Err) Is(target error) bool {
+	t, ok := target.(*Err)
 	if !ok {
+		return false
+	}
+
+	return e.Code != "" && e.Code == t.Code
+}
+
+// Wrap returns a copy of the custom error with the cause, not custom errors are returned as is
+func Wrap(err error, cause error) error {
+	var e *Err
+	if !errors.As(err, &e) {
+		return err
+	}
+
+	wrapped := *e
+	wrapped.Cause = cause
+	return &wrapped
+}
+
+// WithMetadata returns a copy of the custom error with the metadata field added, not custom errors are returned as is
+func WithMetadata(err error, key string, value interface{}) error {
+	var e *Err
+	if !errors.As(err, &e) {
+		return err
+	}
+
+	withMetadata := *e
+	withMetadata.Metadata = make(map[string]interface{}, len(e.Metadata)+1)
+	for k, v := range e.Metadata {
+		withMetadata.Metadata[k] = v
+	}
+	withMetadata.Metadata[key] = value
+	return &withMetadata
+}
+
+func IsCustom(err error) bool {
+	var e *Err
+	return errors.As(err, &e)
+}
+
+func Code(err error) string {
+	var e *Err
+	if !errors.As(err, &e) {
+		return ""
+	}
+
+	return e.Code
+}
+
+// KindOf returns a kind of the custom error, empty kind is returned for not custom errors and errors without kind
+func KindOf(err error) Kind {
+	var e *Err
+	if !errors.As(err, &e) {
 		return ""
 	}
 
-	return v.Code
+	return e.Kind
+}
+
+// Metadata returns metadata of the custom error, nil is returned for not custom errors
+func Metadata(err error) map[string]interface{} {
+	var e *Err
+	if !errors.As(err, &e) {
+		return nil
+	}
+
+	return e.Metadata
 }
@@ -2,6 +2,7 @@ package errs
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -54,6 +55,19 @@ func TestErrs_IsCustom(t *testing.T) {
 			}),
 			expected: true,
 		},
+		{
+			name: "IsCustom with wrapped error",
+			inputErr: fmt.Errorf("failed: %w", New(Options{
+				Message: "msg",
+				Code:    "code",
+			})),
+			expected: true,
+		},
+		{
+			name:     "IsCustom with wrapped not custom error",
+			inputErr: fmt.Errorf("failed: %w", errors.New("not custom")),
+			expected: false,
+		},
 		{
 			name:     "IsCustom with not custom error",
 			inputErr: errors.New("not custom"),
@@ -93,6 +107,14 @@ func TestErrs_GetCode(t *testing.T) {
 			}),
 			expected: "",
 		},
+		{
+			name: "GetCode with wrapped error",
+			inputErr: fmt.Errorf("failed: %w", fmt.Errorf("failed: %w", New(Options{
+				Message: "msg",
+				Code:    "code",
+			}))),
+			expected: "code",
+		},
 		{
 			name:     "GetCode with not custom error",
 			inputErr: errors.New("not custom"),
@@ -108,3 +130,219 @@ func TestErrs_GetCode(t *testing.T) {
 		})
 	}
 }
+
+func TestErrs_KindOf(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		inputErr error
+		expected Kind
+	}{
+		{
+			name: "KindOf",
+			inputErr: New(Options{
+				Message: "msg",
+				Code:    "code",
+				Kind:    KindNotFound,
+			}),
+			expected: KindNotFound,
+		},
+		{
+			name: "KindOf with empty kind",
+			inputErr: New(Options{
+				Message: "msg",
+				Code:    "code",
+			}),
+			expected: "",
+		},
+		{
+			name: "KindOf with wrapped error",
+			inputErr: fmt.Errorf("failed: %w", New(Options{
+				Message: "msg",
+				Code:    "code",
+				Kind:    KindConflict,
+			})),
+			expected: KindConflict,
+		},
+		{
+			name:     "KindOf with not custom error",
+			inputErr: errors.New("not custom"),
+			expected: "",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			actual := KindOf(tc.inputErr)
+			require.Equal(t, tc.expected, actual, "kinds are not equal")
+		})
+	}
+}
+
+func TestErrs_Is(t *testing.T) {
+	t.Parallel()
+	target := New(Options{
+		Message: "msg",
+		Code:    "code",
+	})
+	testCases := []struct {
+		name        string
+		inputErr    error
+		inputTarget error
+		expected    bool
+	}{
+		{
+			name:        "Is",
+			inputErr:    target,
+			inputTarget: target,
+			expected:    true,
+		},
+		{
+			name: "Is with the same code",
+			inputErr: New(Options{
+				Message: "another msg",
+				Code:    "code",
+			}),
+			inputTarget: target,
+			expected:    true,
+		},
+		{
+			name: "Is with another code",
+			inputErr: New(Options{
+				Message: "msg",
+				Code:    "another code",
+			}),
+			inputTarget: target,
+			expected:    false,
+		},
+		{
+			name: "Is with empty codes",
+			inputErr: New(Options{
+				Message: "msg",
+			}),
+			inputTarget: New(Options{
+				Message: "msg",
+			}),
+			expected: false,
+		},
+		{
+			name:        "Is with wrapped error",
+			inputErr:    fmt.Errorf("failed: %w", Wrap(target, errors.New("cause"))),
+			inputTarget: target,
+			expected:    true,
+		},
+		{
+			name:        "Is with not custom error",
+			inputErr:    errors.New("not custom"),
+			inputTarget: target,
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			actual := errors.Is(tc.inputErr, tc.inputTarget)
+			require.Equal(t, tc.expected, actual, "is not equal")
+		})
+	}
+}
+
+func TestErrs_Wrap(t *testing.T) {
+	t.Parallel()
+	cause := errors.New("cause")
+	testCases := []struct {
+		name     string
+		inputErr error
+		expected error
+	}{
+		{
+			name: "Wrap",
+			inputErr: New(Options{
+				Message: "msg",
+				Code:    "code",
+			}),
+			expected: cause,
+		},
+		{
+			name: "Wrap wrapped error",
+			inputErr: fmt.Errorf("failed: %w", New(Options{
+				Message: "msg",
+				Code:    "code",
+			})),
+			expected: cause,
+		},
+		{
+			name:     "Wrap not custom error",
+			inputErr: errors.New("not custom"),
+			expected: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			actual := Wrap(tc.inputErr, cause)
+			require.Equal(t, Code(tc.inputErr), Code(actual), "codes are not equal")
+			require.Equal(t, tc.expected != nil, errors.Is(actual, cause), "cause is not in the chain")
+
+			var e *Err
+			if errors.As(tc.inputErr, &e) {
+				require.Nil(t, e.Cause, "cause is set on the original error")
+			}
+		})
+	}
+}
+
+func TestErrs_WithMetadata(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		inputErr error
+		expected map[string]interface{}
+	}{
+		{
+			name: "WithMetadata",
+			inputErr: New(Options{
+				Message: "msg",
+				Code:    "code",
+			}),
+			expected: map[string]interface{}{"id": "value"},
+		},
+		{
+			name: "WithMetadata with existing metadata",
+			inputErr: New(Options{
+				Message:  "msg",
+				Code:     "code",
+				Metadata: map[string]interface{}{"field": "title"},
+			}),
+			expected: map[string]interface{}{"field": "title", "id": "value"},
+		},
+		{
+			name: "WithMetadata with wrapped error",
+			inputErr: fmt.Errorf("failed: %w", New(Options{
+				Message: "msg",
+				Code:    "code",
+			})),
+			expected: map[string]interface{}{"id": "value"},
+		},
+		{
+			name:     "WithMetadata with not custom error",
+			inputErr: errors.New("not custom"),
+			expected: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			originalLen := len(Metadata(tc.inputErr))
+
+			actual := WithMetadata(tc.inputErr, "id", "value")
+			require.Equal(t, tc.expected, Metadata(actual), "metadata is not equal")
+			require.Equal(t, Code(tc.inputErr), Code(actual), "codes are not equal")
+			require.Len(t, Metadata(tc.inputErr), originalLen, "metadata of the original error is changed")
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Provider is an OpenID Connect identity provider signing users in with the authorization code flow and PKCE
+type Provider interface {
+	// AuthCodeURL returns the URL of the authorization endpoint the user is sent to
+	AuthCodeURL(ctx context.Context, opt AuthCodeOptions) (string, error)
+	// Exchange redeems the code at the token endpoint and returns claims of the verified ID token.
+	// It returns *Error if the provider rejects the code and ErrInvalidIDToken if the ID token can't be trusted.
+	Exchange(ctx context.Context, opt ExchangeOptions) (*Claims, error)
+}
+
+type AuthCodeOptions struct {
+	State string
+	Nonce string
+	// CodeChallenge is the S256 challenge of the code verifier
+	CodeChallenge string
+}
+
+type ExchangeOptions struct {
+	Code         string
+	CodeVerifier string
+	// Nonce must be the one of the authorization request, it binds the ID token to it
+	Nonce string
+}
+
+// Claims are claims of the ID token
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	// Raw are all claims of the ID token including provider-specific ones like groups
+	Raw map[string]interface{}
+}
+
+// Strings returns the claim which is a string or an array of strings, other claims are empty
+func (c *Claims) Strings(name string) []string {
+	switch v := c.Raw[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// ErrInvalidIDToken is returned for ID tokens with invalid signature, issuer, audience, nonce or expiration
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// Error is an OAuth 2.0 error response of the provider, e.g. invalid_grant for used or expired codes
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("oidc: %s", e.Code)
+	}
+
+	return fmt.Sprintf("oidc: %s: %s", e.Code, e.Description)
+}
+
+// NewCodeVerifier returns a random PKCE code verifier
+func NewCodeVerifier() (string, error) {
+	return RandomString(32)
+}
+
+// CodeChallenge returns the S256 challenge of the code verifier
+func CodeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// RandomString returns size random bytes encoded for URLs, it's used for states and nonces
+func RandomString(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,386 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var _ Provider = (*httpProvider)(nil)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// defaultJWKSRefreshInterval limits refetching of the keys for tokens signed with unknown keys
+	defaultJWKSRefreshInterval = time.Minute
+	// clockSkew is tolerated in expiration and issue times of ID tokens
+	clockSkew      = time.Minute
+	defaultTimeout = 10 * time.Second
+)
+
+// signingMethods are asymmetric algorithms of ID tokens, symmetric ones would make the client secret a signing key
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type Options struct {
+	// Issuer is the URL the provider is discovered from, it must be the issuer of its ID tokens
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL receives the code and the state after the user signs in
+	RedirectURL string
+	// Scopes are requested besides openid
+	Scopes []string
+	// HTTPClient defaults to a client with a 10s timeout
+	HTTPClient *http.Client
+}
+
+// discoveryDocument is the part of the provider metadata the flow needs
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type httpProvider struct {
+	opt                 Options
+	client              *http.Client
+	jwksRefreshInterval time.Duration
+
+	// the metadata is discovered on the first use, so the app starts while the provider is down
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewHTTPProvider(opt Options) *httpProvider {
+	client := opt.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	return &httpProvider{opt: opt, client: client, jwksRefreshInterval: defaultJWKSRefreshInterval}
+}
+
+func (p *httpProvider) AuthCodeURL(ctx context.Context, opt AuthCodeOptions) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover provider: %w", err)
+	}
+
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse authorization endpoint: %w", err)
+	}
+
+	scopes := []string{"openid"}
+	for _, scope := range p.opt.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.opt.ClientID)
+	query.Set("redirect_uri", p.opt.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", opt.State)
+	query.Set("nonce", opt.Nonce)
+	query.Set("code_challenge", opt.CodeChallenge)
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func (p *httpProvider) Exchange(ctx context.Context, opt ExchangeOptions) (*Claims, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover provider: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", opt.Code)
+	form.Set("redirect_uri", p.opt.RedirectURL)
+	form.Set("code_verifier", opt.CodeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// client_secret_basic requires the credentials to be form-encoded first
+	req.SetBasicAuth(url.QueryEscape(p.opt.ClientID), url.QueryEscape(p.opt.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request tokens: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr Error
+		err = json.NewDecoder(resp.Body).Decode(&oauthErr)
+		if err != nil || oauthErr.Code == "" {
+			return nil, fmt.Errorf("unexpected status of token response: %d", resp.StatusCode)
+		}
+
+		return nil, &oauthErr
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: no ID token in token response", ErrInvalidIDToken)
+	}
+
+	return p.verify(ctx, d, tokens.IDToken, opt.Nonce)
+}
+
+// idTokenClaims are standard claims of the ID token
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp"`
+	Email           string `json:"email"`
+	// EmailVerified is a boolean, but some providers send it as a string
+	EmailVerified interface{} `json:"email_verified"`
+	Name          string      `json:"name"`
+}
+
+func (p *httpProvider) verify(ctx context.Context, d *discoveryDocument, idToken, nonce string) (*Claims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.opt.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.opt.ClientID {
+		return nil, fmt.Errorf("%w: unexpected authorized party %q", ErrInvalidIDToken, claims.AuthorizedParty)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: unexpected nonce", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	// the signature is checked above, so the token is parsed again only to get claims unknown here
+	raw := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(idToken, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	return &Claims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:          claims.Name,
+		Raw:           raw,
+	}, nil
+}
+
+func (p *httpProvider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d discoveryDocument
+	err := p.getJSON(ctx, strings.TrimSuffix(p.opt.Issuer, "/")+discoveryPath, &d)
+	if err != nil {
+		return nil, err
+	}
+	// the issuer is compared as it is, so the provider can't claim to be another one
+	if d.Issuer != p.opt.Issuer {
+		return nil, fmt.Errorf("issuer of discovery document %q doesn't match %q", d.Issuer, p.opt.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document misses endpoints")
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// key returns the key of the JWKS by its ID, the keys are refetched for unknown IDs, so rotated keys are picked up
+func (p *httpProvider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.findKey(kid)
+	if ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysFetchedAt) < p.jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	err := p.getJSON(ctx, p.discovery.JWKSURI, &set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		// unsupported and encryption keys are skipped, so they don't break the whole set
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		publicKey, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = publicKey
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	key, ok = p.findKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	return key, nil
+}
+
+// findKey takes the only key for tokens without key ID
+func (p *httpProvider) findKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *httpProvider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status of %s: %d", url, resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+
+	return nil
+}
+
+// jsonWebKey is an RSA or EC public key of the JWKS
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode y: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point isn't on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
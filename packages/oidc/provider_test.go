@@ -0,0 +1,272 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"darkness8129/news-api/packages/oidc/oidctest"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID     = "news-api"
+	testClientSecret = "secret:with/special chars"
+	testRedirectURL  = "https://news.example.com/oidc/callback"
+)
+
+func newTestProvider(idp *oidctest.Server) *httpProvider {
+	return NewHTTPProvider(Options{
+		Issuer:       idp.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+}
+
+// signIn goes through the flow and returns claims of the ID token issued with the claims
+func signIn(t *testing.T, idp *oidctest.Server, p *httpProvider, claims map[string]interface{}, tamper func(opt *ExchangeOptions)) (*Claims, error) {
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err, "failed to create code verifier")
+
+	authURL, err := p.AuthCodeURL(context.Background(), AuthCodeOptions{State: "state", Nonce: "nonce", CodeChallenge: CodeChallenge(verifier)})
+	require.NoError(t, err, "failed to get authorization URL")
+
+	code, state, err := idp.Authorize(authURL, claims)
+	require.NoError(t, err, "failed to authorize")
+	require.Equal(t, "state", state, "states are not equal")
+
+	opt := ExchangeOptions{Code: code, CodeVerifier: verifier, Nonce: "nonce"}
+	if tamper != nil {
+		tamper(&opt)
+	}
+
+	return p.Exchange(context.Background(), opt)
+}
+
+func TestHTTPProvider_AuthCodeURL(t *testing.T) {
+	t.Parallel()
+
+	idp := oidctest.NewServer(testClientID, testClientSecret)
+	t.Cleanup(idp.Close)
+
+	actual, err := newTestProvider(idp).AuthCodeURL(context.Background(), AuthCodeOptions{State: "state", Nonce: "nonce", CodeChallenge: "challenge"})
+	require.NoError(t, err, "failed to get authorization URL")
+
+	u, err := url.Parse(actual)
+	require.NoError(t, err, "failed to parse authorization URL")
+	require.Equal(t, idp.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path, "unexpected endpoint")
+	require.Equal(t, url.Values{
+		"response_type":         {"code"},
+		"client_id":             {testClientID},
+		"redirect_uri":          {testRedirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {"state"},
+		"nonce":                 {"nonce"},
+		"code_challenge":        {"challenge"},
+		"code_challenge_method": {"S256"},
+	}, u.Query(), "unexpected query")
+}
+
+func TestHTTPProvider_Exchange(t *testing.T) {
+	t.Parallel()
+
+	idp := oidctest.NewServer(testClientID, testClientSecret)
+	t.Cleanup(idp.Close)
+
+	user := map[string]interface{}{"sub": "subject", "email": "user@example.com", "email_verified": true, "name": "User", "groups": []string{"staff", "editors"}}
+	with := func(claims map[string]interface{}) map[string]interface{} {
+		merged := make(map[string]interface{}, len(user)+len(claims))
+		for name, value := range user {
+			merged[name] = value
+		}
+		for name, value := range claims {
+			merged[name] = value
+		}
+		return merged
+	}
+
+	testCases := []struct {
+		name        string
+		claims      map[string]interface{}
+		tamper      func(opt *ExchangeOptions)
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name:   "Exchange",
+			claims: user,
+		},
+		{
+			name:   "Exchange with several audiences",
+			claims: with(map[string]interface{}{"aud": []string{testClientID, "other"}, "azp": testClientID}),
+		},
+		{
+			name:   "Exchange with email verified as string",
+			claims: with(map[string]interface{}{"email_verified": "true"}),
+		},
+		{
+			name:        "Exchange with another nonce",
+			claims:      user,
+			tamper:      func(opt *ExchangeOptions) { opt.Nonce = "another" },
+			expectedErr: ErrInvalidIDToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Exchange with another audience",
+			claims:      with(map[string]interface{}{"aud": "other"}),
+			expectedErr: ErrInvalidIDToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Exchange with several audiences without authorized party",
+			claims:      with(map[string]interface{}{"aud": []string{testClientID, "other"}}),
+			expectedErr: ErrInvalidIDToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Exchange with another issuer",
+			claims:      with(map[string]interface{}{"iss": "https://evil.example.com"}),
+			expectedErr: ErrInvalidIDToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Exchange with expired ID token",
+			claims:      with(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}),
+			expectedErr: ErrInvalidIDToken,
+			expectErr:   true,
+		},
+		{
+			name:        "Exchange without subject",
+			claims:      with(map[string]interface{}{"sub": ""}),
+			expectedErr: ErrInvalidIDToken,
+			expectErr:   true,
+		},
+		{
+			name:      "Exchange with another code verifier",
+			claims:    user,
+			tamper:    func(opt *ExchangeOptions) { opt.CodeVerifier = "another" },
+			expectErr: true,
+		},
+		{
+			name:      "Exchange with unknown code",
+			claims:    user,
+			tamper:    func(opt *ExchangeOptions) { opt.Code = "unknown" },
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := signIn(t, idp, newTestProvider(idp), tc.claims, tc.tamper)
+			if !tc.expectErr {
+				require.NoError(t, err, "failed to exchange code")
+				require.Equal(t, "subject", actual.Subject, "subjects are not equal")
+				require.Equal(t, "user@example.com", actual.Email, "emails are not equal")
+				require.True(t, actual.EmailVerified, "email isn't verified")
+				require.Equal(t, "User", actual.Name, "names are not equal")
+				require.Equal(t, []string{"staff", "editors"}, actual.Strings("groups"), "groups are not equal")
+			} else {
+				require.Error(t, err, "no error")
+				if tc.expectedErr != nil {
+					require.ErrorIs(t, err, tc.expectedErr, "unexpected error")
+				} else {
+					var oauthErr *Error
+					require.True(t, errors.As(err, &oauthErr), "no OAuth error")
+					require.Equal(t, "invalid_grant", oauthErr.Code, "unexpected OAuth error")
+				}
+				require.Nil(t, actual, "claims are not nil")
+			}
+		})
+	}
+}
+
+func TestHTTPProvider_Exchange_InvalidClient(t *testing.T) {
+	t.Parallel()
+
+	idp := oidctest.NewServer(testClientID, testClientSecret)
+	t.Cleanup(idp.Close)
+
+	p := newTestProvider(idp)
+	p.opt.ClientSecret = "wrong"
+
+	_, err := signIn(t, idp, p, map[string]interface{}{"sub": "subject"}, nil)
+	var oauthErr *Error
+	require.True(t, errors.As(err, &oauthErr), "no OAuth error")
+	require.Equal(t, "invalid_client", oauthErr.Code, "unexpected OAuth error")
+}
+
+func TestHTTPProvider_KeyRotation(t *testing.T) {
+	t.Parallel()
+
+	idp := oidctest.NewServer(testClientID, testClientSecret)
+	t.Cleanup(idp.Close)
+
+	p := newTestProvider(idp)
+	user := map[string]interface{}{"sub": "subject"}
+
+	_, err := signIn(t, idp, p, user, nil)
+	require.NoError(t, err, "failed to sign in")
+
+	// the keys were just fetched, so a token of an unknown key doesn't make the provider refetch them
+	idp.RotateKey()
+	_, err = signIn(t, idp, p, user, nil)
+	require.ErrorIs(t, err, ErrInvalidIDToken, "unexpected error")
+
+	p.jwksRefreshInterval = 0
+	_, err = signIn(t, idp, p, user, nil)
+	require.NoError(t, err, "failed to sign in after key rotation")
+}
+
+func TestHTTPProvider_Verify(t *testing.T) {
+	t.Parallel()
+
+	idp := oidctest.NewServer(testClientID, testClientSecret)
+	t.Cleanup(idp.Close)
+
+	p := newTestProvider(idp)
+	d, err := p.discover(context.Background())
+	require.NoError(t, err, "failed to discover provider")
+
+	claims := jwt.MapClaims{"iss": idp.URL, "aud": testClientID, "sub": "subject", "nonce": "nonce", "exp": time.Now().Add(time.Minute).Unix()}
+
+	actual, err := p.verify(context.Background(), d, idp.SignIDToken(claims), "nonce")
+	require.NoError(t, err, "failed to verify ID token")
+	require.Equal(t, "subject", actual.Subject, "subjects are not equal")
+
+	forgedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate key")
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(forgedKey)
+	require.NoError(t, err, "failed to sign ID token")
+	_, err = p.verify(context.Background(), d, forged, "nonce")
+	require.ErrorIs(t, err, ErrInvalidIDToken, "forged signature is accepted")
+
+	symmetric, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testClientSecret))
+	require.NoError(t, err, "failed to sign ID token")
+	_, err = p.verify(context.Background(), d, symmetric, "nonce")
+	require.ErrorIs(t, err, ErrInvalidIDToken, "symmetric signature is accepted")
+}
+
+func TestHTTPProvider_Discover(t *testing.T) {
+	t.Parallel()
+
+	idp := oidctest.NewServer(testClientID, testClientSecret)
+	t.Cleanup(idp.Close)
+
+	p := NewHTTPProvider(Options{Issuer: idp.URL + "/", ClientID: testClientID})
+	_, err := p.AuthCodeURL(context.Background(), AuthCodeOptions{})
+	require.Error(t, err, "issuer of another provider is accepted")
+
+	idp.Close()
+	_, err = newTestProvider(idp).AuthCodeURL(context.Background(), AuthCodeOptions{})
+	require.Error(t, err, "unreachable provider is accepted")
+}